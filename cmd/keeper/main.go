@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"expvar"
 	"go.uber.org/zap"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"password-keeper/config"
 	"password-keeper/internal/bot"
+	"password-keeper/internal/breach"
+	"password-keeper/internal/onetime"
 	"password-keeper/internal/storage"
-	"password-keeper/internal/storage/queries"
 	"password-keeper/internal/usecase"
 	"syscall"
 )
@@ -34,13 +40,82 @@ func main() {
 		log.Fatalf("logic error: %s", err)
 	}
 
-	b, err := bot.New(cfg.Token, cfg.DeletionInterval, logic, logger)
+	if cfg.Command == config.VerifyAuditCommand {
+		os.Exit(verifyAudit(logic, store))
+	}
+
+	logic.SetAccessPolicy(usecase.AccessPolicy{
+		Allow:      cfg.Access.Allow,
+		Admins:     cfg.Access.Admins,
+		InviteCode: cfg.Access.InviteCode,
+	})
+
+	logic.SetAuditRetention(cfg.AuditRetention)
+
+	if cfg.BreachDir != "" {
+		breaches, err := breach.New(cfg.BreachDir)
+		if err != nil {
+			log.Fatalf("breach check error: %s", err)
+		}
+		logic.SetBreachChecker(breaches)
+	}
+
+	// the button data key is derived from the encryption key,
+	// so all the replicas of the bot accept each other's buttons
+	callbackKey := hmac.New(sha256.New, []byte(cfg.EncryptionKey))
+	callbackKey.Write([]byte("callback"))
+
+	opts := []bot.Option{
+		bot.WithWorkers(cfg.Workers),
+		bot.WithAPIEndpoint(cfg.APIEndpoint),
+		bot.WithCallbackKey(callbackKey.Sum(nil)),
+		bot.WithRateLimit(cfg.Rate, cfg.Burst),
+	}
+	if cfg.Webhook.URL != "" {
+		opts = append(opts, bot.WithWebhook(bot.WebhookConfig{
+			URL:        cfg.Webhook.URL,
+			Listen:     cfg.Webhook.Listen,
+			Secret:     cfg.Webhook.Secret,
+			CertFile:   cfg.Webhook.TLSCert,
+			KeyFile:    cfg.Webhook.TLSKey,
+			SelfSigned: cfg.Webhook.SelfSigned,
+		}))
+	}
+	if cfg.OneTime.URL != "" {
+		opts = append(opts, bot.WithOneTimeURL(cfg.OneTime.URL))
+	}
+
+	b, err := bot.New(cfg.Token, cfg.DeletionInterval, logic, logger, opts...)
 	if err != nil {
 		log.Fatalf("bot error: %s", err)
 	}
 
+	if cfg.Metrics != "" {
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle("/debug/vars", expvar.Handler())
+			if err := http.ListenAndServe(cfg.Metrics, mux); err != nil {
+				log.Printf("metrics server error: %s", err)
+			}
+		}()
+	}
+
+	var links *onetime.Server
+	if cfg.OneTime.URL != "" {
+		links = onetime.New(cfg.OneTime.Listen, logic, b.NotifyOneTimeOpened, logger)
+		go func() {
+			if err := links.Start(); err != nil {
+				log.Fatalf("one-time links server error: %s", err)
+			}
+		}()
+	}
+
 	log.Println("Starting bot...")
-	go b.Start()
+	go func() {
+		if err := b.Start(); err != nil {
+			log.Fatalf("bot error: %s", err)
+		}
+	}()
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -49,8 +124,20 @@ func main() {
 
 	log.Println("Shutdown Server ...")
 
-	b.Stop()
-	if err := queries.Close(); err != nil {
-		log.Fatalf("queries close error: %s", err)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if links != nil {
+		if err := links.Shutdown(ctx); err != nil {
+			log.Printf("one-time links server shutdown error: %s", err)
+		}
+	}
+
+	if err := b.Shutdown(ctx); err != nil {
+		log.Printf("bot shutdown error: %s", err)
+	}
+
+	if err := store.Close(); err != nil {
+		log.Fatalf("storage close error: %s", err)
 	}
 }
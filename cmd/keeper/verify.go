@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"password-keeper/internal/storage"
+	"password-keeper/internal/usecase"
+)
+
+// verifyAudit walks the audit log chain, prints the result and returns the exit code,
+// it is 1 when a link is broken and 2 when the chain can't be read.
+func verifyAudit(logic *usecase.UseCase, store *storage.Storage) int {
+	defer store.Close()
+
+	v, err := logic.VerifyAudit()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "audit log verification error: %s\n", err)
+		return 2
+	}
+
+	if !v.Intact() {
+		fmt.Printf("audit log is broken at record %d (chat %d): %s\n", v.BrokenID, v.BrokenChat, v.Reason)
+		return 1
+	}
+
+	fmt.Printf("audit log is intact: %d records checked", v.Checked)
+	if v.Legacy > 0 {
+		fmt.Printf(", %d records added before the chain", v.Legacy)
+	}
+	fmt.Println()
+	return 0
+}
@@ -4,9 +4,13 @@ import (
 	"errors"
 	"flag"
 	"os"
+	"strings"
 	"time"
 )
 
+// VerifyAuditCommand command verifying the audit log chain instead of running the bot.
+const VerifyAuditCommand = "verify-audit"
+
 // Flag struct for parsing from env and cmd args.
 type Flag struct {
 	EncryptionKey    *string
@@ -14,6 +18,25 @@ type Flag struct {
 	DeletionInterval *time.Duration
 	Storage          *string
 	DSN              *string
+	WebhookURL       *string
+	WebhookListen    *string
+	WebhookSecret    *string
+	TLSCert          *string
+	TLSKey           *string
+	SelfSigned       *bool
+	Workers          *int
+	ShutdownTimeout  *time.Duration
+	APIEndpoint      *string
+	Rate             *float64
+	Burst            *int
+	Metrics          *string
+	Allow            *string
+	Admins           *string
+	InviteCode       *string
+	OneTimeURL       *string
+	OneTimeListen    *string
+	BreachDir        *string
+	AuditRetention   *time.Duration
 }
 
 var (
@@ -24,6 +47,9 @@ var (
 
 	// ErrEncryptionKeyNotSet error when the key is not set.
 	ErrEncryptionKeyNotSet = errors.New("encryption-key is not set")
+
+	// ErrWebhookSecretNotSet error when the webhook is used without a secret.
+	ErrWebhookSecretNotSet = errors.New("webhook-secret is not set")
 )
 
 func init() {
@@ -32,20 +58,88 @@ func init() {
 	f.DeletionInterval = flag.Duration("interval", 7*time.Second, "-interval=1s")
 	f.Storage = flag.String("storage", "sqlite", "-storage=sqlite|postgres")
 	f.DSN = flag.String("dsn", "keeper.db", "-dsn=CONNECTION_STRING")
+	f.WebhookURL = flag.String("webhook", "", "-webhook=https://example.com/bot")
+	f.WebhookListen = flag.String("listen", ":8443", "-listen=:8443")
+	f.WebhookSecret = flag.String("webhook-secret", "", "-webhook-secret=SECRET")
+	f.TLSCert = flag.String("tls-cert", "", "-tls-cert=cert.pem")
+	f.TLSKey = flag.String("tls-key", "", "-tls-key=key.pem")
+	f.SelfSigned = flag.Bool("self-signed", false, "-self-signed")
+	f.Workers = flag.Int("workers", 8, "-workers=8")
+	f.APIEndpoint = flag.String("api-endpoint", "https://api.telegram.org/bot%s/%s", "-api-endpoint=http://localhost:8081/bot%s/%s")
+	f.Rate = flag.Float64("rate", 1, "-rate=1 (commands per second per chat)")
+	f.Burst = flag.Int("burst", 10, "-burst=10")
+	f.Metrics = flag.String("metrics", "", "-metrics=:9090")
+	f.ShutdownTimeout = flag.Duration("shutdown-timeout", 10*time.Second, "-shutdown-timeout=10s")
+	f.Allow = flag.String("allow", "", "-allow=123456789,@username")
+	f.Admins = flag.String("admins", "", "-admins=123456789,@username")
+	f.InviteCode = flag.String("invite", "", "-invite=CODE")
+	f.OneTimeURL = flag.String("onetime-url", "", "-onetime-url=https://example.com")
+	f.OneTimeListen = flag.String("onetime-listen", ":8080", "-onetime-listen=:8080")
+	f.BreachDir = flag.String("hibp-dir", "", "-hibp-dir=/data/pwnedpasswords")
+	f.AuditRetention = flag.Duration("audit-retention", 90*24*time.Hour, "-audit-retention=2160h (0 keeps the audit log forever)")
 }
 
 // Config contains all the settings for configuring the application.
 type Config struct {
+	// Command is run instead of the bot when it's not empty,
+	// it needs only the storage and the encryption key.
+	Command          string
 	EncryptionKey    string
 	Token            string
 	DeletionInterval time.Duration
 	Storage          string
 	DSN              string
+	Webhook          Webhook
+	Workers          int
+	ShutdownTimeout  time.Duration
+	APIEndpoint      string
+	Rate             float64
+	Burst            int
+	Metrics          string
+	Access           Access
+	OneTime          OneTime
+	// BreachDir directory of the Pwned Passwords range files, the breach check is off when it's empty.
+	BreachDir string
+	// AuditRetention how long the audit log is kept, zero keeps it forever.
+	AuditRetention time.Duration
+}
+
+// OneTime contains settings of the one-time links server.
+// The server is not started when URL is empty.
+type OneTime struct {
+	// URL public URL of the server, e.g. behind a reverse proxy with TLS.
+	URL    string
+	Listen string
+}
+
+// Access contains settings of who may use the bot.
+// The bot is open to everyone when Allow and InviteCode are empty.
+type Access struct {
+	Allow      []string
+	Admins     []string
+	InviteCode string
+}
+
+// Webhook contains settings for receiving updates via webhook.
+// Long polling is used when URL is empty.
+type Webhook struct {
+	URL        string
+	Listen     string
+	Secret     string
+	TLSCert    string
+	TLSKey     string
+	SelfSigned bool
 }
 
 // New initializing the config for the application.
 func New() (*Config, error) {
-	flag.Parse()
+	var command string
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == VerifyAuditCommand {
+		command, args = args[0], args[1:]
+	}
+	// the command line flags exit on errors
+	_ = flag.CommandLine.Parse(args)
 
 	if key, ok := os.LookupEnv("ENCRYPTION_KEY"); ok {
 		*f.EncryptionKey = key
@@ -59,15 +153,64 @@ func New() (*Config, error) {
 		return nil, ErrEncryptionKeyNotSet
 	}
 
-	if *f.Token == "" {
+	if secret, ok := os.LookupEnv("WEBHOOK_SECRET"); ok {
+		*f.WebhookSecret = secret
+	}
+
+	if code, ok := os.LookupEnv("INVITE_CODE"); ok {
+		*f.InviteCode = code
+	}
+
+	if command == "" && *f.Token == "" {
 		return nil, ErrTokenNotSet
 	}
 
+	if command == "" && *f.WebhookURL != "" && *f.WebhookSecret == "" {
+		return nil, ErrWebhookSecretNotSet
+	}
+
 	return &Config{
+		Command:          command,
 		EncryptionKey:    *f.EncryptionKey,
 		Token:            *f.Token,
 		DeletionInterval: *f.DeletionInterval,
 		Storage:          *f.Storage,
 		DSN:              *f.DSN,
+		Webhook: Webhook{
+			URL:        *f.WebhookURL,
+			Listen:     *f.WebhookListen,
+			Secret:     *f.WebhookSecret,
+			TLSCert:    *f.TLSCert,
+			TLSKey:     *f.TLSKey,
+			SelfSigned: *f.SelfSigned,
+		},
+		Workers:         *f.Workers,
+		ShutdownTimeout: *f.ShutdownTimeout,
+		APIEndpoint:     *f.APIEndpoint,
+		Rate:            *f.Rate,
+		Burst:           *f.Burst,
+		Metrics:         *f.Metrics,
+		Access: Access{
+			Allow:      splitList(*f.Allow),
+			Admins:     splitList(*f.Admins),
+			InviteCode: *f.InviteCode,
+		},
+		OneTime: OneTime{
+			URL:    *f.OneTimeURL,
+			Listen: *f.OneTimeListen,
+		},
+		BreachDir:      *f.BreachDir,
+		AuditRetention: *f.AuditRetention,
 	}, nil
 }
+
+// splitList splits the comma separated list skipping empty items.
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
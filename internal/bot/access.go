@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"fmt"
+	"password-keeper/internal/usecase"
+	"strconv"
+	"strings"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// authorize checks that the sender of the update may use the bot.
+// Strangers may join with /start INVITE_CODE.
+func (b *Bot) authorize(update *tgapi.Update) bool {
+	from := update.SentFrom()
+	if from == nil {
+		return false
+	}
+
+	if b.logic.IsAllowed(from.ID, from.UserName) {
+		return true
+	}
+
+	if msg := update.Message; msg != nil && msg.IsCommand() && msg.Command() == start && msg.CommandArguments() != "" {
+		err := b.logic.Invite(from.ID, from.UserName, strings.TrimSpace(msg.CommandArguments()))
+		if err == nil {
+			// the start message is kept, but this one contains the invite code
+			b.hideLater(msg)
+			return true
+		}
+		b.logger.Warn(fmt.Sprintf("invite error: user %d: %v", from.ID, err))
+	}
+
+	b.deny(update, from)
+	return false
+}
+
+// deny tells the stranger the bot is private.
+func (b *Bot) deny(update *tgapi.Update, from *tgapi.User) {
+	text := fmt.Sprintf(localize(accessDeniedErr, usecase.MatchLang(from.LanguageCode)), from.ID)
+
+	if query := update.CallbackQuery; query != nil {
+		b.answer(query, text)
+		return
+	}
+
+	msg := update.Message
+	if msg == nil || !msg.Chat.IsPrivate() {
+		return
+	}
+
+	if allowed, warn := b.limiter.allow(msg.Chat.ID); !allowed && !warn {
+		return
+	}
+
+	if _, err := b.client.Send(tgapi.NewMessage(msg.Chat.ID, text)); err != nil {
+		b.logger.Warn(fmt.Sprintf("send error: %v", err))
+	}
+}
+
+// handleAdmin handles admin command.
+func (b *Bot) handleAdmin(req *Request) error {
+	var text string
+	var err error
+
+	switch req.Args[0] {
+	case "users":
+		text, err = b.adminUsers(req.ChatID())
+	case "stats":
+		text, err = b.adminStats(req.ChatID())
+	case "revoke", "allow":
+		if len(req.Args) != 2 {
+			text = b.handleMessageLang(wrongInputErr, req.ChatID())
+			err = ErrWrongInput
+			break
+		}
+
+		userID, parseErr := strconv.ParseInt(req.Args[1], 10, 64)
+		if parseErr != nil {
+			text = b.handleMessageLang(wrongInputErr, req.ChatID())
+			err = ErrWrongInput
+			break
+		}
+
+		err = b.logic.SetRevoked(userID, req.Args[0] == "revoke")
+		if err != nil {
+			text = b.handleMessageLang(adminErr, req.ChatID())
+		} else {
+			text = fmt.Sprintf(b.handleMessageLang(adminDone, req.ChatID()), userID)
+		}
+	default:
+		text = b.handleMessageLang(adminUsage, req.ChatID())
+	}
+
+	b.reply(req, tgapi.NewMessage(req.ChatID(), text))
+	return err
+}
+
+func (b *Bot) adminUsers(chatID int64) (string, error) {
+	members, err := b.logic.Members()
+	if err != nil {
+		return b.handleMessageLang(adminErr, chatID), err
+	}
+
+	if len(members) == 0 {
+		return b.handleMessageLang(adminNoUsers, chatID), nil
+	}
+
+	var sb strings.Builder
+	for _, m := range members {
+		fmt.Fprintf(&sb, "%d", m.UserID)
+		if m.Username != "" {
+			fmt.Fprintf(&sb, " @%s", m.Username)
+		}
+		fmt.Fprintf(&sb, " %s", m.Role)
+		if m.Revoked {
+			sb.WriteString(" ⛔️")
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), nil
+}
+
+func (b *Bot) adminStats(chatID int64) (string, error) {
+	stats, err := b.logic.Stats()
+	if err != nil {
+		return b.handleMessageLang(adminErr, chatID), err
+	}
+
+	return fmt.Sprintf(b.handleMessageLang(adminStats, chatID),
+		stats.Members, stats.Chats, stats.Services,
+		throttledRequests.Value(), lockedRequests.Value(),
+	), nil
+}
@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"fmt"
+	"password-keeper/internal/entity"
+	"strings"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// activityTimeFormat format of the time of the audit log records.
+const activityTimeFormat = "2006-01-02 15:04 UTC"
+
+// actionLabels the messages of the audit log actions.
+var actionLabels = map[string]string{
+	entity.ActionGet:        actionGet,
+	entity.ActionSave:       actionSave,
+	entity.ActionDelete:     actionDelete,
+	entity.ActionLang:       actionLang,
+	entity.ActionWipe:       actionWipe,
+	entity.ActionExpire:     actionExpire,
+	entity.ActionFileSave:   actionFileSave,
+	entity.ActionFileGet:    actionFileGet,
+	entity.ActionFileDelete: actionFileDelete,
+	entity.ActionExport:     actionExport,
+}
+
+// outcomeLabels the messages of the audit log outcomes.
+var outcomeLabels = map[string]string{
+	entity.OutcomeOK:       outcomeOK,
+	entity.OutcomeNotFound: outcomeNotFound,
+	entity.OutcomeLocked:   outcomeLocked,
+	entity.OutcomeDenied:   outcomeDenied,
+	entity.OutcomeError:    outcomeError,
+}
+
+// handleLog handles log command, it shows the recent activity of the chat.
+func (b *Bot) handleLog(req *Request) error {
+	chatID := req.ChatID()
+	activity, err := b.logic.RecentActivity(chatID)
+	if err != nil {
+		b.replyText(req, internalErr)
+		return fmt.Errorf("log error: %w", err)
+	}
+
+	if len(activity) == 0 {
+		b.replyText(req, logEmpty)
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(b.handleMessageLang(logTitle, chatID))
+	for _, a := range activity {
+		action := a.Action
+		if label, ok := actionLabels[a.Action]; ok {
+			action = b.handleMessageLang(label, chatID)
+		}
+		if a.Service != "" {
+			action += " " + a.Service
+		}
+
+		result := a.Outcome
+		if label, ok := outcomeLabels[a.Outcome]; ok {
+			result = b.handleMessageLang(label, chatID)
+		}
+
+		fmt.Fprintf(&sb, "\n%s %s — %s", a.At.UTC().Format(activityTimeFormat), action, result)
+	}
+
+	msgConfig := tgapi.NewMessage(chatID, sb.String())
+	msgConfig.ReplyMarkup = b.hideKeyboard(chatID, req.Message.MessageID)
+	b.reply(req, msgConfig)
+	return nil
+}
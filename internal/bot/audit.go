@@ -0,0 +1,133 @@
+package bot
+
+import (
+	"fmt"
+	"password-keeper/internal/strength"
+	"password-keeper/internal/usecase"
+	"strings"
+	"unicode/utf8"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxMessageLen is less than the Telegram limit of 4096 characters, which are counted in UTF-16.
+const maxMessageLen = 3500
+
+// scoreLabels the messages of the strength scores.
+var scoreLabels = [strength.MaxScore + 1]string{scoreVeryWeak, scoreWeak, scoreFair, scoreGood, scoreStrong}
+
+// patternLabels the messages of the strength warnings.
+var patternLabels = map[string]string{
+	strength.Common:   patternCommon,
+	strength.Word:     patternWord,
+	strength.Keyboard: patternKeyboard,
+	strength.Sequence: patternSequence,
+	strength.Repeat:   patternRepeat,
+	strength.Year:     patternYear,
+}
+
+// handleAudit handles audit command.
+func (b *Bot) handleAudit(req *Request) error {
+	chatID := req.ChatID()
+	report, err := b.logic.Audit(chatID)
+	if err != nil {
+		b.replyText(req, internalErr)
+		return fmt.Errorf("audit error: %w", err)
+	}
+
+	if len(report.Entries) == 0 && report.Unnamed == 0 {
+		b.replyText(req, auditEmpty)
+		return nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, b.handleMessageLang(auditSummary, chatID),
+		len(report.Entries), report.Weak, report.Reused, report.Old, report.NoLogin)
+	if report.Unnamed > 0 {
+		fmt.Fprintf(&sb, b.handleMessageLang(auditUnnamed, chatID), report.Unnamed)
+	}
+	sb.WriteString("\n")
+
+	for _, e := range report.Entries {
+		sb.WriteString("\n" + b.auditLine(chatID, e))
+	}
+
+	parts := splitMessage(sb.String())
+	for i, part := range parts {
+		msgConfig := tgapi.NewMessage(chatID, part)
+		if i == len(parts)-1 {
+			msgConfig.ReplyMarkup = b.hideKeyboard(chatID, req.Message.MessageID)
+		}
+		b.reply(req, msgConfig)
+	}
+	return nil
+}
+
+// auditLine returns the line of the report about the entry.
+func (b *Bot) auditLine(chatID int64, e usecase.AuditEntry) string {
+	mark := "✅"
+	if !e.Healthy() {
+		mark = "⚠️"
+	}
+
+	var issues []string
+	if label, ok := patternLabels[e.Warning]; ok && e.Weak {
+		issues = append(issues, b.handleMessageLang(label, chatID))
+	}
+	if len(e.ReusedWith) > 0 {
+		issues = append(issues, fmt.Sprintf(b.handleMessageLang(auditReused, chatID), strings.Join(e.ReusedWith, ", ")))
+	}
+	if e.Old {
+		issues = append(issues, b.handleMessageLang(auditOld, chatID))
+	}
+	if e.NoLogin {
+		issues = append(issues, b.handleMessageLang(auditNoLogin, chatID))
+	}
+
+	line := fmt.Sprintf("%s %s — %s", mark, e.Service, b.scoreText(chatID, e.Score))
+	if len(issues) > 0 {
+		line += ": " + strings.Join(issues, "; ")
+	}
+	return line
+}
+
+// scoreText returns the label of the score like "weak (1/4)".
+func (b *Bot) scoreText(chatID int64, score int) string {
+	return fmt.Sprintf("%s (%d/%d)", b.handleMessageLang(scoreLabels[score], chatID), score, strength.MaxScore)
+}
+
+// strengthNote returns the strength of the password just saved and the reuse warning.
+// The note is optional, so its errors are only logged.
+func (b *Bot) strengthNote(chatID int64, service, password string) string {
+	r, reused, err := b.logic.Assess(chatID, service, password)
+	if err != nil {
+		b.logger.Warn(fmt.Sprintf("assess error: chat %d: %v", chatID, err))
+		return ""
+	}
+
+	note := fmt.Sprintf(b.handleMessageLang(strengthResult, chatID), b.scoreText(chatID, r.Score))
+	if label, ok := patternLabels[r.Warning]; ok && r.Score <= usecase.WeakScore {
+		note += ": " + b.handleMessageLang(label, chatID)
+	}
+	if len(reused) > 0 {
+		note += "\n" + fmt.Sprintf(b.handleMessageLang(reuseWarning, chatID), strings.Join(reused, ", "))
+	}
+	return note
+}
+
+// splitMessage splits the text by lines into parts not longer than maxMessageLen.
+func splitMessage(text string) []string {
+	var parts []string
+	var part strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		if part.Len() > 0 && utf8.RuneCountInString(part.String())+utf8.RuneCountInString(line)+1 > maxMessageLen {
+			parts = append(parts, part.String())
+			part.Reset()
+		}
+		if part.Len() > 0 {
+			part.WriteString("\n")
+		}
+		part.WriteString(line)
+	}
+	return append(parts, part.String())
+}
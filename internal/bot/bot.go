@@ -1,9 +1,14 @@
 package bot
 
 import (
+	"context"
 	"fmt"
 	"go.uber.org/zap"
+	"net/http"
+	"password-keeper/internal/bot/callback"
 	"password-keeper/internal/usecase"
+	"strings"
+	"sync"
 	"time"
 
 	// telegram SDK
@@ -16,68 +21,286 @@ type Bot struct {
 	logic  *usecase.UseCase
 	logger *zap.Logger
 
-	*tgapi.BotAPI
+	// api receives updates, client is used by the handlers and the watcher.
+	api    *tgapi.BotAPI
+	client Client
 
-	stopHiding   func()
-	toHide       chan MessageInfo
+	stopHiding func(ctx context.Context) error
+	toHide     chan MessageInfo
+	// hideChat deletes the queued messages of the chat right away.
+	hideChat     func(chatID int64)
 	hideInterval int64
+
+	quit chan struct{}
+	done chan struct{}
+
+	shutdownOnce sync.Once
+	shutdownErr  error
+
+	webhook  *WebhookConfig
+	serverMu sync.Mutex
+	server   *http.Server
+
+	workers     int
+	apiEndpoint string
+
+	router  *router
+	limiter *limiter
+
+	callbackKey []byte
+	callbacks   *callback.Codec
+
+	rate  float64
+	burst int
+
+	// conflicts of imports waiting for the choice of the user.
+	conflicts *conflicts
+	// wipes chats which pressed the button and may confirm the wipe with the phrase.
+	wipes *confirmations
+	// reveals masked entries waiting for the reveal buttons.
+	reveals *reveals
+	// revealFor how long a password revealed with a button stays visible.
+	revealFor time.Duration
+
+	// oneTimeURL public URL of the one-time links server, /onetime is disabled when it's empty.
+	oneTimeURL string
 }
 
-type messages struct {
-	Russian string
-	English string
+// Client is the part of the Telegram Bot API used by the handlers.
+type Client interface {
+	Send(c tgapi.Chattable) (tgapi.Message, error)
+	Request(c tgapi.Chattable) (*tgapi.APIResponse, error)
 }
 
-type keyboards struct {
-	Russian tgapi.InlineKeyboardMarkup
-	English tgapi.InlineKeyboardMarkup
+// Option configures the bot.
+type Option func(*Bot)
+
+// WithWebhook makes the bot receive updates via webhook instead of long polling.
+func WithWebhook(cfg WebhookConfig) Option {
+	return func(b *Bot) {
+		b.webhook = &cfg
+	}
 }
 
-// New creates a new bot.
-func New(token string, deletionInterval time.Duration, logic *usecase.UseCase, logger *zap.Logger) (*Bot, error) {
-	bot, err := tgapi.NewBotAPI(token)
-	if err != nil {
-		return nil, fmt.Errorf("error creating bot: %w", err)
+// WithWorkers sets the number of updates handled concurrently.
+func WithWorkers(n int) Option {
+	return func(b *Bot) {
+		b.workers = n
+	}
+}
+
+// WithAPIEndpoint sets the Bot API endpoint, e.g. of a local Bot API server.
+// The endpoint is a format string with the token and the method, see tgapi.APIEndpoint.
+func WithAPIEndpoint(endpoint string) Option {
+	return func(b *Bot) {
+		b.apiEndpoint = endpoint
 	}
+}
+
+// WithRateLimit allows each chat burst commands at once and rate commands per second after that.
+func WithRateLimit(rate float64, burst int) Option {
+	return func(b *Bot) {
+		b.rate = rate
+		b.burst = burst
+	}
+}
 
-	return &Bot{
+// WithCallbackKey sets the key signing the button data.
+// Replicas of the bot must share the key, otherwise a random one is used.
+func WithCallbackKey(key []byte) Option {
+	return func(b *Bot) {
+		b.callbackKey = key
+	}
+}
+
+// WithOneTimeURL enables /onetime with links to the server at the public URL.
+func WithOneTimeURL(url string) Option {
+	return func(b *Bot) {
+		b.oneTimeURL = strings.TrimSuffix(url, "/")
+	}
+}
+
+// New creates a new bot.
+func New(token string, deletionInterval time.Duration, logic *usecase.UseCase, logger *zap.Logger, opts ...Option) (*Bot, error) {
+	b := &Bot{
 		token:        token,
 		logic:        logic,
-		BotAPI:       bot,
 		logger:       logger,
 		hideInterval: int64(deletionInterval.Seconds()),
-	}, nil
+		quit:         make(chan struct{}),
+		done:         make(chan struct{}),
+		workers:      defaultWorkers,
+		apiEndpoint:  tgapi.APIEndpoint,
+		rate:         commandsPerSecond,
+		burst:        commandsBurst,
+		conflicts:    newConflicts(),
+		wipes:        newConfirmations(),
+		reveals:      newReveals(),
+		revealFor:    revealShowFor,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	callbacks, err := callback.NewCodec(b.callbackKey)
+	if err != nil {
+		return nil, fmt.Errorf("error creating callback codec: %w", err)
+	}
+	b.callbacks = callbacks
+
+	bot, err := tgapi.NewBotAPIWithClient(token, b.apiEndpoint, &http.Client{})
+	if err != nil {
+		return nil, fmt.Errorf("error creating bot: %w", err)
+	}
+	b.api = bot
+	b.client = bot
+
+	b.toHide, b.stopHiding, b.hideChat = b.Watch()
+
+	b.limiter = newLimiter(b.rate, b.burst)
+	b.router = newRouter()
+	// autoDelete goes outside recovery, so the messages are deleted after panics too
+	b.router.use(b.logging, b.autoDelete, b.recovery, b.accessControl, b.rateLimit, b.validateArgs)
+	b.router.register(b.commands()...)
+
+	return b, nil
 }
 
-// Start starts the bot.
-func (b *Bot) Start() {
-	u := tgapi.NewUpdate(0)
-	u.Timeout = 60
-	b.toHide, b.stopHiding = b.Watch()
+// Start starts the bot and blocks until Shutdown is called.
+func (b *Bot) Start() error {
+	defer close(b.done)
 
-	updates := b.GetUpdatesChan(u)
-	for update := range updates {
-		if update.CallbackQuery != nil {
-			b.handleCallbackQuery(update.CallbackQuery)
-			continue
-		}
+	updates, err := b.updates()
+	if err != nil {
+		return err
+	}
+
+	b.publishCommands()
 
-		if update.Message == nil {
-			continue
+	var jobs sync.WaitGroup
+	for _, job := range []func(){b.schedule, b.sweepExpired} {
+		jobs.Add(1)
+		go func(job func()) {
+			defer jobs.Done()
+			job()
+		}(job)
+	}
+	defer jobs.Wait()
+
+	p := newPool(b.workers, b.handleUpdate, b.logger)
+	defer p.stop()
+
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			p.push(update)
+		case <-b.quit:
+			drain(updates, p)
+			return nil
 		}
+	}
+}
 
-		if update.Message.IsCommand() {
-			b.handleCommand(update.Message)
-			continue
+// drain passes the updates buffered in the channel to the pool. Shutdown stops the intake
+// before quit, so the updates received by then are handled before the pool stops.
+func drain(updates tgapi.UpdatesChannel, p *pool) {
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			p.push(update)
+		default:
+			return
 		}
+	}
+}
+
+// handleUpdate passes the update to the matching handler.
+func (b *Bot) handleUpdate(update tgapi.Update) {
+	if !b.authorize(&update) {
+		return
+	}
+
+	if from := update.SentFrom(); from != nil {
+		b.logic.SeeUser(from.ID, from.UserName)
+	}
+
+	b.detectLang(&update)
 
-		b.handleMessage()
+	if update.CallbackQuery != nil {
+		b.handleCallbackQuery(update.CallbackQuery)
+		return
 	}
 
+	if update.Message == nil {
+		return
+	}
+
+	documentCommand(update.Message)
+	if update.Message.IsCommand() {
+		b.router.handle(update.Message)
+		return
+	}
+
+	b.handleMessage(update.Message)
+}
+
+// updates returns the channel with updates from the webhook or long polling.
+func (b *Bot) updates() (tgapi.UpdatesChannel, error) {
+	if b.webhook != nil {
+		return b.listenWebhook()
+	}
+
+	u := tgapi.NewUpdate(0)
+	u.Timeout = 60
+
+	return b.api.GetUpdatesChan(u), nil
+}
+
+// detectLang detects the chat language from Telegram user settings.
+func (b *Bot) detectLang(update *tgapi.Update) {
+	from, chat := update.SentFrom(), update.FromChat()
+	if from == nil || chat == nil {
+		return
+	}
+
+	b.logic.DetectLang(chat.ID, from.LanguageCode)
 }
 
-// Stop stops the bot.
-func (b *Bot) Stop() {
-	b.StopReceivingUpdates()
-	b.stopHiding()
+// Shutdown stops receiving updates, waits for the handlers of the received ones
+// and deletes all the messages queued for deletion.
+// It returns an error if ctx is done before that.
+// The bot is stopped once, later calls return the result of the first one.
+func (b *Bot) Shutdown(ctx context.Context) error {
+	b.shutdownOnce.Do(func() {
+		b.shutdownErr = b.shutdown(ctx)
+	})
+	return b.shutdownErr
+}
+
+func (b *Bot) shutdown(ctx context.Context) error {
+	if b.webhook != nil {
+		b.stopWebhook(ctx)
+	} else {
+		b.api.StopReceivingUpdates()
+	}
+	close(b.quit)
+
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		return fmt.Errorf("wait for handlers: %w", ctx.Err())
+	}
+
+	if err := b.stopHiding(ctx); err != nil {
+		return fmt.Errorf("flush deletion queue: %w", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,47 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"password-keeper/internal/usecase"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleCheck handles check command: /check password.
+func (b *Bot) handleCheck(req *Request) error {
+	n, err := b.logic.CheckBreach(req.Args[0])
+	if err != nil {
+		if errors.Is(err, usecase.ErrBreachCheckOff) {
+			b.replyText(req, breachCheckOffErr)
+			return nil
+		}
+		b.replyText(req, internalErr)
+		return fmt.Errorf("check error: %w", err)
+	}
+
+	if n == 0 {
+		b.replyText(req, breachNotFound)
+		return nil
+	}
+
+	b.reply(req, tgapi.NewMessage(req.ChatID(), fmt.Sprintf(b.handleMessageLang(breachFound, req.ChatID()), n)))
+	return nil
+}
+
+// breachWarning returns the warning about the breached password or an empty string.
+// The check is optional, so its errors are only logged.
+func (b *Bot) breachWarning(chatID int64, password string) string {
+	n, err := b.logic.CheckBreach(password)
+	if err != nil {
+		if !errors.Is(err, usecase.ErrBreachCheckOff) {
+			b.logger.Warn(fmt.Sprintf("breach check error: chat %d: %v", chatID, err))
+		}
+		return ""
+	}
+
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf(b.handleMessageLang(breachFound, chatID), n)
+}
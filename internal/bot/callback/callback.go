@@ -0,0 +1,173 @@
+// Package callback encodes the data of inline keyboard buttons.
+//
+// Telegram limits callback data to 64 bytes, so the data is packed in binary:
+//
+//	action   1 byte
+//	expires  4 bytes, unix seconds, big endian, 0 means never
+//	count    uvarint, number of target messages
+//	messages uvarint each
+//	length   uvarint, length of the argument
+//	argument bytes
+//	mac      10 bytes, truncated HMAC-SHA256 of the chat ID (8 bytes, big endian) and all the above
+//
+// and then encoded with unpadded base64url. The MAC binds the data to the chat,
+// so buttons can't be forged, edited or reused in another chat.
+package callback
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// Action is what the button does.
+type Action byte
+
+// Actions of the buttons.
+const (
+	Hide Action = iota + 1
+	ChangeLang
+	SetLang
+	// ResolveImport resolves the conflicts of the import, the argument is the usecase.ImportMode.
+	ResolveImport
+	// SnoozeRotation snoozes the password rotation reminders.
+	SnoozeRotation
+	// GenerateRotation generates new passwords for the services of the rotation reminder.
+	GenerateRotation
+	// ConfirmWipe is the first step of the confirmation of the wipe.
+	ConfirmWipe
+	// ForgetMe deletes everything stored about the chat.
+	ForgetMe
+	// Reveal reveals the masked password of the message, the argument is how.
+	Reveal
+)
+
+// MaxLen maximum length of callback data allowed by Telegram.
+const MaxLen = 64
+
+const macSize = 10
+
+// Errors of encoding and decoding.
+var (
+	ErrTooLong      = errors.New("callback data is too long")
+	ErrMalformed    = errors.New("callback data is malformed")
+	ErrBadSignature = errors.New("callback data has a bad signature")
+	ErrExpired      = errors.New("callback data has expired")
+)
+
+// Data is the payload of a button.
+type Data struct {
+	Action Action
+	// Messages to act on, e.g. the messages to delete on hide.
+	Messages []int
+	// Arg is an action argument, e.g. the language or a reference to an entry.
+	Arg string
+	// Expires is the time after which the button is rejected. Zero means never.
+	Expires time.Time
+}
+
+// Codec signs and verifies callback data.
+type Codec struct {
+	key []byte
+	now func() time.Time
+}
+
+// NewCodec creates a codec with the key. A random key is used when key is empty,
+// then the buttons become invalid after the restart.
+func NewCodec(key []byte) (*Codec, error) {
+	if len(key) == 0 {
+		key = make([]byte, sha256.Size)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Codec{key: key, now: time.Now}, nil
+}
+
+// Encode packs and signs the data for the chat.
+func (c *Codec) Encode(chatID int64, d Data) (string, error) {
+	payload := []byte{byte(d.Action), 0, 0, 0, 0}
+	if !d.Expires.IsZero() {
+		binary.BigEndian.PutUint32(payload[1:], uint32(d.Expires.Unix()))
+	}
+
+	payload = binary.AppendUvarint(payload, uint64(len(d.Messages)))
+	for _, id := range d.Messages {
+		payload = binary.AppendUvarint(payload, uint64(id))
+	}
+
+	payload = binary.AppendUvarint(payload, uint64(len(d.Arg)))
+	payload = append(payload, d.Arg...)
+	payload = append(payload, c.mac(chatID, payload)...)
+
+	s := base64.RawURLEncoding.EncodeToString(payload)
+	if len(s) > MaxLen {
+		return "", ErrTooLong
+	}
+
+	return s, nil
+}
+
+// Decode verifies and unpacks the data of the chat.
+func (c *Codec) Decode(chatID int64, s string) (Data, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(raw) < 5+macSize {
+		return Data{}, ErrMalformed
+	}
+
+	payload, mac := raw[:len(raw)-macSize], raw[len(raw)-macSize:]
+	if !hmac.Equal(mac, c.mac(chatID, payload)) {
+		return Data{}, ErrBadSignature
+	}
+
+	d := Data{Action: Action(payload[0])}
+	if exp := binary.BigEndian.Uint32(payload[1:5]); exp != 0 {
+		d.Expires = time.Unix(int64(exp), 0)
+		if c.now().After(d.Expires) {
+			return Data{}, ErrExpired
+		}
+	}
+
+	rest := payload[5:]
+	count, rest, err := uvarint(rest)
+	if err != nil || count > uint64(len(rest)) {
+		return Data{}, ErrMalformed
+	}
+
+	for i := uint64(0); i < count; i++ {
+		var id uint64
+		id, rest, err = uvarint(rest)
+		if err != nil {
+			return Data{}, err
+		}
+		d.Messages = append(d.Messages, int(id))
+	}
+
+	length, rest, err := uvarint(rest)
+	if err != nil || length != uint64(len(rest)) {
+		return Data{}, ErrMalformed
+	}
+	d.Arg = string(rest)
+
+	return d, nil
+}
+
+func (c *Codec) mac(chatID int64, payload []byte) []byte {
+	h := hmac.New(sha256.New, c.key)
+	_ = binary.Write(h, binary.BigEndian, chatID)
+	h.Write(payload)
+	return h.Sum(nil)[:macSize]
+}
+
+func uvarint(buf []byte) (uint64, []byte, error) {
+	v, n := binary.Uvarint(buf)
+	if n <= 0 {
+		return 0, nil, ErrMalformed
+	}
+	return v, buf[n:], nil
+}
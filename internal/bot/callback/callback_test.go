@@ -0,0 +1,151 @@
+package callback
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newCodec(t *testing.T) *Codec {
+	c, err := NewCodec([]byte("test key"))
+	if err != nil {
+		t.Fatalf("NewCodec() error = %v", err)
+	}
+	return c
+}
+
+func TestCodec_Encode(t *testing.T) {
+	c := newCodec(t)
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name    string
+		data    Data
+		wantErr error
+	}{
+		{
+			name: "hide",
+			data: Data{Action: Hide, Messages: []int{123456789, 123456790}, Expires: expires},
+		},
+		{
+			name: "set lang",
+			data: Data{Action: SetLang, Arg: "ru"},
+		},
+		{
+			name: "no messages",
+			data: Data{Action: ChangeLang, Expires: expires},
+		},
+		{
+			name:    "too long",
+			data:    Data{Action: Hide, Arg: strings.Repeat("x", 40)},
+			wantErr: ErrTooLong,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := c.Encode(42, tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Encode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if len(s) > MaxLen {
+				t.Errorf("Encode() len = %d, want <= %d", len(s), MaxLen)
+			}
+
+			got, err := c.Decode(42, s)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.data) {
+				t.Errorf("Decode() got = %+v, want %+v", got, tt.data)
+			}
+		})
+	}
+}
+
+func TestCodec_Decode(t *testing.T) {
+	c := newCodec(t)
+
+	valid, err := c.Encode(42, Data{Action: Hide, Messages: []int{1}, Expires: time.Now().Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	expired, err := c.Encode(42, Data{Action: Hide, Expires: time.Now().Add(-time.Minute)})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	other, err := NewCodec([]byte("other key"))
+	if err != nil {
+		t.Fatalf("NewCodec() error = %v", err)
+	}
+	forged, err := other.Encode(42, Data{Action: Hide, Messages: []int{1}})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	tampered := []byte(valid)
+	tampered[2] ^= 1
+
+	tests := []struct {
+		name    string
+		chatID  int64
+		data    string
+		wantErr error
+	}{
+		{
+			name:   "ok",
+			chatID: 42,
+			data:   valid,
+		},
+		{
+			name:    "another chat",
+			chatID:  43,
+			data:    valid,
+			wantErr: ErrBadSignature,
+		},
+		{
+			name:    "forged",
+			chatID:  42,
+			data:    forged,
+			wantErr: ErrBadSignature,
+		},
+		{
+			name:    "tampered",
+			chatID:  42,
+			data:    string(tampered),
+			wantErr: ErrBadSignature,
+		},
+		{
+			name:    "expired",
+			chatID:  42,
+			data:    expired,
+			wantErr: ErrExpired,
+		},
+		{
+			name:    "legacy",
+			chatID:  42,
+			data:    "change::ru",
+			wantErr: ErrMalformed,
+		},
+		{
+			name:    "short",
+			chatID:  42,
+			data:    "hide",
+			wantErr: ErrMalformed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.Decode(tt.chatID, tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package bot
+
+import (
+	"fmt"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// commands returns all the commands of the bot in the order they are shown in the menu.
+func (b *Bot) commands() []Command {
+	return []Command{
+		{
+			Name:        start,
+			Description: messages{Russian: startDescriptionRU, English: startDescriptionEN},
+			MaxArgs:     1,
+			Keep:        true,
+			Handler:     b.handleStart,
+		},
+		{
+			Name:        set,
+			Description: messages{Russian: setDescriptionRU, English: setDescriptionEN},
+			MinArgs:     3,
+			MaxArgs:     5,
+			Access:      accessPrivate,
+			Handler:     b.handleSet,
+		},
+		{
+			Name:        get,
+			Description: messages{Russian: getDescriptionRU, English: getDescriptionEN},
+			MinArgs:     1,
+			MaxArgs:     1,
+			Access:      accessPrivate,
+			Handler:     b.handleGet,
+		},
+		{
+			Name:        del,
+			Description: messages{Russian: delDescriptionRU, English: delDescriptionEN},
+			MinArgs:     1,
+			MaxArgs:     1,
+			Access:      accessPrivate,
+			Handler:     b.handleDel,
+		},
+		{
+			Name:        display,
+			Description: messages{Russian: displayDescriptionRU, English: displayDescriptionEN},
+			MaxArgs:     1,
+			Access:      accessPrivate,
+			Handler:     b.handleDisplay,
+		},
+		{
+			Name:        fileCmd,
+			Description: messages{Russian: fileDescriptionRU, English: fileDescriptionEN},
+			MaxArgs:     2,
+			Access:      accessPrivate,
+			Handler:     b.handleFile,
+		},
+		{
+			Name:        check,
+			Description: messages{Russian: checkDescriptionRU, English: checkDescriptionEN},
+			MinArgs:     1,
+			MaxArgs:     1,
+			Access:      accessPrivate,
+			Handler:     b.handleCheck,
+		},
+		{
+			Name:        audit,
+			Description: messages{Russian: auditDescriptionRU, English: auditDescriptionEN},
+			Access:      accessPrivate,
+			Handler:     b.handleAudit,
+		},
+		{
+			Name:        rotate,
+			Description: messages{Russian: rotateDescriptionRU, English: rotateDescriptionEN},
+			MaxArgs:     2,
+			Access:      accessPrivate,
+			Handler:     b.handleRotate,
+		},
+		{
+			Name:        logCmd,
+			Description: messages{Russian: logDescriptionRU, English: logDescriptionEN},
+			Access:      accessPrivate,
+			Handler:     b.handleLog,
+		},
+		{
+			Name:        wipe,
+			Description: messages{Russian: wipeDescriptionRU, English: wipeDescriptionEN},
+			MaxArgs:     -1,
+			Access:      accessPrivate,
+			Handler:     b.handleWipe,
+		},
+		{
+			Name:        duress,
+			Description: messages{Russian: duressDescriptionRU, English: duressDescriptionEN},
+			MaxArgs:     1,
+			Access:      accessPrivate,
+			Handler:     b.handleDuress,
+		},
+		{
+			Name:        myData,
+			Description: messages{Russian: myDataDescriptionRU, English: myDataDescriptionEN},
+			Access:      accessPrivate,
+			Handler:     b.handleMyData,
+		},
+		{
+			Name:        forgetMeCmd,
+			Description: messages{Russian: forgetMeDescriptionRU, English: forgetMeDescriptionEN},
+			Access:      accessPrivate,
+			Handler:     b.handleForgetMe,
+		},
+		{
+			Name:        share,
+			Description: messages{Russian: shareDescriptionRU, English: shareDescriptionEN},
+			MinArgs:     2,
+			MaxArgs:     4,
+			Access:      accessPrivate,
+			Handler:     b.handleShare,
+		},
+		{
+			Name:        unshare,
+			Description: messages{Russian: unshareDescriptionRU, English: unshareDescriptionEN},
+			MinArgs:     2,
+			MaxArgs:     2,
+			Access:      accessPrivate,
+			Handler:     b.handleUnshare,
+		},
+		{
+			Name:        shares,
+			Description: messages{Russian: sharesDescriptionRU, English: sharesDescriptionEN},
+			Access:      accessPrivate,
+			Handler:     b.handleShares,
+		},
+		{
+			Name:        oneTime,
+			Description: messages{Russian: oneTimeDescriptionRU, English: oneTimeDescriptionEN},
+			MinArgs:     1,
+			MaxArgs:     2,
+			Access:      accessPrivate,
+			Handler:     b.handleOneTime,
+		},
+		{
+			Name:        export,
+			Description: messages{Russian: exportDescriptionRU, English: exportDescriptionEN},
+			MinArgs:     1,
+			MaxArgs:     -1,
+			Access:      accessPrivate,
+			Handler:     b.handleExport,
+		},
+		{
+			Name:        importCmd,
+			Description: messages{Russian: importDescriptionRU, English: importDescriptionEN},
+			MaxArgs:     -1,
+			Access:      accessPrivate,
+			Handler:     b.handleImport,
+		},
+		{
+			Name:        vault,
+			Description: messages{Russian: vaultDescriptionRU, English: vaultDescriptionEN},
+			MaxArgs:     5,
+			Handler:     b.handleVault,
+		},
+		{
+			Name:        admin,
+			Description: messages{Russian: adminDescriptionRU, English: adminDescriptionEN},
+			MinArgs:     1,
+			MaxArgs:     2,
+			Access:      accessAdmin,
+			Hidden:      true,
+			Handler:     b.handleAdmin,
+		},
+	}
+}
+
+// publishCommands shows the commands in the Telegram menu in every language.
+// English is the default for users with other languages.
+func (b *Bot) publishCommands() {
+	var ru, en []tgapi.BotCommand
+	for _, cmd := range b.router.list() {
+		if cmd.Hidden {
+			continue
+		}
+		ru = append(ru, tgapi.BotCommand{Command: cmd.Name, Description: cmd.Description.Russian})
+		en = append(en, tgapi.BotCommand{Command: cmd.Name, Description: cmd.Description.English})
+	}
+
+	configs := []tgapi.SetMyCommandsConfig{
+		tgapi.NewSetMyCommands(en...),
+		tgapi.NewSetMyCommandsWithScopeAndLanguage(tgapi.NewBotCommandScopeDefault(), "ru", ru...),
+	}
+
+	for _, c := range configs {
+		if _, err := b.client.Request(c); err != nil {
+			b.logger.Warn(fmt.Sprintf("set commands error: %v", err))
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"password-keeper/internal/usecase"
+	"strings"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// displayLabels the messages of the display modes.
+var displayLabels = map[usecase.DisplayMode]string{
+	usecase.DisplayAll:      displayAll,
+	usecase.DisplayLogin:    displayLogin,
+	usecase.DisplayPassword: displayPassword,
+	usecase.DisplayReveal:   displayReveal,
+}
+
+// handleDisplay handles display command: /display [all|login|password|reveal].
+func (b *Bot) handleDisplay(req *Request) error {
+	chatID := req.ChatID()
+	if len(req.Args) == 0 {
+		mode, err := b.logic.Display(chatID)
+		if err != nil {
+			b.replyText(req, internalErr)
+			return fmt.Errorf("display error: %w", err)
+		}
+
+		b.reply(req, tgapi.NewMessage(chatID, fmt.Sprintf(b.handleMessageLang(displayStatus, chatID),
+			b.handleMessageLang(displayLabels[mode], chatID))))
+		return nil
+	}
+
+	mode := usecase.DisplayMode(strings.ToLower(req.Args[0]))
+	err := b.logic.SetDisplay(chatID, mode)
+	switch {
+	case errors.Is(err, usecase.ErrDisplayMode):
+		b.replyText(req, wrongInputErr)
+		return ErrWrongInput
+	case err != nil:
+		b.replyText(req, internalErr)
+		return fmt.Errorf("display error: %w", err)
+	}
+
+	b.reply(req, tgapi.NewMessage(chatID, fmt.Sprintf(b.handleMessageLang(displaySet, chatID),
+		b.handleMessageLang(displayLabels[mode], chatID))))
+	return nil
+}
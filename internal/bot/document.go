@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxDocumentSize limits the documents downloaded from Telegram.
+const maxDocumentSize = 1 << 20
+
+// downloadTimeout limits the download of a document.
+const downloadTimeout = 30 * time.Second
+
+// ErrDocumentTooLarge occurs when the document is larger than the limit of the download.
+var ErrDocumentTooLarge = errors.New("document is too large")
+
+// documentCommand turns the command in the caption of a document or a photo into the command of the message,
+// so files can be sent together with commands like /import.
+// In private chats a caption without a command names the service the file is attached to with /file,
+// and documents without a caption are imported: they are most likely
+// exports of password managers, which must not stay in the chat.
+func documentCommand(msg *tgapi.Message) {
+	if msg.Text != "" || (msg.Document == nil && len(msg.Photo) == 0) {
+		return
+	}
+
+	if len(msg.CaptionEntities) > 0 && msg.CaptionEntities[0].Offset == 0 && msg.CaptionEntities[0].IsCommand() {
+		msg.Text, msg.Entities = msg.Caption, msg.CaptionEntities
+		return
+	}
+
+	if msg.Chat == nil || !msg.Chat.IsPrivate() {
+		return
+	}
+
+	switch {
+	case strings.TrimSpace(msg.Caption) != "":
+		msg.Text = "/" + fileCmd
+		msg.Entities = []tgapi.MessageEntity{{Type: "bot_command", Length: len(msg.Text)}}
+		msg.Text += " " + msg.Caption
+	case msg.Document != nil:
+		msg.Text = "/" + importCmd
+		msg.Entities = []tgapi.MessageEntity{{Type: "bot_command", Length: len(msg.Text)}}
+	}
+}
+
+// document returns the document sent with the command or the one the command replies to.
+func document(msg *tgapi.Message) *tgapi.Document {
+	if msg.Document != nil {
+		return msg.Document
+	}
+	if msg.ReplyToMessage != nil {
+		return msg.ReplyToMessage.Document
+	}
+	return nil
+}
+
+// download downloads the document from Telegram.
+func (b *Bot) download(doc *tgapi.Document) ([]byte, error) {
+	return b.downloadFile(doc.FileID, doc.FileSize, maxDocumentSize)
+}
+
+// downloadFile downloads the file from Telegram, files larger than limit are not downloaded.
+func (b *Bot) downloadFile(fileID string, size, limit int) ([]byte, error) {
+	if size > limit {
+		return nil, ErrDocumentTooLarge
+	}
+
+	resp, err := b.client.Request(tgapi.FileConfig{FileID: fileID})
+	if err != nil {
+		return nil, fmt.Errorf("get file: %w", err)
+	}
+
+	var file tgapi.File
+	if err := json.Unmarshal(resp.Result, &file); err != nil {
+		return nil, fmt.Errorf("get file: %w", err)
+	}
+
+	client := http.Client{Timeout: downloadTimeout}
+	r, err := client.Get(fmt.Sprintf(b.fileEndpoint(), b.token, file.FilePath))
+	if err != nil {
+		return nil, fmt.Errorf("download: %w", err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download: %s", r.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, int64(limit)+1))
+	if err != nil {
+		return nil, fmt.Errorf("download: %w", err)
+	}
+	if len(data) > limit {
+		return nil, ErrDocumentTooLarge
+	}
+	return data, nil
+}
+
+// fileEndpoint returns the file endpoint of the Bot API server, see tgapi.FileEndpoint.
+func (b *Bot) fileEndpoint() string {
+	return strings.Replace(b.apiEndpoint, "/bot%s/", "/file/bot%s/", 1)
+}
@@ -0,0 +1,78 @@
+package bot
+
+import (
+	"fmt"
+	"password-keeper/internal/usecase"
+	"time"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// expirySweepInterval how often the expired entries are deleted,
+// the lookups treat them as missing in between.
+const expirySweepInterval = time.Minute
+
+// sweepExpired warns the owners about the entries expiring soon and deletes
+// the expired ones every expirySweepInterval until Shutdown.
+func (b *Bot) sweepExpired() {
+	ticker := time.NewTicker(expirySweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.quit:
+			return
+		case <-ticker.C:
+			b.warnExpiring()
+			b.deleteExpired()
+		}
+	}
+}
+
+// warnExpiring warns the owners about the entries expiring within usecase.ExpiryWarning.
+// The entries are marked warned only when the warning is sent, so it's retried otherwise.
+func (b *Bot) warnExpiring() {
+	due, err := b.logic.ExpiryWarnings()
+	if err != nil {
+		return
+	}
+
+	for chatID, entries := range due {
+		if !b.sendExpiry(chatID, expiryWarning, entries) {
+			continue
+		}
+
+		if err := b.logic.ExpiryWarned(chatID, entries); err != nil {
+			b.logger.Warn(fmt.Sprintf("expiry warning error: chat %d: %v", chatID, err))
+		}
+	}
+}
+
+// deleteExpired deletes the expired entries and tells their owners.
+func (b *Bot) deleteExpired() {
+	swept, err := b.logic.SweepExpired()
+	if err != nil {
+		return
+	}
+
+	for chatID, entries := range swept {
+		b.logger.Info(fmt.Sprintf("deleted %d expired entries of %d", len(entries), chatID))
+		b.sendExpiry(chatID, expiredNotice, entries)
+	}
+}
+
+// sendExpiry sends the message listing the entries with their expiry and returns true on success.
+func (b *Bot) sendExpiry(chatID int64, key string, entries []usecase.Expiring) bool {
+	items := make([]string, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, fmt.Sprintf("%s — %s", e.Service, e.ExpiresAt.UTC().Format(activityTimeFormat)))
+	}
+
+	for _, part := range splitMessage(fmt.Sprintf(b.handleMessageLang(key, chatID), bulletList(items))) {
+		if _, err := b.client.Send(tgapi.NewMessage(chatID, part)); err != nil {
+			b.logger.Warn(fmt.Sprintf("expiry message error: chat %d: %v", chatID, err))
+			return false
+		}
+	}
+	return true
+}
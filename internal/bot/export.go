@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"password-keeper/internal/importer"
+	"password-keeper/internal/usecase"
+	"strings"
+	"time"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleExport handles export command: /export passphrase.
+// The passphrase may contain spaces.
+func (b *Bot) handleExport(req *Request) error {
+	passphrase := strings.TrimSpace(req.Message.CommandArguments())
+
+	file, exported, unnamed, err := b.logic.Export(req.ChatID(), passphrase)
+	if err != nil {
+		b.replyExportErr(req, err)
+		return fmt.Errorf("export error: %w", err)
+	}
+
+	caption := fmt.Sprintf(b.handleMessageLang(exportDone, req.ChatID()), exported)
+	if unnamed > 0 {
+		caption += fmt.Sprintf(b.handleMessageLang(exportUnnamed, req.ChatID()), unnamed)
+	}
+
+	doc := tgapi.NewDocument(req.ChatID(), tgapi.FileBytes{
+		Name:  "keeper-export-" + time.Now().UTC().Format("2006-01-02") + ".json",
+		Bytes: file,
+	})
+	doc.Caption = caption
+	doc.ReplyMarkup = b.hideKeyboard(req.ChatID(), req.Message.MessageID)
+
+	b.reply(req, doc)
+	return nil
+}
+
+// replyExportErr replies with the message explaining the error.
+func (b *Bot) replyExportErr(req *Request, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrWeakPassphrase):
+		b.reply(req, tgapi.NewMessage(req.ChatID(),
+			fmt.Sprintf(b.handleMessageLang(weakPassphraseErr, req.ChatID()), usecase.MinPassphraseLen)))
+	case errors.Is(err, usecase.ErrNothingToExport):
+		b.replyText(req, exportEmptyErr)
+	case errors.Is(err, usecase.ErrBadExport):
+		b.replyText(req, badExportErr)
+	case errors.Is(err, usecase.ErrLocked):
+		b.reply(req, tgapi.NewMessage(req.ChatID(), b.lockedMessage(req.ChatID())))
+	case errors.Is(err, ErrDocumentTooLarge):
+		b.replyText(req, documentTooLargeErr)
+	case errors.Is(err, importer.ErrUnknownFormat):
+		b.replyText(req, unknownFormatErr)
+	case errors.Is(err, importer.ErrEncrypted):
+		b.replyText(req, encryptedFileErr)
+	default:
+		b.replyText(req, internalErr)
+	}
+}
@@ -0,0 +1,172 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"password-keeper/internal/storage"
+	"password-keeper/internal/usecase"
+	"strings"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// fileDelete is the argument of /file deleting the file.
+const fileDelete = "del"
+
+// photoFileName name of the photos sent back, Telegram doesn't keep the names of photos.
+const photoFileName = "photo.jpg"
+
+// attachment is the document or the photo sent with /file.
+type attachment struct {
+	fileID   string
+	fileName string
+	size     int
+	photo    bool
+	// messageID of the message with the file, it's deleted once the file is read.
+	messageID int
+}
+
+// handleFile handles file command:
+// a document or a photo with the caption "service" or "/file service" is attached to the service,
+// /file service sends it back, /file service del deletes it and /file lists the files.
+func (b *Bot) handleFile(req *Request) error {
+	if a, ok := fileAttachment(req.Message); ok {
+		return b.saveFile(req, a)
+	}
+
+	switch len(req.Args) {
+	case 0:
+		return b.listFiles(req)
+	case 1:
+		return b.sendFile(req, req.Args[0])
+	}
+
+	if req.Args[1] != fileDelete {
+		b.replyText(req, fileUsage)
+		return ErrWrongInput
+	}
+	return b.deleteFile(req, req.Args[0])
+}
+
+// fileAttachment returns the file sent with the command or the one the command replies to.
+// The largest size of a photo is taken.
+func fileAttachment(msg *tgapi.Message) (attachment, bool) {
+	for _, m := range []*tgapi.Message{msg, msg.ReplyToMessage} {
+		switch {
+		case m == nil:
+		case m.Document != nil:
+			return attachment{fileID: m.Document.FileID, fileName: m.Document.FileName, size: m.Document.FileSize, messageID: m.MessageID}, true
+		case len(m.Photo) > 0:
+			p := m.Photo[len(m.Photo)-1]
+			return attachment{fileID: p.FileID, size: p.FileSize, photo: true, messageID: m.MessageID}, true
+		}
+	}
+	return attachment{}, false
+}
+
+// saveFile encrypts and stores the attachment, the original message is deleted right away.
+func (b *Bot) saveFile(req *Request, a attachment) error {
+	b.deleteMessage(MessageInfo{chatID: req.ChatID(), id: a.messageID})
+
+	if len(req.Args) != 1 {
+		b.replyText(req, fileUsage)
+		return ErrWrongInput
+	}
+
+	data, err := b.downloadFile(a.fileID, a.size, usecase.MaxFileSize)
+	if err == nil {
+		err = b.logic.SaveFile(req.ChatID(), usecase.File{Service: req.Args[0], FileName: a.fileName, Photo: a.photo, Data: data})
+	}
+	if err != nil {
+		if errors.Is(err, ErrDocumentTooLarge) || errors.Is(err, usecase.ErrFileTooLarge) {
+			b.reply(req, tgapi.NewMessage(req.ChatID(),
+				fmt.Sprintf(b.handleMessageLang(fileTooLargeErr, req.ChatID()), usecase.MaxFileSize>>20)))
+		} else {
+			b.replyText(req, internalErr)
+		}
+		return fmt.Errorf("file error: %w", err)
+	}
+
+	b.reply(req, tgapi.NewMessage(req.ChatID(), fmt.Sprintf(b.handleMessageLang(fileSaved, req.ChatID()), req.Args[0])))
+	return nil
+}
+
+// sendFile sends the decrypted file back, it's deleted with the other replies.
+func (b *Bot) sendFile(req *Request, service string) error {
+	f, err := b.logic.GetFile(req.ChatID(), service)
+	if err != nil {
+		return b.replyFileErr(req, err)
+	}
+
+	caption := fmt.Sprintf(b.handleMessageLang(fileCaption, req.ChatID()), service)
+	markup := b.hideKeyboard(req.ChatID(), req.Message.MessageID)
+	if f.Photo {
+		photo := tgapi.NewPhoto(req.ChatID(), tgapi.FileBytes{Name: photoFileName, Bytes: f.Data})
+		photo.Caption, photo.ReplyMarkup = caption, markup
+		b.reply(req, photo)
+		return nil
+	}
+
+	name := f.FileName
+	if name == "" {
+		name = service
+	}
+	doc := tgapi.NewDocument(req.ChatID(), tgapi.FileBytes{Name: name, Bytes: f.Data})
+	doc.Caption, doc.ReplyMarkup = caption, markup
+	b.reply(req, doc)
+	return nil
+}
+
+// deleteFile deletes the file of the service.
+func (b *Bot) deleteFile(req *Request, service string) error {
+	if err := b.logic.DeleteFile(req.ChatID(), service); err != nil {
+		return b.replyFileErr(req, err)
+	}
+
+	b.replyText(req, fileDeleted)
+	return nil
+}
+
+// listFiles lists the files of the chat.
+func (b *Bot) listFiles(req *Request) error {
+	chatID := req.ChatID()
+	files, err := b.logic.Files(chatID)
+	if err != nil {
+		b.replyText(req, internalErr)
+		return fmt.Errorf("file error: %w", err)
+	}
+
+	if len(files) == 0 {
+		b.replyText(req, filesEmpty)
+		return nil
+	}
+
+	lines := []string{b.handleMessageLang(filesTitle, chatID)}
+	for _, f := range files {
+		name := f.FileName
+		if f.Photo {
+			name = b.handleMessageLang(filePhoto, chatID)
+		}
+		lines = append(lines, fmt.Sprintf("• %s — %s, %s", f.Service, name, f.CreatedAt.UTC().Format(dataDateFormat)))
+	}
+
+	msgConfig := tgapi.NewMessage(chatID, strings.Join(lines, "\n"))
+	msgConfig.ReplyMarkup = b.hideKeyboard(chatID, req.Message.MessageID)
+	b.reply(req, msgConfig)
+	return nil
+}
+
+// replyFileErr replies with the message explaining the error of reading or deleting the file.
+// Missing files are not errors of the bot.
+func (b *Bot) replyFileErr(req *Request, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		b.replyText(req, fileNotFoundErr)
+		return nil
+	case errors.Is(err, usecase.ErrLocked):
+		b.reply(req, tgapi.NewMessage(req.ChatID(), b.lockedMessage(req.ChatID())))
+	default:
+		b.replyText(req, internalErr)
+	}
+	return fmt.Errorf("file error: %w", err)
+}
@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"fmt"
+	"password-keeper/internal/entity"
+	"password-keeper/internal/usecase"
+	"strings"
+)
+
+// htmlEscaper escapes the text for the HTML parse mode, these are all the entities Telegram supports.
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+// escapeHTML escapes the text for the HTML parse mode.
+func escapeHTML(s string) string {
+	return htmlEscaper.Replace(s)
+}
+
+// monospace formats the text as code, Telegram copies it on tap.
+func monospace(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "<code>" + escapeHTML(s) + "</code>"
+}
+
+// spoiler hides the formatted text until it's tapped.
+func spoiler(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "<tg-spoiler>" + s + "</tg-spoiler>"
+}
+
+// passwordMask is shown instead of the password until it's revealed.
+const passwordMask = "••••••••"
+
+// displayRevealed renders the password revealed with a button as is, without the spoiler.
+const displayRevealed usecase.DisplayMode = "revealed"
+
+// displayMode returns the display mode of the chat, the error is logged and everything is shown then.
+func (b *Bot) displayMode(chatID int64) usecase.DisplayMode {
+	mode, _ := b.logic.Display(chatID)
+	return mode
+}
+
+// credentials renders the entry for the HTML parse mode with the display mode:
+// the login and the password are copied on tap and the password is hidden under a spoiler or masked.
+func (b *Bot) credentials(chatID int64, mode usecase.DisplayMode, service string, pair entity.Pair) string {
+	name, login, password := escapeHTML(service), monospace(pair.Login), spoiler(monospace(pair.Password))
+
+	var text string
+	switch mode {
+	case usecase.DisplayLogin:
+		text = fmt.Sprintf(b.handleMessageLang(getLogin, chatID), name, login)
+	case usecase.DisplayPassword:
+		text = fmt.Sprintf(b.handleMessageLang(getPassword, chatID), name, password)
+	case usecase.DisplayReveal:
+		text = fmt.Sprintf(b.handleMessageLang(get, chatID), name, login, passwordMask)
+	case displayRevealed:
+		text = fmt.Sprintf(b.handleMessageLang(get, chatID), name, login, monospace(pair.Password))
+	default:
+		text = fmt.Sprintf(b.handleMessageLang(get, chatID), name, login, password)
+	}
+
+	if !pair.ExpiresAt.IsZero() {
+		text += fmt.Sprintf(b.handleMessageLang(getExpires, chatID), pair.ExpiresAt.UTC().Format(activityTimeFormat))
+	}
+	return text
+}
@@ -4,275 +4,284 @@ import (
 	"errors"
 	"fmt"
 	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"log"
+	"math"
+	"password-keeper/internal/bot/callback"
+	"password-keeper/internal/entity"
 	"password-keeper/internal/storage"
+	"password-keeper/internal/usecase"
 	"strings"
 	"time"
 )
 
-// handleMessage handles commands.
-func (b *Bot) handleCommand(msg *tgapi.Message) {
-	switch msg.Command() {
-	case start:
-		b.handleStart(msg)
-	case set:
-		b.handleSet(msg)
-	case get:
-		b.handleGet(msg)
-	case del:
-		b.handleDel(msg)
+// handleMessage handles messages which are not commands.
+// The duress PIN wipes the chat silently: the message is deleted and nothing is replied.
+func (b *Bot) handleMessage(msg *tgapi.Message) {
+	if !msg.Chat.IsPrivate() || msg.Text == "" {
+		return
 	}
-}
 
-// handleMessage handles messages.
-func (b *Bot) handleMessage() {
+	wiped, err := b.logic.Duress(msg.Chat.ID, strings.TrimSpace(msg.Text))
+	if err != nil {
+		b.logger.Warn(fmt.Sprintf("duress error: chat %d: %v", msg.Chat.ID, err))
+	}
+	if !wiped {
+		return
+	}
 
+	b.deleteMessage(MessageInfo{chatID: msg.Chat.ID, id: msg.MessageID})
+	b.forgetChat(msg.Chat.ID)
 }
 
 // handleMessageLang handles messages languages.
 func (b *Bot) handleMessageLang(msg string, chatID int64) string {
-	lang := b.logic.GetLang(chatID)
-	switch lang {
-	case "ru":
-		return allMessages[msg].Russian
-	default:
-		return allMessages[msg].English
-	}
+	return localize(msg, b.logic.GetLang(chatID))
 }
 
-// handleKeyboardLang handles keyboards languages.
-func (b *Bot) handleKeyboardLang(keyboard string, chatID int64) tgapi.InlineKeyboardMarkup {
-	lang := b.logic.GetLang(chatID)
+// localize returns the message in the language.
+func localize(msg string, lang string) string {
 	switch lang {
 	case "ru":
-		return allKeyboards[keyboard].Russian
+		return allMessages[msg].Russian
 	default:
-		return allKeyboards[keyboard].English
+		return allMessages[msg].English
 	}
 }
 
 // handleStart handles start command.
-func (b *Bot) handleStart(msg *tgapi.Message) {
-	msgConfig := tgapi.NewMessage(msg.Chat.ID,
-		fmt.Sprintf(b.handleMessageLang(start, msg.Chat.ID),
+func (b *Bot) handleStart(req *Request) error {
+	msgConfig := tgapi.NewMessage(req.ChatID(),
+		fmt.Sprintf(b.handleMessageLang(start, req.ChatID()),
 			b.hideInterval))
-	msgConfig.ReplyMarkup = b.handleKeyboardLang(startKeyboard, msg.Chat.ID)
+	msgConfig.ReplyMarkup = b.startKeyboard(req.ChatID())
 
-	_, err := b.Send(msgConfig)
-	if err != nil {
-		log.Println("send error: ", err)
-	}
+	b.reply(req, msgConfig)
+	return nil
 }
 
-func (b *Bot) handleSet(msg *tgapi.Message) {
-	split := strings.Split(msg.Text, " ")
-
-	msgConfig := tgapi.NewMessage(msg.Chat.ID, b.handleMessageLang(set, msg.Chat.ID))
-	if len(split) != 4 {
-		msgConfig = tgapi.NewMessage(msg.Chat.ID, b.handleMessageLang(wrongInputErr, msg.Chat.ID))
-		m, err := b.Send(msgConfig)
-		if err != nil {
-			log.Println("send error: ", err)
-		} else {
-			b.toHide <- MessageInfo{
-				chatID:    msg.Chat.ID,
-				id:        msg.MessageID,
-				createdAt: time.Now(),
-			}
-
-			b.toHide <- MessageInfo{
-				chatID:    m.Chat.ID,
-				id:        m.MessageID,
-				createdAt: time.Now(),
-			}
+// handleSet handles set command: /set service login password [--expires ttl].
+func (b *Bot) handleSet(req *Request) error {
+	msgConfig := tgapi.NewMessage(req.ChatID(), b.handleMessageLang(set, req.ChatID()))
+
+	var expiresAt time.Time
+	switch len(req.Args) {
+	case 3:
+	case 5:
+		ttl, err := parseTTL(req.Args[4])
+		if req.Args[3] != expiresFlag || err != nil {
+			b.replyText(req, wrongInputErr)
+			return ErrWrongInput
 		}
-		return
-	}
-
-	err := b.logic.Save(msg.Chat.ID, split[1], split[2], split[3])
-	if err != nil {
-		msgConfig.Text = b.handleMessageLang(setErr, msg.Chat.ID)
-		log.Printf("save error: %v\n", err)
+		expiresAt = time.Now().Add(ttl)
+	default:
+		b.replyText(req, wrongInputErr)
+		return ErrWrongInput
 	}
 
-	m, err := b.Send(msgConfig)
+	err := b.logic.SaveUntil(req.ChatID(), req.Args[0], req.Args[1], req.Args[2], expiresAt)
 	if err != nil {
-		log.Println("send error: ", err)
+		switch {
+		case errors.Is(err, usecase.ErrReadOnly):
+			msgConfig.Text = b.handleMessageLang(readOnlyErr, req.ChatID())
+		case errors.Is(err, usecase.ErrExpiry):
+			msgConfig.Text = fmt.Sprintf(b.handleMessageLang(expiryErr, req.ChatID()), int(usecase.MinExpiry.Hours()))
+		default:
+			msgConfig.Text = b.handleMessageLang(setErr, req.ChatID())
+		}
+		err = fmt.Errorf("save error: %w", err)
 	} else {
-		b.toHide <- MessageInfo{
-			chatID:    msg.Chat.ID,
-			id:        msg.MessageID,
-			createdAt: time.Now(),
+		if !expiresAt.IsZero() {
+			msgConfig.Text += "\n" + fmt.Sprintf(b.handleMessageLang(setExpires, req.ChatID()), expiresAt.UTC().Format(activityTimeFormat))
 		}
-
-		b.toHide <- MessageInfo{
-			chatID:    m.Chat.ID,
-			id:        m.MessageID,
-			createdAt: time.Now(),
+		if note := b.strengthNote(req.ChatID(), req.Args[0], req.Args[2]); note != "" {
+			msgConfig.Text += "\n" + note
+		}
+		if warning := b.breachWarning(req.ChatID(), req.Args[2]); warning != "" {
+			msgConfig.Text += "\n" + warning
 		}
 	}
+
+	b.reply(req, msgConfig)
+	return err
 }
 
-func (b *Bot) handleGet(msg *tgapi.Message) {
-	split := strings.Split(msg.Text, " ")
-
-	msgConfig := tgapi.NewMessage(msg.Chat.ID, b.handleMessageLang(get, msg.Chat.ID))
-	if len(split) != 2 {
-		msgConfig = tgapi.NewMessage(msg.Chat.ID, b.handleMessageLang(wrongInputErr, msg.Chat.ID))
-		m, err := b.Send(msgConfig)
-		if err != nil {
-			log.Println("send error: ", err)
-		} else {
-			b.toHide <- MessageInfo{
-				chatID:    msg.Chat.ID,
-				id:        msg.MessageID,
-				createdAt: time.Now(),
-			}
-
-			b.toHide <- MessageInfo{
-				chatID:    m.Chat.ID,
-				id:        m.MessageID,
-				createdAt: time.Now(),
-			}
-		}
-		return
-	}
-	service := split[1]
+func (b *Bot) handleGet(req *Request) error {
+	service := req.Args[0]
+	msgConfig := tgapi.NewMessage(req.ChatID(), b.handleMessageLang(get, req.ChatID()))
 
-	pair, err := b.logic.Get(msg.Chat.ID, service)
+	pair, err := b.logic.Get(req.ChatID(), service)
 	if err != nil {
-		if errors.Is(err, storage.ErrNotFound) {
-			msgConfig.Text = b.handleMessageLang(serviceNotFoundErr, msg.Chat.ID)
-		} else {
-			msgConfig.Text = b.handleMessageLang(getErr, msg.Chat.ID)
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			msgConfig.Text = b.handleMessageLang(serviceNotFoundErr, req.ChatID())
+		case errors.Is(err, usecase.ErrLocked):
+			msgConfig.Text = b.lockedMessage(req.ChatID())
+		default:
+			msgConfig.Text = b.handleMessageLang(getErr, req.ChatID())
 		}
-		log.Printf("get error: %v\n", err)
+		err = fmt.Errorf("get error: %w", err)
 	} else {
-		msgConfig.ReplyMarkup = b.handleKeyboardLang(hideKeyboard, msg.Chat.ID)
-		msgConfig.Text = fmt.Sprintf(b.handleMessageLang(get, msg.Chat.ID), service, pair.Login, pair.Password)
+		mode := b.displayMode(req.ChatID())
+		hide := b.hideKeyboard(req.ChatID(), req.Message.MessageID)
+		msgConfig.ReplyMarkup = hide
+		msgConfig.ParseMode = tgapi.ModeHTML
+		msgConfig.Text = b.credentials(req.ChatID(), mode, service, pair)
+		if mode == usecase.DisplayReveal {
+			chatID := req.ChatID()
+			b.replyMasked(req, reveal{
+				service: service,
+				text:    msgConfig.Text,
+				hide:    hide,
+				lookup: func() (entity.Pair, error) {
+					return b.logic.Get(chatID, service)
+				},
+			})
+			return nil
+		}
 	}
 
-	m, err := b.Send(msgConfig)
-	if err != nil {
-		log.Println("send error: ", err)
-	} else {
-		b.toHide <- MessageInfo{
-			chatID:    msg.Chat.ID,
-			id:        msg.MessageID,
-			createdAt: time.Now(),
-		}
+	b.reply(req, msgConfig)
+	return err
+}
+
+func (b *Bot) handleDel(req *Request) error {
+	msgConfig := tgapi.NewMessage(req.ChatID(), b.handleMessageLang(del, req.ChatID()))
 
-		b.toHide <- MessageInfo{
-			chatID:    m.Chat.ID,
-			id:        m.MessageID,
-			createdAt: time.Now(),
+	err := b.logic.Delete(req.ChatID(), req.Args[0])
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			msgConfig.Text = b.handleMessageLang(serviceNotFoundErr, req.ChatID())
+			err = nil
+		case errors.Is(err, usecase.ErrLocked):
+			msgConfig.Text = b.lockedMessage(req.ChatID())
+			err = fmt.Errorf("del error: %w", err)
+		default:
+			msgConfig.Text = b.handleMessageLang(delErr, req.ChatID())
+			err = fmt.Errorf("del error: %w", err)
 		}
 	}
+
+	b.reply(req, msgConfig)
+	return err
 }
 
-func (b *Bot) handleDel(msg *tgapi.Message) {
-	split := strings.Split(msg.Text, " ")
-
-	msgConfig := tgapi.NewMessage(msg.Chat.ID, b.handleMessageLang(del, msg.Chat.ID))
-	if len(split) != 2 {
-		msgConfig = tgapi.NewMessage(msg.Chat.ID, b.handleMessageLang(wrongInputErr, msg.Chat.ID))
-		m, err := b.Send(msgConfig)
-		if err != nil {
-			log.Println("send error: ", err)
-		} else {
-			b.toHide <- MessageInfo{
-				chatID:    msg.Chat.ID,
-				id:        msg.MessageID,
-				createdAt: time.Now(),
-			}
-
-			b.toHide <- MessageInfo{
-				chatID:    m.Chat.ID,
-				id:        m.MessageID,
-				createdAt: time.Now(),
-			}
-		}
-		return
+// lockedMessage returns the message for the locked out chat and counts the request.
+func (b *Bot) lockedMessage(chatID int64) string {
+	lockedRequests.Add(1)
+
+	left := time.Until(b.logic.LockedUntil(chatID))
+	minutes := int(math.Ceil(left.Minutes()))
+	if minutes < 1 {
+		minutes = 1
 	}
-	service := split[1]
 
-	err := b.logic.Delete(msg.Chat.ID, service)
+	return fmt.Sprintf(b.handleMessageLang(lockedErr, chatID), minutes)
+}
+
+// reply sends the message and remembers it, so the middlewares can process it.
+func (b *Bot) reply(req *Request, c tgapi.Chattable) {
+	m, err := b.client.Send(c)
 	if err != nil {
-		if errors.Is(err, storage.ErrNotFound) {
-			msgConfig.Text = b.handleMessageLang(serviceNotFoundErr, msg.Chat.ID)
-		} else {
-			msgConfig.Text = b.handleMessageLang(delErr, msg.Chat.ID)
-			log.Printf("del error: %v\n", err)
-		}
+		b.logger.Warn(fmt.Sprintf("send error: %v", err))
+		return
 	}
 
-	m, err := b.Send(msgConfig)
-	if err != nil {
-		log.Println("send error: ", err)
-	} else {
-		b.toHide <- MessageInfo{
-			chatID:    msg.Chat.ID,
-			id:        msg.MessageID,
-			createdAt: time.Now(),
-		}
+	req.sent = append(req.sent, m)
+}
 
-		b.toHide <- MessageInfo{
-			chatID:    m.Chat.ID,
-			id:        m.MessageID,
-			createdAt: time.Now(),
-		}
+// hideLater queues the message for deletion.
+func (b *Bot) hideLater(msg *tgapi.Message) {
+	b.toHide <- MessageInfo{
+		chatID:    msg.Chat.ID,
+		id:        msg.MessageID,
+		createdAt: time.Now(),
 	}
 }
 
-// handleMessage handle callbacks from user.
+// hideAfter queues the message for deletion after d instead of hideInterval.
+func (b *Bot) hideAfter(msg *tgapi.Message, d time.Duration) {
+	b.toHide <- MessageInfo{
+		chatID:    msg.Chat.ID,
+		id:        msg.MessageID,
+		createdAt: time.Now().Add(d - time.Duration(b.hideInterval)*time.Second),
+	}
+}
+
+// handleCallbackQuery handles callbacks from user.
 func (b *Bot) handleCallbackQuery(query *tgapi.CallbackQuery) {
-	split := strings.Split(query.Data, "::")
-	if len(split) == 0 {
+	if query.Message == nil {
 		return
 	}
 
 	defer b.logger.Sync()
 
-	text := split[0]
-
-	switch text {
-	case hide:
-		msg := tgapi.NewDeleteMessage(query.Message.Chat.ID, query.Message.MessageID)
-		if _, err := b.Send(msg); err != nil {
-			b.logger.Warn(fmt.Sprintf("del error: %v", err.Error()))
-		}
+	chatID := query.Message.Chat.ID
+	data, err := b.callbacks.Decode(chatID, query.Data)
+	if err != nil {
+		b.logger.Warn(fmt.Sprintf("callback error: chat %d: %v", chatID, err))
+		b.answer(query, b.handleMessageLang(expiredButtonErr, chatID))
+		return
+	}
 
-		msg = tgapi.NewDeleteMessage(query.Message.Chat.ID, query.Message.MessageID-1)
-		if _, err := b.Send(msg); err != nil {
-			b.logger.Warn(fmt.Sprintf("del error: %v", err.Error()))
+	switch data.Action {
+	case callback.Hide:
+		b.deleteMessage(MessageInfo{chatID: chatID, id: query.Message.MessageID})
+		for _, id := range data.Messages {
+			b.deleteMessage(MessageInfo{chatID: chatID, id: id})
 		}
-	case changeLang:
+	case callback.ChangeLang:
 		msg := tgapi.NewEditMessageTextAndMarkup(
-			query.Message.Chat.ID,
+			chatID,
 			query.Message.MessageID,
-			"Choose a new language 🌎",
-			b.handleKeyboardLang(setLangKeyboard, query.Message.Chat.ID),
+			chooseLangMessage,
+			b.setLangKeyboard(chatID),
 		)
 
-		if _, err := b.Send(msg); err != nil {
+		if _, err := b.client.Send(msg); err != nil {
 			b.logger.Warn(fmt.Sprintf("send error: %v", err.Error()))
 		}
-	case change:
-		if len(split) == 1 {
-			return
+	case callback.ResolveImport:
+		b.answer(query, b.resolveImport(query, usecase.ImportMode(data.Arg)))
+		return
+	case callback.SnoozeRotation:
+		b.answer(query, b.snoozeRotation(query))
+		return
+	case callback.GenerateRotation:
+		b.answer(query, b.generateRotation(query))
+		return
+	case callback.ConfirmWipe:
+		b.confirmWipe(query)
+	case callback.ForgetMe:
+		b.answer(query, b.forgetMe(query))
+		return
+	case callback.Reveal:
+		b.answer(query, b.revealPassword(query, data.Arg))
+		return
+	case callback.SetLang:
+		if usecase.MatchLang(data.Arg) != data.Arg {
+			b.logger.Warn(fmt.Sprintf("callback error: unsupported language %q", data.Arg))
+			break
 		}
 
-		b.logic.SetLang(query.Message.Chat.ID, split[1])
+		b.logic.SetLang(chatID, data.Arg)
 
 		msg := tgapi.NewEditMessageTextAndMarkup(
-			query.Message.Chat.ID, query.Message.MessageID,
-			fmt.Sprintf(b.handleMessageLang(start, query.Message.Chat.ID), b.hideInterval),
-			b.handleKeyboardLang(startKeyboard, query.Message.Chat.ID),
+			chatID, query.Message.MessageID,
+			fmt.Sprintf(b.handleMessageLang(start, chatID), b.hideInterval),
+			b.startKeyboard(chatID),
 		)
 
-		if _, err := b.Send(msg); err != nil {
+		if _, err := b.client.Send(msg); err != nil {
 			b.logger.Warn(fmt.Sprintf("send error: %v", err.Error()))
 		}
+	}
+
+	b.answer(query, "")
+}
 
+// answer stops the loading animation on the button and shows the text, if any.
+func (b *Bot) answer(query *tgapi.CallbackQuery, text string) {
+	if _, err := b.client.Request(tgapi.NewCallback(query.ID, text)); err != nil {
+		b.logger.Warn(fmt.Sprintf("answer callback error: %v", err.Error()))
 	}
 }
@@ -0,0 +1,188 @@
+package bot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"password-keeper/internal/bot/callback"
+	"password-keeper/internal/importer"
+	"password-keeper/internal/usecase"
+	"strings"
+	"sync"
+	"time"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// importConflictTTL how long the conflicts of an import wait for the choice.
+const importConflictTTL = 10 * time.Minute
+
+// exportMarker is in every export of the bot, other files come from other password managers.
+var exportMarker = []byte(`"password-keeper-export"`)
+
+// handleImport handles import command: /import [passphrase]
+// in the caption of the file or in the reply to it.
+// The file is deleted right away, exports of other password managers are not encrypted.
+func (b *Bot) handleImport(req *Request) error {
+	doc := document(req.Message)
+	if doc == nil {
+		b.replyText(req, importUsage)
+		return ErrWrongInput
+	}
+
+	if req.Message.Document != nil {
+		b.deleteMessage(MessageInfo{chatID: req.ChatID(), id: req.Message.MessageID})
+	} else {
+		b.deleteMessage(MessageInfo{chatID: req.ChatID(), id: req.Message.ReplyToMessage.MessageID})
+	}
+
+	file, err := b.download(doc)
+	if err != nil {
+		b.replyExportErr(req, err)
+		return fmt.Errorf("import error: %w", err)
+	}
+
+	if bytes.Contains(file, exportMarker) {
+		passphrase := strings.TrimSpace(req.Message.CommandArguments())
+		n, err := b.logic.Import(req.ChatID(), passphrase, file)
+		if err != nil {
+			b.replyExportErr(req, err)
+			return fmt.Errorf("import error: %w", err)
+		}
+
+		b.reply(req, tgapi.NewMessage(req.ChatID(), fmt.Sprintf(b.handleMessageLang(importDone, req.ChatID()), n)))
+		return nil
+	}
+
+	return b.importFile(req, file)
+}
+
+// importFile imports the export of another password manager.
+func (b *Bot) importFile(req *Request, file []byte) error {
+	format, entries, err := importer.Parse(file)
+	if err != nil {
+		b.replyExportErr(req, err)
+		return fmt.Errorf("import error: %w", err)
+	}
+
+	report, err := b.logic.ImportEntries(req.ChatID(), entries)
+	if err != nil {
+		b.replyExportErr(req, err)
+		return fmt.Errorf("import error: %w", err)
+	}
+
+	text := fmt.Sprintf(b.handleMessageLang(importReport, req.ChatID()), format, report.Imported, report.Duplicates)
+	b.reply(req, tgapi.NewMessage(req.ChatID(), text))
+
+	if len(report.Conflicts) == 0 {
+		return nil
+	}
+
+	b.conflicts.put(req.ChatID(), report.Conflicts)
+
+	names := make([]string, 0, len(report.Conflicts))
+	for _, e := range report.Conflicts {
+		names = append(names, e.Service)
+	}
+
+	msgConfig := tgapi.NewMessage(req.ChatID(),
+		fmt.Sprintf(b.handleMessageLang(importConflicts, req.ChatID()), strings.Join(names, "\n")))
+	msgConfig.ReplyMarkup = b.conflictsKeyboard(req.ChatID())
+	b.reply(req, msgConfig)
+	return nil
+}
+
+// conflictsKeyboard returns the keyboard with the ways to resolve the conflicts.
+func (b *Bot) conflictsKeyboard(chatID int64) tgapi.InlineKeyboardMarkup {
+	expires := time.Now().Add(importConflictTTL)
+	button := func(text string, mode usecase.ImportMode) tgapi.InlineKeyboardButton {
+		return b.button(chatID, b.handleMessageLang(text, chatID), callback.Data{
+			Action:  callback.ResolveImport,
+			Arg:     string(mode),
+			Expires: expires,
+		})
+	}
+
+	return tgapi.NewInlineKeyboardMarkup(
+		tgapi.NewInlineKeyboardRow(
+			button(skipButton, usecase.ImportSkip),
+			button(overwriteButton, usecase.ImportOverwrite),
+			button(keepBothButton, usecase.ImportKeepBoth),
+		),
+	)
+}
+
+// resolveImport resolves the conflicts of the last import with the chosen mode.
+// It returns the text for the answer to the button.
+func (b *Bot) resolveImport(query *tgapi.CallbackQuery, mode usecase.ImportMode) string {
+	chatID := query.Message.Chat.ID
+
+	conflicts, ok := b.conflicts.take(chatID)
+	if !ok {
+		return b.handleMessageLang(expiredButtonErr, chatID)
+	}
+
+	n, err := b.logic.ResolveConflicts(chatID, conflicts, mode)
+	if errors.Is(err, usecase.ErrLocked) {
+		return b.lockedMessage(chatID)
+	}
+	if err != nil {
+		b.logger.Warn(fmt.Sprintf("resolve import error: chat %d: %v", chatID, err))
+		return b.handleMessageLang(internalErr, chatID)
+	}
+
+	msg := tgapi.NewEditMessageText(chatID, query.Message.MessageID,
+		fmt.Sprintf(b.handleMessageLang(importResolved, chatID), n))
+	if _, err := b.client.Send(msg); err != nil {
+		b.logger.Warn(fmt.Sprintf("send error: %v", err.Error()))
+	}
+	return ""
+}
+
+// pendingImport conflicts of an import waiting for the choice.
+type pendingImport struct {
+	entries []usecase.Entry
+}
+
+// conflicts keeps the conflicts of the last import of each chat until the choice
+// or importConflictTTL, they contain passwords and mustn't stay in memory.
+type conflicts struct {
+	mu      sync.Mutex
+	pending map[int64]*pendingImport
+}
+
+func newConflicts() *conflicts {
+	return &conflicts{pending: make(map[int64]*pendingImport)}
+}
+
+// put replaces the conflicts of the chat.
+func (c *conflicts) put(chatID int64, entries []usecase.Entry) {
+	p := &pendingImport{entries: entries}
+
+	c.mu.Lock()
+	c.pending[chatID] = p
+	c.mu.Unlock()
+
+	time.AfterFunc(importConflictTTL, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if c.pending[chatID] == p {
+			delete(c.pending, chatID)
+		}
+	})
+}
+
+// take returns and forgets the conflicts of the chat.
+func (c *conflicts) take(chatID int64) ([]usecase.Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, ok := c.pending[chatID]
+	if !ok {
+		return nil, false
+	}
+
+	delete(c.pending, chatID)
+	return p.entries, true
+}
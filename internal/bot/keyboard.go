@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"fmt"
+	"password-keeper/internal/bot/callback"
+	"time"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Lifetimes of the buttons.
+const (
+	// hideButtonTTL the message with the button is deleted by the watcher anyway.
+	hideButtonTTL = 24 * time.Hour
+	// langButtonTTL the start message is kept, so its buttons live longer.
+	langButtonTTL = 30 * 24 * time.Hour
+)
+
+// button returns an inline button with signed callback data.
+func (b *Bot) button(chatID int64, text string, data callback.Data) tgapi.InlineKeyboardButton {
+	encoded, err := b.callbacks.Encode(chatID, data)
+	if err != nil {
+		// the data is built by the bot itself, so it's a bug
+		panic(fmt.Sprintf("encode callback data: %v", err))
+	}
+
+	return tgapi.NewInlineKeyboardButtonData(text, encoded)
+}
+
+// hideKeyboard returns the keyboard deleting the message with it and the messages.
+func (b *Bot) hideKeyboard(chatID int64, messageIDs ...int) tgapi.InlineKeyboardMarkup {
+	return tgapi.NewInlineKeyboardMarkup(
+		tgapi.NewInlineKeyboardRow(
+			b.button(chatID, b.handleMessageLang(hideButton, chatID), callback.Data{
+				Action:   callback.Hide,
+				Messages: messageIDs,
+				Expires:  time.Now().Add(hideButtonTTL),
+			}),
+		),
+	)
+}
+
+// startKeyboard returns the keyboard of the start message.
+func (b *Bot) startKeyboard(chatID int64) tgapi.InlineKeyboardMarkup {
+	return tgapi.NewInlineKeyboardMarkup(
+		tgapi.NewInlineKeyboardRow(
+			b.button(chatID, b.handleMessageLang(changeLangButton, chatID), callback.Data{
+				Action:  callback.ChangeLang,
+				Expires: time.Now().Add(langButtonTTL),
+			}),
+		),
+	)
+}
+
+// setLangKeyboard returns the keyboard with the languages.
+func (b *Bot) setLangKeyboard(chatID int64) tgapi.InlineKeyboardMarkup {
+	expires := time.Now().Add(langButtonTTL)
+	return tgapi.NewInlineKeyboardMarkup(
+		tgapi.NewInlineKeyboardRow(
+			b.button(chatID, russianButton, callback.Data{Action: callback.SetLang, Arg: "ru", Expires: expires}),
+			b.button(chatID, englishButton, callback.Data{Action: callback.SetLang, Arg: "en", Expires: expires}),
+		),
+	)
+}
@@ -0,0 +1,11 @@
+package bot
+
+import "expvar"
+
+// Metrics of the bot, published by expvar at /debug/vars.
+var (
+	// throttledRequests number of commands rejected by the rate limiter.
+	throttledRequests = expvar.NewInt("throttled_requests")
+	// lockedRequests number of lookups rejected because of the brute-force lockout.
+	lockedRequests = expvar.NewInt("locked_requests")
+)
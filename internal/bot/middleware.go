@@ -0,0 +1,201 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Errors returned by the middlewares, so the outer ones can log them.
+var (
+	// ErrWrongInput occurs when the command has a wrong number of arguments.
+	ErrWrongInput = errors.New("wrong input")
+	// ErrForbidden occurs when the command can't be run in the chat.
+	ErrForbidden = errors.New("forbidden")
+	// ErrTooManyRequests occurs when the chat sends commands too often.
+	ErrTooManyRequests = errors.New("too many requests")
+)
+
+// logging logs every command with its duration and error.
+// Arguments are never logged, they contain secrets.
+func (b *Bot) logging(next HandlerFunc) HandlerFunc {
+	return func(req *Request) error {
+		start := time.Now()
+		err := next(req)
+
+		msg := fmt.Sprintf("command /%s from chat %d took %s", req.Command.Name, req.ChatID(), time.Since(start))
+		if err != nil {
+			b.logger.Warn(fmt.Sprintf("%s: %v", msg, err))
+		} else {
+			b.logger.Debug(msg)
+		}
+
+		return err
+	}
+}
+
+// recovery replies with an error instead of crashing when the handler panics.
+func (b *Bot) recovery(next HandlerFunc) HandlerFunc {
+	return func(req *Request) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+				b.reply(req, tgapi.NewMessage(req.ChatID(), b.handleMessageLang(internalErr, req.ChatID())))
+			}
+		}()
+
+		return next(req)
+	}
+}
+
+// accessControl checks that the command may be run in the chat.
+func (b *Bot) accessControl(next HandlerFunc) HandlerFunc {
+	return func(req *Request) error {
+		if req.Command.Access >= accessPrivate && !req.Message.Chat.IsPrivate() {
+			b.reply(req, tgapi.NewMessage(req.ChatID(), b.handleMessageLang(privateOnlyErr, req.ChatID())))
+			return ErrForbidden
+		}
+
+		if req.Command.Access == accessAdmin {
+			from := req.Message.From
+			if from == nil || !b.logic.IsAdmin(from.ID, from.UserName) {
+				// pretend the command doesn't exist
+				return ErrForbidden
+			}
+		}
+
+		return next(req)
+	}
+}
+
+// rateLimit rejects commands from chats which send them too often.
+func (b *Bot) rateLimit(next HandlerFunc) HandlerFunc {
+	return func(req *Request) error {
+		allowed, warn := b.limiter.allow(req.ChatID())
+		if !allowed {
+			throttledRequests.Add(1)
+			if warn {
+				b.reply(req, tgapi.NewMessage(req.ChatID(), b.handleMessageLang(tooManyRequestsErr, req.ChatID())))
+			}
+			return ErrTooManyRequests
+		}
+
+		return next(req)
+	}
+}
+
+// autoDelete queues the command and the replies for deletion.
+func (b *Bot) autoDelete(next HandlerFunc) HandlerFunc {
+	return func(req *Request) error {
+		err := next(req)
+		if req.Command.Keep {
+			return err
+		}
+
+		b.hideLater(req.Message)
+		for i := range req.sent {
+			b.hideLater(&req.sent[i])
+		}
+
+		return err
+	}
+}
+
+// validateArgs checks the number of the command arguments.
+func (b *Bot) validateArgs(next HandlerFunc) HandlerFunc {
+	return func(req *Request) error {
+		n := len(req.Args)
+		if n < req.Command.MinArgs || (req.Command.MaxArgs >= 0 && n > req.Command.MaxArgs) {
+			b.reply(req, tgapi.NewMessage(req.ChatID(), b.handleMessageLang(wrongInputErr, req.ChatID())))
+			return ErrWrongInput
+		}
+
+		return next(req)
+	}
+}
+
+// Default limits of the commands per chat.
+const (
+	commandsPerSecond = 1
+	commandsBurst     = 10
+)
+
+// maxBuckets number of buckets after which the full ones are dropped.
+const maxBuckets = 10000
+
+// limiter is a token bucket per chat.
+type limiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[int64]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// warned is set when the chat was told to slow down,
+	// so it isn't flooded with warnings while throttled.
+	warned bool
+}
+
+// newLimiter creates a limiter allowing burst commands at once
+// and rate commands per second after that.
+func newLimiter(rate float64, burst int) *limiter {
+	return &limiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[int64]*bucket),
+	}
+}
+
+// allow takes a token from the bucket of the chat.
+// warn is true for the first rejected request in a row.
+func (l *limiter) allow(chatID int64) (allowed, warn bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	bk, ok := l.buckets[chatID]
+	if !ok {
+		if len(l.buckets) >= maxBuckets {
+			l.cleanup(now)
+		}
+		bk = &bucket{tokens: l.burst, last: now}
+		l.buckets[chatID] = bk
+	}
+
+	bk.refill(now, l.rate, l.burst)
+
+	if bk.tokens < 1 {
+		warn = !bk.warned
+		bk.warned = true
+		return false, warn
+	}
+
+	bk.tokens--
+	bk.warned = false
+	return true, false
+}
+
+// cleanup drops the full buckets, they are the same as new ones.
+func (l *limiter) cleanup(now time.Time) {
+	for id, bk := range l.buckets {
+		bk.refill(now, l.rate, l.burst)
+		if bk.tokens >= l.burst {
+			delete(l.buckets, id)
+		}
+	}
+}
+
+func (bk *bucket) refill(now time.Time, rate, burst float64) {
+	bk.tokens += now.Sub(bk.last).Seconds() * rate
+	if bk.tokens > burst {
+		bk.tokens = burst
+	}
+	bk.last = now
+}
@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"fmt"
+	"password-keeper/internal/bot/callback"
+	"strings"
+	"time"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// dataDateFormat format of the dates of the data report.
+const dataDateFormat = "2006-01-02"
+
+// forgetButtonTTL time to press the button of /forgetme.
+const forgetButtonTTL = 2 * time.Minute
+
+// handleMyData handles mydata command, it shows what is stored about the chat.
+func (b *Bot) handleMyData(req *Request) error {
+	chatID := req.ChatID()
+	d, err := b.logic.MyData(chatID)
+	if err != nil {
+		b.replyText(req, internalErr)
+		return fmt.Errorf("mydata error: %w", err)
+	}
+
+	msg := func(key string, args ...interface{}) string {
+		return fmt.Sprintf(b.handleMessageLang(key, chatID), args...)
+	}
+
+	lines := []string{msg(mydataTitle), msg(mydataServices, d.Services)}
+	if !d.FirstSaved.IsZero() {
+		lines[1] += msg(mydataServicesTimes, d.FirstSaved.UTC().Format(dataDateFormat), d.LastUpdated.UTC().Format(dataDateFormat))
+	}
+	lines = append(lines,
+		msg(mydataShares, d.SharesGiven, d.SharesReceived),
+		msg(mydataOneTime, d.OneTime),
+		msg(mydataFiles, d.Files),
+		msg(mydataVaults, d.Vaults),
+	)
+
+	switch {
+	case d.Lang == "":
+		lines = append(lines, msg(mydataLangNone))
+	case d.LangAuto:
+		lines = append(lines, msg(mydataLangAuto, d.Lang))
+	default:
+		lines = append(lines, msg(mydataLang, d.Lang))
+	}
+
+	if d.Rotation > 0 {
+		lines = append(lines, msg(mydataRotation, days(d.Rotation)))
+	} else {
+		lines = append(lines, msg(mydataRotationOff))
+	}
+
+	lines = append(lines, msg(mydataDisplay, b.handleMessageLang(displayLabels[d.Display], chatID)))
+
+	if d.DuressPIN {
+		lines = append(lines, msg(mydataDuressOn))
+	} else {
+		lines = append(lines, msg(mydataDuressOff))
+	}
+
+	if d.Member != nil {
+		lines = append(lines, msg(mydataMember, d.Member.Role, d.Member.CreatedAt.UTC().Format(dataDateFormat)))
+	}
+
+	switch {
+	case d.AuditRecords == 0:
+		lines = append(lines, msg(mydataAuditNone))
+	case d.AuditRetention > 0:
+		lines = append(lines, msg(mydataAudit, d.AuditRecords, d.AuditSince.UTC().Format(dataDateFormat), days(d.AuditRetention)))
+	default:
+		lines = append(lines, msg(mydataAuditForever, d.AuditRecords, d.AuditSince.UTC().Format(dataDateFormat)))
+	}
+	lines = append(lines, "", msg(mydataFooter))
+
+	msgConfig := tgapi.NewMessage(chatID, strings.Join(lines, "\n"))
+	msgConfig.ReplyMarkup = b.hideKeyboard(chatID, req.Message.MessageID)
+	b.reply(req, msgConfig)
+	return nil
+}
+
+// handleForgetMe handles forgetme command, it warns and shows the button deleting everything.
+func (b *Bot) handleForgetMe(req *Request) error {
+	chatID := req.ChatID()
+	msgConfig := tgapi.NewMessage(chatID, b.handleMessageLang(forgetWarning, chatID))
+	msgConfig.ReplyMarkup = tgapi.NewInlineKeyboardMarkup(tgapi.NewInlineKeyboardRow(
+		b.button(chatID, b.handleMessageLang(forgetButton, chatID),
+			callback.Data{Action: callback.ForgetMe, Expires: time.Now().Add(forgetButtonTTL)}),
+	))
+	b.reply(req, msgConfig)
+	return nil
+}
+
+// forgetMe handles the button of /forgetme: everything stored about the chat is deleted
+// with the state of the chat kept by the bot, see forgetChat.
+// It returns the text for the answer to the button.
+func (b *Bot) forgetMe(query *tgapi.CallbackQuery) string {
+	chatID := query.Message.Chat.ID
+
+	// the language is deleted too, so the reply is localized before
+	text := b.handleMessageLang(forgotten, chatID)
+	if err := b.logic.ForgetMe(chatID); err != nil {
+		return b.handleMessageLang(internalErr, chatID)
+	}
+
+	b.forgetChat(chatID)
+	b.deleteMessage(MessageInfo{chatID: chatID, id: query.Message.MessageID})
+
+	if _, err := b.client.Send(tgapi.NewMessage(chatID, text)); err != nil {
+		b.logger.Warn(fmt.Sprintf("send error: %v", err.Error()))
+	}
+	return ""
+}
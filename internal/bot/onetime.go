@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"password-keeper/internal/onetime"
+	"password-keeper/internal/storage"
+	"password-keeper/internal/usecase"
+	"time"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Lifetimes of one-time links.
+const (
+	defaultOneTimeTTL = 24 * time.Hour
+	maxOneTimeTTL     = 7 * 24 * time.Hour
+)
+
+// handleOneTime handles onetime command: /onetime service [ttl].
+func (b *Bot) handleOneTime(req *Request) error {
+	if b.oneTimeURL == "" {
+		b.replyText(req, oneTimeDisabledErr)
+		return nil
+	}
+
+	ttl := defaultOneTimeTTL
+	if len(req.Args) == 2 {
+		d, err := parseTTL(req.Args[1])
+		if err != nil || d > maxOneTimeTTL {
+			b.replyText(req, wrongInputErr)
+			return ErrWrongInput
+		}
+		ttl = d
+	}
+
+	service := req.Args[0]
+	id, key, err := b.logic.CreateOneTime(req.ChatID(), service, ttl)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			b.replyText(req, serviceNotFoundErr)
+		case errors.Is(err, usecase.ErrLocked):
+			b.reply(req, tgapi.NewMessage(req.ChatID(), b.lockedMessage(req.ChatID())))
+		default:
+			b.replyText(req, internalErr)
+		}
+		return fmt.Errorf("onetime error: %w", err)
+	}
+
+	link := b.oneTimeURL + onetime.Path + id + "#" + key
+	until := time.Now().Add(ttl).UTC().Format("2006-01-02 15:04 UTC")
+
+	msgConfig := tgapi.NewMessage(req.ChatID(), fmt.Sprintf(b.handleMessageLang(oneTime, req.ChatID()), service, until, link))
+	// the preview is harmless, but there is no point in it
+	msgConfig.DisableWebPagePreview = true
+	msgConfig.ReplyMarkup = b.hideKeyboard(req.ChatID(), req.Message.MessageID)
+
+	b.reply(req, msgConfig)
+	return nil
+}
+
+// NotifyOneTimeOpened tells the owner that the one-time link to the service was opened.
+func (b *Bot) NotifyOneTimeOpened(owner int64, service string) {
+	text := fmt.Sprintf(b.handleMessageLang(oneTimeOpened, owner), service)
+	if _, err := b.client.Send(tgapi.NewMessage(owner, text)); err != nil {
+		b.logger.Warn(fmt.Sprintf("one-time notice error: chat %d: %v", owner, err))
+	}
+}
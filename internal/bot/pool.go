@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+)
+
+// defaultWorkers number of workers handling updates by default.
+const defaultWorkers = 8
+
+// queueSize capacity of the queue of each worker.
+const queueSize = 100
+
+// pool handles updates concurrently.
+// Updates from the same chat always go to the same worker,
+// so they are handled in the order they were received.
+type pool struct {
+	queues []chan tgapi.Update
+	handle func(tgapi.Update)
+	logger *zap.Logger
+	wg     sync.WaitGroup
+}
+
+// newPool creates a pool and starts its workers.
+func newPool(workers int, handle func(tgapi.Update), logger *zap.Logger) *pool {
+	if workers < 1 {
+		workers = 1
+	}
+
+	p := &pool{
+		queues: make([]chan tgapi.Update, workers),
+		handle: handle,
+		logger: logger,
+	}
+
+	for i := range p.queues {
+		p.queues[i] = make(chan tgapi.Update, queueSize)
+		p.wg.Add(1)
+		go p.work(p.queues[i])
+	}
+
+	return p
+}
+
+// push adds the update to the queue of the worker responsible for its chat.
+func (p *pool) push(update tgapi.Update) {
+	p.queues[p.shard(update)] <- update
+}
+
+// stop waits until all queued updates are handled and stops the workers.
+func (p *pool) stop() {
+	for _, q := range p.queues {
+		close(q)
+	}
+	p.wg.Wait()
+}
+
+// shard returns the index of the worker queue for the update.
+func (p *pool) shard(update tgapi.Update) int {
+	var id int64
+	if chat := update.FromChat(); chat != nil {
+		id = chat.ID
+	} else if from := update.SentFrom(); from != nil {
+		id = from.ID
+	}
+
+	if id < 0 {
+		id = -id
+	}
+
+	return int(id % int64(len(p.queues)))
+}
+
+func (p *pool) work(queue <-chan tgapi.Update) {
+	defer p.wg.Done()
+
+	for update := range queue {
+		p.safeHandle(update)
+	}
+}
+
+// safeHandle handles the update and recovers from a panic in the handler.
+func (p *pool) safeHandle(update tgapi.Update) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Error(fmt.Sprintf("panic while handling update %d: %v\n%s", update.UpdateID, r, debug.Stack()))
+		}
+	}()
+
+	p.handle(update)
+}
@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"sync"
+	"testing"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+)
+
+func newUpdate(id int, chatID int64) tgapi.Update {
+	return tgapi.Update{
+		UpdateID: id,
+		Message: &tgapi.Message{
+			MessageID: id,
+			Chat:      &tgapi.Chat{ID: chatID},
+		},
+	}
+}
+
+func TestPool_order(t *testing.T) {
+	var mu sync.Mutex
+	handled := make(map[int64][]int)
+
+	p := newPool(4, func(update tgapi.Update) {
+		mu.Lock()
+		defer mu.Unlock()
+		chatID := update.Message.Chat.ID
+		handled[chatID] = append(handled[chatID], update.UpdateID)
+	}, zap.NewNop())
+
+	chats := []int64{1, 2, 3, -100500, 42}
+	for i := 0; i < 1000; i++ {
+		p.push(newUpdate(i, chats[i%len(chats)]))
+	}
+	p.stop()
+
+	total := 0
+	for chatID, ids := range handled {
+		total += len(ids)
+		for i := 1; i < len(ids); i++ {
+			if ids[i-1] > ids[i] {
+				t.Errorf("chat %d: update %d handled before %d", chatID, ids[i-1], ids[i])
+			}
+		}
+	}
+
+	if total != 1000 {
+		t.Errorf("handled %d updates, want %d", total, 1000)
+	}
+}
+
+func TestPool_panic(t *testing.T) {
+	var mu sync.Mutex
+	var handled []int
+
+	p := newPool(1, func(update tgapi.Update) {
+		if update.UpdateID == 1 {
+			panic("bad update")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, update.UpdateID)
+	}, zap.NewNop())
+
+	for i := 0; i < 3; i++ {
+		p.push(newUpdate(i, 1))
+	}
+	p.stop()
+
+	if len(handled) != 2 || handled[0] != 0 || handled[1] != 2 {
+		t.Errorf("handled = %v, want %v", handled, []int{0, 2})
+	}
+}
@@ -0,0 +1,244 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"password-keeper/internal/bot/callback"
+	"password-keeper/internal/entity"
+	"password-keeper/internal/storage"
+	"password-keeper/internal/usecase"
+	"sync"
+	"time"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Ways to reveal the masked password, the arguments of the reveal buttons.
+const (
+	revealShow  = "show"
+	revealTimed = "timed"
+	revealSend  = "send"
+)
+
+const (
+	// revealShowFor how long the password is revealed by the timed button
+	// and how long the separate message with the password lives.
+	revealShowFor = 10 * time.Second
+	// revealButtonTTL the masked message is deleted by the watcher anyway.
+	revealButtonTTL = hideButtonTTL
+)
+
+// reveal is the masked entry of a message. The password is looked up again on each reveal,
+// so the lookup is checked for the lockout and recorded in the audit log.
+type reveal struct {
+	service string
+	lookup  func() (entity.Pair, error)
+	// text of the masked message and hide the keyboard of the message without the reveal buttons.
+	text    string
+	hide    tgapi.InlineKeyboardMarkup
+	expires time.Time
+}
+
+type revealKey struct {
+	chatID    int64
+	messageID int
+}
+
+// reveals masked messages waiting for the reveal buttons, until the buttons expire,
+// and the timers masking the timed reveals back.
+type reveals struct {
+	mu      sync.Mutex
+	pending map[revealKey]reveal
+	timers  map[revealKey]*time.Timer
+}
+
+func newReveals() *reveals {
+	return &reveals{pending: make(map[revealKey]reveal), timers: make(map[revealKey]*time.Timer)}
+}
+
+// put remembers the masked message for revealButtonTTL, the expired ones are forgotten.
+func (r *reveals) put(chatID int64, messageID int, masked reveal) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for key, m := range r.pending {
+		if now.After(m.expires) {
+			delete(r.pending, key)
+		}
+	}
+
+	masked.expires = now.Add(revealButtonTTL)
+	r.pending[revealKey{chatID: chatID, messageID: messageID}] = masked
+}
+
+// refresh extends the masked message for revealButtonTTL when its buttons are issued again.
+func (r *reveals) refresh(chatID int64, messageID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := revealKey{chatID: chatID, messageID: messageID}
+	if masked, ok := r.pending[key]; ok {
+		masked.expires = time.Now().Add(revealButtonTTL)
+		r.pending[key] = masked
+	}
+}
+
+// forget forgets the masked messages of the chat and stops their timers.
+func (r *reveals) forget(chatID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.pending {
+		if key.chatID == chatID {
+			delete(r.pending, key)
+		}
+	}
+	for key, timer := range r.timers {
+		if key.chatID == chatID {
+			timer.Stop()
+			delete(r.timers, key)
+		}
+	}
+}
+
+// after runs f after d unless it is replaced by another call for the same message before,
+// so the taps on the timed button mask the message back once, after the last tap.
+func (r *reveals) after(chatID int64, messageID int, d time.Duration, f func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := revealKey{chatID: chatID, messageID: messageID}
+	if timer, ok := r.timers[key]; ok {
+		timer.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(d, func() {
+		r.mu.Lock()
+		current := r.timers[key] == timer
+		if current {
+			delete(r.timers, key)
+		}
+		r.mu.Unlock()
+
+		if current {
+			f()
+		}
+	})
+	r.timers[key] = timer
+}
+
+// get returns the masked message when its buttons haven't expired.
+func (r *reveals) get(chatID int64, messageID int) (reveal, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	masked, ok := r.pending[revealKey{chatID: chatID, messageID: messageID}]
+	return masked, ok && time.Now().Before(masked.expires)
+}
+
+// maskedMessage returns the masked entry with the reveal buttons.
+func (b *Bot) maskedMessage(chatID int64, masked reveal) tgapi.MessageConfig {
+	msgConfig := tgapi.NewMessage(chatID, masked.text)
+	msgConfig.ParseMode = tgapi.ModeHTML
+	msgConfig.ReplyMarkup = b.revealKeyboard(chatID, masked.hide)
+	return msgConfig
+}
+
+// replyMasked replies with the masked entry, the password is revealed with the buttons.
+func (b *Bot) replyMasked(req *Request, masked reveal) {
+	n := len(req.sent)
+	b.reply(req, b.maskedMessage(req.ChatID(), masked))
+	if len(req.sent) > n {
+		b.reveals.put(req.ChatID(), req.sent[n].MessageID, masked)
+	}
+}
+
+// revealKeyboard returns the reveal buttons above the rows of the hide keyboard.
+func (b *Bot) revealKeyboard(chatID int64, hide tgapi.InlineKeyboardMarkup) tgapi.InlineKeyboardMarkup {
+	expires := time.Now().Add(revealButtonTTL)
+	button := func(text, how string) tgapi.InlineKeyboardButton {
+		return b.button(chatID, text, callback.Data{Action: callback.Reveal, Arg: how, Expires: expires})
+	}
+
+	rows := [][]tgapi.InlineKeyboardButton{
+		tgapi.NewInlineKeyboardRow(
+			button(b.handleMessageLang(showPasswordButton, chatID), revealShow),
+			button(fmt.Sprintf(b.handleMessageLang(showForButton, chatID), int(b.revealFor.Seconds())), revealTimed),
+		),
+		tgapi.NewInlineKeyboardRow(
+			button(b.handleMessageLang(sendPasswordButton, chatID), revealSend),
+		),
+	}
+	return tgapi.NewInlineKeyboardMarkup(append(rows, hide.InlineKeyboard...)...)
+}
+
+// revealPassword reveals the password of the masked message of the query:
+// in place, in place until b.revealFor passes or in a separate message deleted after b.revealFor.
+// The separate message is queued on the watcher, so it's deleted on shutdown too,
+// and the timed one isn't masked back after the bot quits, the watcher deletes it anyway.
+// The masked message keeps one timer, another tap restarts it.
+// It returns the text for the answer to the button.
+func (b *Bot) revealPassword(query *tgapi.CallbackQuery, how string) string {
+	chatID, messageID := query.Message.Chat.ID, query.Message.MessageID
+	masked, ok := b.reveals.get(chatID, messageID)
+	if !ok {
+		return b.handleMessageLang(expiredButtonErr, chatID)
+	}
+
+	pair, err := masked.lookup()
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return b.handleMessageLang(serviceNotFoundErr, chatID)
+	case errors.Is(err, usecase.ErrLocked):
+		return b.lockedMessage(chatID)
+	case err != nil:
+		b.logger.Warn(fmt.Sprintf("reveal error: chat %d: %v", chatID, err))
+		return b.handleMessageLang(internalErr, chatID)
+	}
+
+	revealed := b.credentials(chatID, displayRevealed, masked.service, pair)
+	switch how {
+	case revealShow:
+		b.editMasked(chatID, messageID, revealed, masked.hide)
+	case revealTimed:
+		b.editMasked(chatID, messageID, revealed, masked.hide)
+		b.reveals.after(chatID, messageID, b.revealFor, func() {
+			select {
+			case <-b.quit:
+				return
+			default:
+			}
+
+			// the buttons are issued again, so the message can be revealed until they expire
+			b.editMasked(chatID, messageID, masked.text, b.revealKeyboard(chatID, masked.hide))
+			b.reveals.refresh(chatID, messageID)
+		})
+	case revealSend:
+		msgConfig := tgapi.NewMessage(chatID, fmt.Sprintf(b.handleMessageLang(revealedPassword, chatID),
+			escapeHTML(masked.service), monospace(pair.Password), int(b.revealFor.Seconds())))
+		msgConfig.ParseMode = tgapi.ModeHTML
+		msgConfig.ReplyMarkup = b.hideKeyboard(chatID)
+
+		m, err := b.client.Send(msgConfig)
+		if err != nil {
+			b.logger.Warn(fmt.Sprintf("send error: %v", err.Error()))
+			return b.handleMessageLang(internalErr, chatID)
+		}
+		b.hideAfter(&m, b.revealFor)
+	default:
+		b.logger.Warn(fmt.Sprintf("callback error: unknown reveal %q", how))
+		return b.handleMessageLang(internalErr, chatID)
+	}
+	return ""
+}
+
+// editMasked replaces the text and the keyboard of the masked message.
+func (b *Bot) editMasked(chatID int64, messageID int, text string, markup tgapi.InlineKeyboardMarkup) {
+	msg := tgapi.NewEditMessageTextAndMarkup(chatID, messageID, text, markup)
+	msg.ParseMode = tgapi.ModeHTML
+	if _, err := b.client.Send(msg); err != nil {
+		b.logger.Warn(fmt.Sprintf("send error: %v", err.Error()))
+	}
+}
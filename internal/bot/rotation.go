@@ -0,0 +1,199 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"password-keeper/internal/bot/callback"
+	"password-keeper/internal/storage"
+	"password-keeper/internal/usecase"
+	"strings"
+	"time"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// rotationButtonTTL the reminder is repeated after usecase.RemindRepeat anyway.
+const rotationButtonTTL = usecase.RemindRepeat
+
+// rotationOffArg argument of /rotate turning the reminders off.
+const rotationOffArg = "off"
+
+// handleRotate handles rotate command: /rotate [service] [period|off].
+func (b *Bot) handleRotate(req *Request) error {
+	chatID := req.ChatID()
+	if len(req.Args) == 0 {
+		period, err := b.logic.Rotation(chatID)
+		if err != nil {
+			b.replyText(req, internalErr)
+			return fmt.Errorf("rotate error: %w", err)
+		}
+
+		if period == 0 {
+			b.replyText(req, rotationOff)
+			return nil
+		}
+		b.reply(req, tgapi.NewMessage(chatID, fmt.Sprintf(b.handleMessageLang(rotationStatus, chatID), days(period))))
+		return nil
+	}
+
+	var period time.Duration
+	if arg := req.Args[len(req.Args)-1]; !strings.EqualFold(arg, rotationOffArg) {
+		d, err := parseTTL(arg)
+		if err != nil {
+			b.replyText(req, wrongInputErr)
+			return ErrWrongInput
+		}
+		period = d
+	}
+
+	var text string
+	var err error
+	if len(req.Args) == 1 {
+		err = b.logic.SetRotation(chatID, period)
+		text = b.handleMessageLang(rotationDisabled, chatID)
+		if period > 0 {
+			text = fmt.Sprintf(b.handleMessageLang(rotationSet, chatID), days(period))
+		}
+	} else {
+		service := req.Args[0]
+		if period == 0 {
+			period = -1
+		}
+		err = b.logic.SetEntryRotation(chatID, service, period)
+		text = fmt.Sprintf(b.handleMessageLang(rotationEntryDisabled, chatID), service)
+		if period > 0 {
+			text = fmt.Sprintf(b.handleMessageLang(rotationEntrySet, chatID), service, days(period))
+		}
+	}
+
+	switch {
+	case errors.Is(err, usecase.ErrRotationPeriod):
+		b.replyText(req, rotationPeriodErr)
+		return nil
+	case errors.Is(err, storage.ErrNotFound):
+		b.replyText(req, serviceNotFoundErr)
+		return nil
+	case errors.Is(err, usecase.ErrLocked):
+		b.reply(req, tgapi.NewMessage(chatID, b.lockedMessage(chatID)))
+		return nil
+	case err != nil:
+		b.replyText(req, internalErr)
+		return fmt.Errorf("rotate error: %w", err)
+	}
+
+	b.reply(req, tgapi.NewMessage(chatID, text))
+	return nil
+}
+
+// days returns the period in whole days.
+func days(d time.Duration) int {
+	return int(d / usecase.MinRotation)
+}
+
+// remindRotations sends the reminders to the chats with passwords older than the rotation period.
+// The reminders are repeated after usecase.RemindRepeat until the passwords are changed.
+func (b *Bot) remindRotations() {
+	due, err := b.logic.DueRotations()
+	if err != nil {
+		return
+	}
+
+	for chatID, services := range due {
+		if !b.sendReminder(chatID, services) {
+			continue
+		}
+
+		if err := b.logic.RemindLater(chatID, usecase.RemindRepeat); err != nil {
+			b.logger.Warn(fmt.Sprintf("rotation reminder error: chat %d: %v", chatID, err))
+		}
+	}
+}
+
+// sendReminder sends the reminder about the services and returns true on success.
+func (b *Bot) sendReminder(chatID int64, services []string) bool {
+	parts := splitMessage(fmt.Sprintf(b.handleMessageLang(rotationReminder, chatID), bulletList(services)))
+	for i, part := range parts {
+		msg := tgapi.NewMessage(chatID, part)
+		if i == len(parts)-1 {
+			msg.ReplyMarkup = b.rotationKeyboard(chatID)
+		}
+
+		if _, err := b.client.Send(msg); err != nil {
+			b.logger.Warn(fmt.Sprintf("rotation reminder error: chat %d: %v", chatID, err))
+			return false
+		}
+	}
+	return true
+}
+
+// rotationKeyboard returns the keyboard of the rotation reminder.
+func (b *Bot) rotationKeyboard(chatID int64) tgapi.InlineKeyboardMarkup {
+	expires := time.Now().Add(rotationButtonTTL)
+	return tgapi.NewInlineKeyboardMarkup(
+		tgapi.NewInlineKeyboardRow(
+			b.button(chatID, b.handleMessageLang(snoozeButton, chatID), callback.Data{Action: callback.SnoozeRotation, Expires: expires}),
+			b.button(chatID, b.handleMessageLang(generateButton, chatID), callback.Data{Action: callback.GenerateRotation, Expires: expires}),
+		),
+	)
+}
+
+// snoozeRotation snoozes the reminders to the chat of the query.
+// It returns the text for the answer to the button.
+func (b *Bot) snoozeRotation(query *tgapi.CallbackQuery) string {
+	chatID := query.Message.Chat.ID
+	if err := b.logic.RemindLater(chatID, usecase.SnoozePeriod); err != nil {
+		return b.handleMessageLang(internalErr, chatID)
+	}
+
+	msg := tgapi.NewEditMessageText(chatID, query.Message.MessageID,
+		fmt.Sprintf(b.handleMessageLang(rotationSnoozed, chatID), days(usecase.SnoozePeriod)))
+	if _, err := b.client.Send(msg); err != nil {
+		b.logger.Warn(fmt.Sprintf("send error: %v", err.Error()))
+	}
+	return ""
+}
+
+// generateRotation sends new passwords for the services due to rotation, they are hidden later.
+// It returns the text for the answer to the button.
+func (b *Bot) generateRotation(query *tgapi.CallbackQuery) string {
+	chatID := query.Message.Chat.ID
+	services, err := b.logic.DueServices(chatID)
+	if err != nil {
+		return b.handleMessageLang(internalErr, chatID)
+	}
+	if len(services) == 0 {
+		return b.handleMessageLang(rotationNothingDue, chatID)
+	}
+
+	lines := make([]string, 0, len(services))
+	for _, service := range services {
+		password, err := usecase.GeneratePassword()
+		if err != nil {
+			b.logger.Warn(fmt.Sprintf("generate error: chat %d: %v", chatID, err))
+			return b.handleMessageLang(internalErr, chatID)
+		}
+		lines = append(lines, service+": "+password)
+	}
+
+	text := fmt.Sprintf(b.handleMessageLang(rotationGenerated, chatID), strings.Join(lines, "\n"))
+	parts := splitMessage(text)
+	for i, part := range parts {
+		msg := tgapi.NewMessage(chatID, part)
+		if i == len(parts)-1 {
+			msg.ReplyMarkup = b.hideKeyboard(chatID)
+		}
+
+		m, err := b.client.Send(msg)
+		if err != nil {
+			b.logger.Warn(fmt.Sprintf("send error: %v", err.Error()))
+			return b.handleMessageLang(internalErr, chatID)
+		}
+		b.hideLater(&m)
+	}
+	return ""
+}
+
+// bulletList returns the items as a list, one per line.
+func bulletList(items []string) string {
+	return "• " + strings.Join(items, "\n• ")
+}
@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"strings"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// access defines who may run a command.
+type access int
+
+const (
+	// accessPublic commands can be run in any chat.
+	accessPublic access = iota
+	// accessPrivate commands can be run in private chats only, they deal with secrets.
+	accessPrivate
+	// accessAdmin commands can be run by admins in private chats only.
+	accessAdmin
+)
+
+// HandlerFunc handles a command.
+// Replies are sent with Bot.reply, the returned error is logged.
+type HandlerFunc func(req *Request) error
+
+// Middleware wraps a handler with common behavior.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Command describes a bot command.
+type Command struct {
+	Name        string
+	Description messages
+	// MinArgs and MaxArgs limit the number of arguments, MaxArgs < 0 means no limit.
+	MinArgs int
+	MaxArgs int
+	Access  access
+	// Keep disables the deletion of the command and the replies.
+	Keep bool
+	// Hidden commands are not shown in the menu.
+	Hidden  bool
+	Handler HandlerFunc
+}
+
+// Request is a command sent by the user.
+type Request struct {
+	Message *tgapi.Message
+	Command *Command
+	Args    []string
+
+	// sent messages replied to the request.
+	sent []tgapi.Message
+}
+
+// ChatID returns the chat the request came from.
+func (r *Request) ChatID() int64 {
+	return r.Message.Chat.ID
+}
+
+// router passes commands to their handlers through the middleware chain.
+type router struct {
+	commands    map[string]*Command
+	order       []string
+	middlewares []Middleware
+}
+
+func newRouter() *router {
+	return &router{commands: make(map[string]*Command)}
+}
+
+// register adds the commands to the router.
+func (r *router) register(commands ...Command) {
+	for i := range commands {
+		cmd := commands[i]
+		if _, ok := r.commands[cmd.Name]; !ok {
+			r.order = append(r.order, cmd.Name)
+		}
+		r.commands[cmd.Name] = &cmd
+	}
+}
+
+// use adds middlewares to the chain. The first one is the outermost.
+func (r *router) use(middlewares ...Middleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// list returns the registered commands in the order of registration.
+func (r *router) list() []*Command {
+	list := make([]*Command, 0, len(r.order))
+	for _, name := range r.order {
+		list = append(list, r.commands[name])
+	}
+	return list
+}
+
+// handle runs the handler of the command. Unknown commands are ignored.
+func (r *router) handle(msg *tgapi.Message) {
+	cmd, ok := r.commands[msg.Command()]
+	if !ok {
+		return
+	}
+
+	h := cmd.Handler
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		h = r.middlewares[i](h)
+	}
+
+	_ = h(&Request{
+		Message: msg,
+		Command: cmd,
+		Args:    strings.Fields(msg.CommandArguments()),
+	})
+}
@@ -0,0 +1,182 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"password-keeper/internal/bot/telegramtest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestRouter_handle(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(req *Request) error {
+				calls = append(calls, name)
+				return next(req)
+			}
+		}
+	}
+
+	var got *Request
+	r := newRouter()
+	r.use(trace("first"), trace("second"))
+	r.register(Command{
+		Name:    "test",
+		MaxArgs: -1,
+		Handler: func(req *Request) error {
+			calls = append(calls, "handler")
+			got = req
+			return nil
+		},
+	})
+
+	r.handle(&tgapi.Message{
+		Text:     "/test  a b   c",
+		Chat:     &tgapi.Chat{ID: 1},
+		Entities: []tgapi.MessageEntity{{Type: "bot_command", Length: 5}},
+	})
+
+	if want := []string{"first", "second", "handler"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	if got == nil || !reflect.DeepEqual(got.Args, []string{"a", "b", "c"}) {
+		t.Errorf("request = %+v, want args [a b c]", got)
+	}
+
+	calls = nil
+	r.handle(&tgapi.Message{
+		Text:     "/unknown",
+		Chat:     &tgapi.Chat{ID: 1},
+		Entities: []tgapi.MessageEntity{{Type: "bot_command", Length: 8}},
+	})
+
+	if len(calls) != 0 {
+		t.Errorf("unknown command: calls = %v, want none", calls)
+	}
+}
+
+func TestBot_validateArgs(t *testing.T) {
+	b, api := startBot(t)
+
+	tests := []struct {
+		name    string
+		cmd     Command
+		args    []string
+		wantErr error
+	}{
+		{
+			name: "ok",
+			cmd:  Command{MinArgs: 1, MaxArgs: 2},
+			args: []string{"a", "b"},
+		},
+		{
+			name: "no limit",
+			cmd:  Command{MaxArgs: -1},
+			args: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:    "too few",
+			cmd:     Command{MinArgs: 1, MaxArgs: 2},
+			wantErr: ErrWrongInput,
+		},
+		{
+			name:    "too many",
+			cmd:     Command{MinArgs: 1, MaxArgs: 2},
+			args:    []string{"a", "b", "c"},
+			wantErr: ErrWrongInput,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := b.validateArgs(func(req *Request) error { return nil })
+			req := &Request{
+				Message: &tgapi.Message{Chat: &tgapi.Chat{ID: 3001}},
+				Command: &tt.cmd,
+				Args:    tt.args,
+			}
+
+			if err := h(req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if sent := api.Requests("sendMessage"); len(sent) != 2 {
+		t.Errorf("sent %d replies, want %d", len(sent), 2)
+	}
+}
+
+func TestBot_recovery(t *testing.T) {
+	api := telegramtest.NewServer()
+	defer api.Close()
+
+	b := newBot(t, api, time.Hour)
+	b.router.register(Command{
+		Name:    "boom",
+		MaxArgs: -1,
+		Handler: func(req *Request) error { panic("boom") },
+	})
+
+	started := make(chan error, 1)
+	go func() {
+		started <- b.Start()
+	}()
+
+	api.PushUpdate(telegramtest.Command(3101, 10, "/boom svc login password"))
+	sent, err := api.WaitRequests("sendMessage", 1, waitTimeout)
+	if err != nil {
+		t.Fatalf("WaitRequests() error = %v", err)
+	}
+	if sent[0].Text() != internalErrEN {
+		t.Errorf("reply = %q, want %q", sent[0].Text(), internalErrEN)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout)
+	defer cancel()
+
+	if err := b.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if err := <-started; err != nil {
+		t.Errorf("Start() error = %v", err)
+	}
+
+	// the command with the password and the error reply are deleted anyway
+	var deleted []string
+	for _, r := range api.Requests("deleteMessage") {
+		deleted = append(deleted, fmt.Sprintf("%d/%d", r.ChatID(), r.MessageID()))
+	}
+	if want := []string{"3101/10", "3101/1001"}; strings.Join(deleted, ",") != strings.Join(want, ",") {
+		t.Errorf("deleted = %v, want %v", deleted, want)
+	}
+}
+
+func TestLimiter_allow(t *testing.T) {
+	l := newLimiter(0.001, 3)
+
+	for i := 0; i < 3; i++ {
+		if allowed, _ := l.allow(1); !allowed {
+			t.Fatalf("allow() = false on request %d, want true", i+1)
+		}
+	}
+
+	if allowed, warn := l.allow(1); allowed || !warn {
+		t.Errorf("allow() = %v, %v after burst, want false, true", allowed, warn)
+	}
+
+	if allowed, warn := l.allow(1); allowed || warn {
+		t.Errorf("allow() = %v, %v when throttled, want false, false", allowed, warn)
+	}
+
+	if allowed, _ := l.allow(2); !allowed {
+		t.Errorf("allow() = false for another chat, want true")
+	}
+}
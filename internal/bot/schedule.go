@@ -0,0 +1,27 @@
+package bot
+
+import (
+	"fmt"
+	"time"
+)
+
+// scheduleInterval how often the periodic jobs run.
+const scheduleInterval = time.Hour
+
+// schedule runs the periodic jobs every scheduleInterval until Shutdown.
+func (b *Bot) schedule() {
+	ticker := time.NewTicker(scheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.quit:
+			return
+		case <-ticker.C:
+			b.remindRotations()
+			if n, err := b.logic.PurgeAudit(); err == nil && n > 0 {
+				b.logger.Info(fmt.Sprintf("purged %d audit log records", n))
+			}
+		}
+	}
+}
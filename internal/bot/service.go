@@ -1,7 +1,5 @@
 package bot
 
-import tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-
 var (
 	startMessageRU = `Привет!👋
 Я буду хранить твои пароли, чтобы ты не запоминал каждый 🔐. 
@@ -25,6 +23,12 @@ I'm going to save your passwords so that you don't forget them 🔐.
 I'll delete my messages every %d seconds, so that nobody can see what you've entered 🤫.`
 )
 
+// messages is a message in the supported languages.
+type messages struct {
+	Russian string
+	English string
+}
+
 var allMessages = map[string]messages{
 	start: {
 		Russian: startMessageRU,
@@ -63,6 +67,678 @@ var allMessages = map[string]messages{
 		Russian: serviceNotFoundErrRU,
 		English: serviceNotFoundErrEN,
 	},
+	internalErr: {
+		Russian: internalErrRU,
+		English: internalErrEN,
+	},
+	privateOnlyErr: {
+		Russian: privateOnlyErrRU,
+		English: privateOnlyErrEN,
+	},
+	tooManyRequestsErr: {
+		Russian: tooManyRequestsErrRU,
+		English: tooManyRequestsErrEN,
+	},
+	lockedErr: {
+		Russian: lockedErrRU,
+		English: lockedErrEN,
+	},
+	accessDeniedErr: {
+		Russian: accessDeniedErrRU,
+		English: accessDeniedErrEN,
+	},
+	adminUsage: {
+		Russian: adminUsageRU,
+		English: adminUsageEN,
+	},
+	adminDone: {
+		Russian: adminDoneRU,
+		English: adminDoneEN,
+	},
+	adminErr: {
+		Russian: adminErrRU,
+		English: adminErrEN,
+	},
+	adminNoUsers: {
+		Russian: adminNoUsersRU,
+		English: adminNoUsersEN,
+	},
+	adminStats: {
+		Russian: adminStatsRU,
+		English: adminStatsEN,
+	},
+	vaultUsage: {
+		Russian: vaultUsageRU,
+		English: vaultUsageEN,
+	},
+	vaultCreated: {
+		Russian: vaultCreatedRU,
+		English: vaultCreatedEN,
+	},
+	vaultAdded: {
+		Russian: vaultAddedRU,
+		English: vaultAddedEN,
+	},
+	vaultMemberErr: {
+		Russian: vaultMemberErrRU,
+		English: vaultMemberErrEN,
+	},
+	vaultDone: {
+		Russian: vaultDoneRU,
+		English: vaultDoneEN,
+	},
+	vaultSent: {
+		Russian: vaultSentRU,
+		English: vaultSentEN,
+	},
+	vaultNoVaults: {
+		Russian: vaultNoVaultsRU,
+		English: vaultNoVaultsEN,
+	},
+	vaultExistsErr: {
+		Russian: vaultExistsErrRU,
+		English: vaultExistsErrEN,
+	},
+	vaultNotFoundErr: {
+		Russian: vaultNotFoundErrRU,
+		English: vaultNotFoundErrEN,
+	},
+	vaultRoleErr: {
+		Russian: vaultRoleErrRU,
+		English: vaultRoleErrEN,
+	},
+	vaultLastOwnerErr: {
+		Russian: vaultLastOwnerErrRU,
+		English: vaultLastOwnerErrEN,
+	},
+	vaultStartPrivateErr: {
+		Russian: vaultStartPrivateErrRU,
+		English: vaultStartPrivateErrEN,
+	},
+	groupOnlyErr: {
+		Russian: groupOnlyErrRU,
+		English: groupOnlyErrEN,
+	},
+	shareDone: {
+		Russian: shareDoneRU,
+		English: shareDoneEN,
+	},
+	shareNotice: {
+		Russian: shareNoticeRU,
+		English: shareNoticeEN,
+	},
+	unshareDone: {
+		Russian: unshareDoneRU,
+		English: unshareDoneEN,
+	},
+	sharesEmpty: {
+		Russian: sharesEmptyRU,
+		English: sharesEmptyEN,
+	},
+	sharesItem: {
+		Russian: sharesItemRU,
+		English: sharesItemEN,
+	},
+	sharesReadOnly: {
+		Russian: sharesReadOnlyRU,
+		English: sharesReadOnlyEN,
+	},
+	sharesUntil: {
+		Russian: sharesUntilRU,
+		English: sharesUntilEN,
+	},
+	unknownUserErr: {
+		Russian: unknownUserErrRU,
+		English: unknownUserErrEN,
+	},
+	shareSelfErr: {
+		Russian: shareSelfErrRU,
+		English: shareSelfErrEN,
+	},
+	readOnlyErr: {
+		Russian: readOnlyErrRU,
+		English: readOnlyErrEN,
+	},
+	oneTime: {
+		Russian: oneTimeRU,
+		English: oneTimeEN,
+	},
+	oneTimeOpened: {
+		Russian: oneTimeOpenedRU,
+		English: oneTimeOpenedEN,
+	},
+	oneTimeDisabledErr: {
+		Russian: oneTimeDisabledErrRU,
+		English: oneTimeDisabledErrEN,
+	},
+	breachFound: {
+		Russian: breachFoundRU,
+		English: breachFoundEN,
+	},
+	breachNotFound: {
+		Russian: breachNotFoundRU,
+		English: breachNotFoundEN,
+	},
+	breachCheckOffErr: {
+		Russian: breachCheckOffErrRU,
+		English: breachCheckOffErrEN,
+	},
+	exportDone: {
+		Russian: exportDoneRU,
+		English: exportDoneEN,
+	},
+	exportUnnamed: {
+		Russian: exportUnnamedRU,
+		English: exportUnnamedEN,
+	},
+	importDone: {
+		Russian: importDoneRU,
+		English: importDoneEN,
+	},
+	importUsage: {
+		Russian: importUsageRU,
+		English: importUsageEN,
+	},
+	importReport: {
+		Russian: importReportRU,
+		English: importReportEN,
+	},
+	importConflicts: {
+		Russian: importConflictsRU,
+		English: importConflictsEN,
+	},
+	importResolved: {
+		Russian: importResolvedRU,
+		English: importResolvedEN,
+	},
+	skipButton: {
+		Russian: skipButtonRU,
+		English: skipButtonEN,
+	},
+	overwriteButton: {
+		Russian: overwriteButtonRU,
+		English: overwriteButtonEN,
+	},
+	keepBothButton: {
+		Russian: keepBothButtonRU,
+		English: keepBothButtonEN,
+	},
+	unknownFormatErr: {
+		Russian: unknownFormatErrRU,
+		English: unknownFormatErrEN,
+	},
+	encryptedFileErr: {
+		Russian: encryptedFileErrRU,
+		English: encryptedFileErrEN,
+	},
+	exportEmptyErr: {
+		Russian: exportEmptyErrRU,
+		English: exportEmptyErrEN,
+	},
+	weakPassphraseErr: {
+		Russian: weakPassphraseErrRU,
+		English: weakPassphraseErrEN,
+	},
+	badExportErr: {
+		Russian: badExportErrRU,
+		English: badExportErrEN,
+	},
+	documentTooLargeErr: {
+		Russian: documentTooLargeErrRU,
+		English: documentTooLargeErrEN,
+	},
+	strengthResult: {
+		Russian: strengthResultRU,
+		English: strengthResultEN,
+	},
+	reuseWarning: {
+		Russian: reuseWarningRU,
+		English: reuseWarningEN,
+	},
+	scoreVeryWeak: {
+		Russian: scoreVeryWeakRU,
+		English: scoreVeryWeakEN,
+	},
+	scoreWeak: {
+		Russian: scoreWeakRU,
+		English: scoreWeakEN,
+	},
+	scoreFair: {
+		Russian: scoreFairRU,
+		English: scoreFairEN,
+	},
+	scoreGood: {
+		Russian: scoreGoodRU,
+		English: scoreGoodEN,
+	},
+	scoreStrong: {
+		Russian: scoreStrongRU,
+		English: scoreStrongEN,
+	},
+	patternCommon: {
+		Russian: patternCommonRU,
+		English: patternCommonEN,
+	},
+	patternWord: {
+		Russian: patternWordRU,
+		English: patternWordEN,
+	},
+	patternKeyboard: {
+		Russian: patternKeyboardRU,
+		English: patternKeyboardEN,
+	},
+	patternSequence: {
+		Russian: patternSequenceRU,
+		English: patternSequenceEN,
+	},
+	patternRepeat: {
+		Russian: patternRepeatRU,
+		English: patternRepeatEN,
+	},
+	patternYear: {
+		Russian: patternYearRU,
+		English: patternYearEN,
+	},
+	auditSummary: {
+		Russian: auditSummaryRU,
+		English: auditSummaryEN,
+	},
+	auditUnnamed: {
+		Russian: auditUnnamedRU,
+		English: auditUnnamedEN,
+	},
+	auditReused: {
+		Russian: auditReusedRU,
+		English: auditReusedEN,
+	},
+	auditOld: {
+		Russian: auditOldRU,
+		English: auditOldEN,
+	},
+	auditNoLogin: {
+		Russian: auditNoLoginRU,
+		English: auditNoLoginEN,
+	},
+	auditEmpty: {
+		Russian: auditEmptyRU,
+		English: auditEmptyEN,
+	},
+	rotationStatus: {
+		Russian: rotationStatusRU,
+		English: rotationStatusEN,
+	},
+	rotationOff: {
+		Russian: rotationOffRU,
+		English: rotationOffEN,
+	},
+	rotationSet: {
+		Russian: rotationSetRU,
+		English: rotationSetEN,
+	},
+	rotationEntrySet: {
+		Russian: rotationEntrySetRU,
+		English: rotationEntrySetEN,
+	},
+	rotationDisabled: {
+		Russian: rotationDisabledRU,
+		English: rotationDisabledEN,
+	},
+	rotationEntryDisabled: {
+		Russian: rotationEntryDisabledRU,
+		English: rotationEntryDisabledEN,
+	},
+	rotationPeriodErr: {
+		Russian: rotationPeriodErrRU,
+		English: rotationPeriodErrEN,
+	},
+	rotationReminder: {
+		Russian: rotationReminderRU,
+		English: rotationReminderEN,
+	},
+	rotationSnoozed: {
+		Russian: rotationSnoozedRU,
+		English: rotationSnoozedEN,
+	},
+	rotationGenerated: {
+		Russian: rotationGeneratedRU,
+		English: rotationGeneratedEN,
+	},
+	rotationNothingDue: {
+		Russian: rotationNothingDueRU,
+		English: rotationNothingDueEN,
+	},
+	snoozeButton: {
+		Russian: snoozeButtonRU,
+		English: snoozeButtonEN,
+	},
+	generateButton: {
+		Russian: generateButtonRU,
+		English: generateButtonEN,
+	},
+	logTitle: {
+		Russian: logTitleRU,
+		English: logTitleEN,
+	},
+	logEmpty: {
+		Russian: logEmptyRU,
+		English: logEmptyEN,
+	},
+	actionGet: {
+		Russian: actionGetRU,
+		English: actionGetEN,
+	},
+	actionSave: {
+		Russian: actionSaveRU,
+		English: actionSaveEN,
+	},
+	actionDelete: {
+		Russian: actionDeleteRU,
+		English: actionDeleteEN,
+	},
+	actionLang: {
+		Russian: actionLangRU,
+		English: actionLangEN,
+	},
+	outcomeOK: {
+		Russian: outcomeOKRU,
+		English: outcomeOKEN,
+	},
+	outcomeNotFound: {
+		Russian: outcomeNotFoundRU,
+		English: outcomeNotFoundEN,
+	},
+	outcomeLocked: {
+		Russian: outcomeLockedRU,
+		English: outcomeLockedEN,
+	},
+	outcomeDenied: {
+		Russian: outcomeDeniedRU,
+		English: outcomeDeniedEN,
+	},
+	outcomeError: {
+		Russian: outcomeErrorRU,
+		English: outcomeErrorEN,
+	},
+	wipeWarning: {
+		Russian: wipeWarningRU,
+		English: wipeWarningEN,
+	},
+	wipeButton: {
+		Russian: wipeButtonRU,
+		English: wipeButtonEN,
+	},
+	wipeTypePhrase: {
+		Russian: wipeTypePhraseRU,
+		English: wipeTypePhraseEN,
+	},
+	wipePhrase: {
+		Russian: wipePhraseRU,
+		English: wipePhraseEN,
+	},
+	wipePhraseErr: {
+		Russian: wipePhraseErrRU,
+		English: wipePhraseErrEN,
+	},
+	wipeNotConfirmed: {
+		Russian: wipeNotConfirmedRU,
+		English: wipeNotConfirmedEN,
+	},
+	wipeDone: {
+		Russian: wipeDoneRU,
+		English: wipeDoneEN,
+	},
+	duressOn: {
+		Russian: duressOnRU,
+		English: duressOnEN,
+	},
+	duressOff: {
+		Russian: duressOffRU,
+		English: duressOffEN,
+	},
+	duressSet: {
+		Russian: duressSetRU,
+		English: duressSetEN,
+	},
+	duressRemoved: {
+		Russian: duressRemovedRU,
+		English: duressRemovedEN,
+	},
+	duressPINErr: {
+		Russian: duressPINErrRU,
+		English: duressPINErrEN,
+	},
+	actionWipe: {
+		Russian: actionWipeRU,
+		English: actionWipeEN,
+	},
+	mydataTitle: {
+		Russian: mydataTitleRU,
+		English: mydataTitleEN,
+	},
+	mydataServices: {
+		Russian: mydataServicesRU,
+		English: mydataServicesEN,
+	},
+	mydataServicesTimes: {
+		Russian: mydataServicesTimesRU,
+		English: mydataServicesTimesEN,
+	},
+	mydataShares: {
+		Russian: mydataSharesRU,
+		English: mydataSharesEN,
+	},
+	mydataOneTime: {
+		Russian: mydataOneTimeRU,
+		English: mydataOneTimeEN,
+	},
+	mydataVaults: {
+		Russian: mydataVaultsRU,
+		English: mydataVaultsEN,
+	},
+	mydataLang: {
+		Russian: mydataLangRU,
+		English: mydataLangEN,
+	},
+	mydataLangAuto: {
+		Russian: mydataLangAutoRU,
+		English: mydataLangAutoEN,
+	},
+	mydataLangNone: {
+		Russian: mydataLangNoneRU,
+		English: mydataLangNoneEN,
+	},
+	mydataRotation: {
+		Russian: mydataRotationRU,
+		English: mydataRotationEN,
+	},
+	mydataRotationOff: {
+		Russian: mydataRotationOffRU,
+		English: mydataRotationOffEN,
+	},
+	mydataDuressOn: {
+		Russian: mydataDuressOnRU,
+		English: mydataDuressOnEN,
+	},
+	mydataDuressOff: {
+		Russian: mydataDuressOffRU,
+		English: mydataDuressOffEN,
+	},
+	mydataMember: {
+		Russian: mydataMemberRU,
+		English: mydataMemberEN,
+	},
+	mydataAudit: {
+		Russian: mydataAuditRU,
+		English: mydataAuditEN,
+	},
+	mydataAuditForever: {
+		Russian: mydataAuditForeverRU,
+		English: mydataAuditForeverEN,
+	},
+	mydataAuditNone: {
+		Russian: mydataAuditNoneRU,
+		English: mydataAuditNoneEN,
+	},
+	mydataFooter: {
+		Russian: mydataFooterRU,
+		English: mydataFooterEN,
+	},
+	forgetWarning: {
+		Russian: forgetWarningRU,
+		English: forgetWarningEN,
+	},
+	forgetButton: {
+		Russian: forgetButtonRU,
+		English: forgetButtonEN,
+	},
+	forgotten: {
+		Russian: forgottenRU,
+		English: forgottenEN,
+	},
+	fileUsage: {
+		Russian: fileUsageRU,
+		English: fileUsageEN,
+	},
+	fileSaved: {
+		Russian: fileSavedRU,
+		English: fileSavedEN,
+	},
+	fileCaption: {
+		Russian: fileCaptionRU,
+		English: fileCaptionEN,
+	},
+	fileDeleted: {
+		Russian: fileDeletedRU,
+		English: fileDeletedEN,
+	},
+	fileNotFoundErr: {
+		Russian: fileNotFoundErrRU,
+		English: fileNotFoundErrEN,
+	},
+	fileTooLargeErr: {
+		Russian: fileTooLargeErrRU,
+		English: fileTooLargeErrEN,
+	},
+	filesEmpty: {
+		Russian: filesEmptyRU,
+		English: filesEmptyEN,
+	},
+	filesTitle: {
+		Russian: filesTitleRU,
+		English: filesTitleEN,
+	},
+	filePhoto: {
+		Russian: filePhotoRU,
+		English: filePhotoEN,
+	},
+	mydataFiles: {
+		Russian: mydataFilesRU,
+		English: mydataFilesEN,
+	},
+	actionFileSave: {
+		Russian: actionFileSaveRU,
+		English: actionFileSaveEN,
+	},
+	actionFileGet: {
+		Russian: actionFileGetRU,
+		English: actionFileGetEN,
+	},
+	actionFileDelete: {
+		Russian: actionFileDeleteRU,
+		English: actionFileDeleteEN,
+	},
+	actionExport: {
+		Russian: actionExportRU,
+		English: actionExportEN,
+	},
+	setExpires: {
+		Russian: setExpiresRU,
+		English: setExpiresEN,
+	},
+	getExpires: {
+		Russian: getExpiresRU,
+		English: getExpiresEN,
+	},
+	expiryErr: {
+		Russian: expiryErrRU,
+		English: expiryErrEN,
+	},
+	expiryWarning: {
+		Russian: expiryWarningRU,
+		English: expiryWarningEN,
+	},
+	expiredNotice: {
+		Russian: expiredNoticeRU,
+		English: expiredNoticeEN,
+	},
+	actionExpire: {
+		Russian: actionExpireRU,
+		English: actionExpireEN,
+	},
+	getLogin: {
+		Russian: getLoginRU,
+		English: getLoginEN,
+	},
+	getPassword: {
+		Russian: getPasswordRU,
+		English: getPasswordEN,
+	},
+	displayStatus: {
+		Russian: displayStatusRU,
+		English: displayStatusEN,
+	},
+	displaySet: {
+		Russian: displaySetRU,
+		English: displaySetEN,
+	},
+	displayAll: {
+		Russian: displayAllRU,
+		English: displayAllEN,
+	},
+	displayLogin: {
+		Russian: displayLoginRU,
+		English: displayLoginEN,
+	},
+	displayPassword: {
+		Russian: displayPasswordRU,
+		English: displayPasswordEN,
+	},
+	mydataDisplay: {
+		Russian: mydataDisplayRU,
+		English: mydataDisplayEN,
+	},
+	displayReveal: {
+		Russian: displayRevealRU,
+		English: displayRevealEN,
+	},
+	revealedPassword: {
+		Russian: revealedPasswordRU,
+		English: revealedPasswordEN,
+	},
+	showPasswordButton: {
+		Russian: showPasswordButtonRU,
+		English: showPasswordButtonEN,
+	},
+	showForButton: {
+		Russian: showForButtonRU,
+		English: showForButtonEN,
+	},
+	sendPasswordButton: {
+		Russian: sendPasswordButtonRU,
+		English: sendPasswordButtonEN,
+	},
+	expiredButtonErr: {
+		Russian: expiredButtonErrRU,
+		English: expiredButtonErrEN,
+	},
+	hideButton: {
+		Russian: hideButtonRU,
+		English: hideButtonEN,
+	},
+	changeLangButton: {
+		Russian: changeLangButtonRU,
+		English: changeLangButtonEN,
+	},
 }
 
 // Group of constants for bot messages
@@ -80,6 +756,10 @@ const (
 	getMessageRU    = "🔐 %s\n👤 Логин: %s\n🔑 Пароль: %s\n"
 	getErrMessageRU = "Что-то пошло не так! ⚒"
 	getMessageEN    = "🔐 %s\n👤 Login: %s\n🔑 Password: %s\n"
+	getLoginRU      = "🔐 %s\n👤 Логин: %s\n"
+	getLoginEN      = "🔐 %s\n👤 Login: %s\n"
+	getPasswordRU   = "🔐 %s\n🔑 Пароль: %s\n"
+	getPasswordEN   = "🔐 %s\n🔑 Password: %s\n"
 	getErrMessageEN = "Something went wrong! ⚒"
 
 	wrongInputErrRU = "Неправильные аргументы для команды ⛔️"
@@ -87,6 +767,469 @@ const (
 
 	serviceNotFoundErrRU = "Сервис не найден ❌"
 	serviceNotFoundErrEN = "Service not found ❌"
+
+	internalErrRU = "Внутренняя ошибка, попробуй позже ⚒"
+	internalErrEN = "Internal error, try again later ⚒"
+
+	privateOnlyErrRU = "Эта команда работает только в личном чате с ботом 🔒"
+	privateOnlyErrEN = "This command works only in a private chat with the bot 🔒"
+
+	tooManyRequestsErrRU = "Слишком много запросов, помедленнее ⏳"
+	tooManyRequestsErrEN = "Too many requests, slow down ⏳"
+
+	lockedErrRU = "Слишком много неудачных попыток, попробуй через %d мин. 🔒"
+	lockedErrEN = "Too many failed attempts, try again in %d min. 🔒"
+
+	accessDeniedErrRU = "Это приватный бот 🔒\nПопроси администратора добавить тебя, твой ID: %d"
+	accessDeniedErrEN = "This is a private bot 🔒\nAsk an admin to add you, your ID: %d"
+
+	expiredButtonErrRU = "Кнопка устарела, отправь команду заново ⌛️"
+	expiredButtonErrEN = "The button has expired, send the command again ⌛️"
+)
+
+// Group of constants for admin messages.
+const (
+	adminUsageRU   = "/admin users - участники\n/admin revoke ID - забрать доступ\n/admin allow ID - выдать доступ\n/admin stats - статистика"
+	adminUsageEN   = "/admin users - members\n/admin revoke ID - revoke access\n/admin allow ID - grant access\n/admin stats - statistics"
+	adminDoneRU    = "Готово, пользователь %d обновлен ✅"
+	adminDoneEN    = "Done, user %d is updated ✅"
+	adminErrRU     = "Не получилось ⛔️"
+	adminErrEN     = "Failed ⛔️"
+	adminNoUsersRU = "Пока никто не присоединился"
+	adminNoUsersEN = "Nobody has joined yet"
+	adminStatsRU   = "👥 Участники: %d\n💬 Чаты: %d\n🔐 Сервисы: %d\n⏳ Ограничено запросов: %d\n🔒 Заблокировано запросов: %d"
+	adminStatsEN   = "👥 Members: %d\n💬 Chats: %d\n🔐 Services: %d\n⏳ Throttled requests: %d\n🔒 Locked requests: %d"
+)
+
+// Group of constants for vault messages.
+const (
+	vaultUsageRU = `В группе:
+/vault create имя - создать хранилище группы
+/vault add ID owner|editor|viewer - добавить участника
+/vault members - участники
+/vault role ID owner|editor|viewer - сменить роль
+/vault kick ID - исключить, /vault leave - выйти
+/vault get имя_сервиса - прислать пароль в личный чат
+/vault del имя_сервиса - удалить пароль
+
+В личном чате:
+/vault list - мои хранилища
+/vault set хранилище имя_сервиса логин пароль
+/vault get хранилище имя_сервиса
+/vault del хранилище имя_сервиса`
+	vaultUsageEN = `In a group:
+/vault create name - create the vault of the group
+/vault add ID owner|editor|viewer - add the member
+/vault members - members
+/vault role ID owner|editor|viewer - change the role
+/vault kick ID - remove the member, /vault leave - leave
+/vault get service_name - send the password to the private chat
+/vault del service_name - delete the password
+
+In the private chat:
+/vault list - my vaults
+/vault set vault service_name login password
+/vault get vault service_name
+/vault del vault service_name`
+	vaultCreatedRU         = "Хранилище %s создано 🗄\nУчастников добавляет владелец командой /vault add ID роль"
+	vaultCreatedEN         = "The vault %s is created 🗄\nThe owner adds members with /vault add ID role"
+	vaultAddedRU           = "Участник добавлен, пароли хранилища доступны ему в личном чате с ботом ✅"
+	vaultAddedEN           = "The member is added and gets the passwords of the vault in the private chat with the bot ✅"
+	vaultDoneRU            = "Готово ✅"
+	vaultDoneEN            = "Done ✅"
+	vaultSentRU            = "Отправил в личный чат 📬"
+	vaultSentEN            = "Sent to the private chat 📬"
+	vaultNoVaultsRU        = "У тебя пока нет хранилищ"
+	vaultNoVaultsEN        = "You have no vaults yet"
+	vaultExistsErrRU       = "У группы уже есть хранилище или имя занято ⛔️"
+	vaultExistsErrEN       = "The group already has a vault or the name is taken ⛔️"
+	vaultNotFoundErrRU     = "Хранилище не найдено или ты не его участник ❌"
+	vaultNotFoundErrEN     = "The vault is not found or you're not its member ❌"
+	vaultRoleErrRU         = "Недостаточно прав в хранилище ⛔️"
+	vaultRoleErrEN         = "Not enough rights in the vault ⛔️"
+	vaultLastOwnerErrRU    = "У хранилища должен остаться владелец ⛔️"
+	vaultLastOwnerErrEN    = "The vault must have an owner ⛔️"
+	vaultMemberErrRU       = "Он уже участник хранилища, роль меняется командой /vault role ⛔️"
+	vaultMemberErrEN       = "They're a member of the vault already, /vault role changes the role ⛔️"
+	vaultStartPrivateErrRU = "Сначала напиши мне /start в личном чате ✉️"
+	vaultStartPrivateErrEN = "Send me /start in the private chat first ✉️"
+	groupOnlyErrRU         = "Эта команда работает только в группе 👥"
+	groupOnlyErrEN         = "This command works only in a group 👥"
+)
+
+// Group of constants for share messages.
+const (
+	shareDoneRU      = "Доступ выдан ✅"
+	shareDoneEN      = "Shared ✅"
+	shareNoticeRU    = "🤝 %s поделился с тобой паролем %s, смотри /get %s"
+	shareNoticeEN    = "🤝 %s shared the password %s with you, see /get %s"
+	unshareDoneRU    = "Доступ забран 🚫"
+	unshareDoneEN    = "Unshared 🚫"
+	sharesEmptyRU    = "Ты ни с кем не делился паролями"
+	sharesEmptyEN    = "You haven't shared any passwords"
+	sharesItemRU     = "🔐 %s → %d, открыт %d раз"
+	sharesItemEN     = "🔐 %s → %d, opened %d times"
+	sharesReadOnlyRU = ", только чтение"
+	sharesReadOnlyEN = ", read-only"
+	sharesUntilRU    = ", до %s"
+	sharesUntilEN    = ", until %s"
+	unknownUserErrRU = "Пользователь не найден, он должен сначала написать боту ❌"
+	unknownUserErrEN = "User not found, they have to message the bot first ❌"
+	shareSelfErrRU   = "Нельзя поделиться с самим собой ⛔️"
+	shareSelfErrEN   = "You can't share with yourself ⛔️"
+	readOnlyErrRU    = "С тобой поделились этим паролем только для чтения ⛔️"
+	readOnlyErrEN    = "This password is shared with you read-only ⛔️"
+)
+
+// Group of constants for one-time link messages.
+const (
+	oneTimeRU            = "🔗 Одноразовая ссылка на %s, действует до %s:\n%s"
+	oneTimeEN            = "🔗 One-time link to %s, valid until %s:\n%s"
+	oneTimeOpenedRU      = "🔓 Одноразовую ссылку на %s открыли"
+	oneTimeOpenedEN      = "🔓 The one-time link to %s has been opened"
+	oneTimeDisabledErrRU = "Одноразовые ссылки не настроены ⛔️"
+	oneTimeDisabledErrEN = "One-time links are not configured ⛔️"
+)
+
+// Group of constants for breach check messages.
+const (
+	breachFoundRU       = "⚠️ Этот пароль есть в утечках (%d раз), лучше его сменить"
+	breachFoundEN       = "⚠️ This password appears in known breaches (%d times), better change it"
+	breachNotFoundRU    = "✅ Этого пароля нет в известных утечках"
+	breachNotFoundEN    = "✅ This password is not in the known breaches"
+	breachCheckOffErrRU = "Проверка утечек не настроена ⛔️"
+	breachCheckOffErrEN = "The breach check is not configured ⛔️"
+)
+
+// Group of constants for export messages.
+const (
+	exportDoneRU          = "📦 Экспорт %d паролей. Без парольной фразы файл не расшифровать, не теряй её"
+	exportDoneEN          = "📦 Export of %d passwords. The file can't be decrypted without the passphrase, don't lose it"
+	exportUnnamedRU       = "\n⚠️ %d паролей сохранены до появления экспорта и не попали в файл, сохрани их заново через /set"
+	exportUnnamedEN       = "\n⚠️ %d passwords were saved before the export appeared and are not in the file, save them again with /set"
+	importDoneRU          = "📥 Импортировано паролей: %d"
+	importDoneEN          = "📥 Passwords imported: %d"
+	importUsageRU         = "Отправь файл экспорта другого менеджера паролей или экспорт бота с подписью /import парольная_фраза"
+	importUsageEN         = "Send the export file of another password manager or the export of the bot with the caption /import passphrase"
+	exportEmptyErrRU      = "Нечего экспортировать ❌"
+	exportEmptyErrEN      = "Nothing to export ❌"
+	weakPassphraseErrRU   = "Парольная фраза должна быть не короче %d символов ⛔️"
+	weakPassphraseErrEN   = "The passphrase must be at least %d characters long ⛔️"
+	badExportErrRU        = "Неверная парольная фраза или файл повреждён ❌"
+	badExportErrEN        = "Wrong passphrase or damaged file ❌"
+	documentTooLargeErrRU = "Файл слишком большой ⛔️"
+	documentTooLargeErrEN = "The file is too large ⛔️"
+)
+
+// Group of constants for import messages.
+const (
+	importReportRU     = "📥 Импорт из %s: сохранено %d, одинаковых пропущено %d. Файл удалён из чата"
+	importReportEN     = "📥 Import from %s: %d saved, %d identical skipped. The file is deleted from the chat"
+	importConflictsRU  = "⚠️ Эти пароли уже сохранены с другими данными:\n%s\n\nЧто с ними сделать?"
+	importConflictsEN  = "⚠️ These passwords are already saved with other values:\n%s\n\nWhat should I do with them?"
+	importResolvedRU   = "✅ Готово, сохранено паролей: %d"
+	importResolvedEN   = "✅ Done, passwords saved: %d"
+	skipButtonRU       = "Пропустить"
+	skipButtonEN       = "Skip"
+	overwriteButtonRU  = "Заменить"
+	overwriteButtonEN  = "Overwrite"
+	keepBothButtonRU   = "Оставить оба"
+	keepBothButtonEN   = "Keep both"
+	unknownFormatErrRU = "Неизвестный формат. Поддерживаются Bitwarden (JSON и CSV), KeePass 2 (XML), 1Password, Chrome и Firefox (CSV) ❌"
+	unknownFormatErrEN = "Unknown format. Supported are Bitwarden (JSON and CSV), KeePass 2 (XML), 1Password, Chrome and Firefox (CSV) ❌"
+	encryptedFileErrRU = "Файл зашифрован, сделай экспорт без пароля ❌"
+	encryptedFileErrEN = "The file is encrypted, export it without a password ❌"
+)
+
+// Group of constants for strength and audit messages.
+const (
+	strengthResultRU  = "🔒 Надёжность: %s"
+	strengthResultEN  = "🔒 Strength: %s"
+	reuseWarningRU    = "⚠️ Этот же пароль сохранён для: %s"
+	reuseWarningEN    = "⚠️ The same password is saved for: %s"
+	scoreVeryWeakRU   = "очень слабый"
+	scoreVeryWeakEN   = "very weak"
+	scoreWeakRU       = "слабый"
+	scoreWeakEN       = "weak"
+	scoreFairRU       = "средний"
+	scoreFairEN       = "fair"
+	scoreGoodRU       = "хороший"
+	scoreGoodEN       = "good"
+	scoreStrongRU     = "надёжный"
+	scoreStrongEN     = "strong"
+	patternCommonRU   = "популярный пароль"
+	patternCommonEN   = "common password"
+	patternWordRU     = "словарное слово"
+	patternWordEN     = "dictionary word"
+	patternKeyboardRU = "клавиши подряд"
+	patternKeyboardEN = "keyboard pattern"
+	patternSequenceRU = "последовательность символов"
+	patternSequenceEN = "character sequence"
+	patternRepeatRU   = "повторы"
+	patternRepeatEN   = "repeated characters"
+	patternYearRU     = "год"
+	patternYearEN     = "year"
+	auditSummaryRU    = "🩺 Проверено паролей: %d\nСлабых: %d, повторяющихся: %d, старше года: %d, без логина: %d"
+	auditSummaryEN    = "🩺 Passwords checked: %d\nWeak: %d, reused: %d, older than a year: %d, without login: %d"
+	auditUnnamedRU    = "\n⚠️ %d паролей сохранены до появления проверки и не проверены, сохрани их заново через /set"
+	auditUnnamedEN    = "\n⚠️ %d passwords were saved before the audit appeared and are not checked, save them again with /set"
+	auditReusedRU     = "повторяется в %s"
+	auditReusedEN     = "reused in %s"
+	auditOldRU        = "старше года"
+	auditOldEN        = "older than a year"
+	auditNoLoginRU    = "нет логина"
+	auditNoLoginEN    = "no login"
+	auditEmptyRU      = "Нечего проверять, сохрани пароли через /set"
+	auditEmptyEN      = "Nothing to audit, save passwords with /set"
+)
+
+// Group of constants for rotation messages.
+const (
+	rotationStatusRU        = "🔁 Пароли нужно менять каждые %d дн. Изменить: /rotate 90d или /rotate имя_сервиса 30d, выключить: /rotate off"
+	rotationStatusEN        = "🔁 The passwords should be changed every %d days. Change it with /rotate 90d or /rotate service_name 30d, turn it off with /rotate off"
+	rotationOffRU           = "🔁 Напоминания о смене паролей выключены. Включить: /rotate 90d или /rotate имя_сервиса 30d"
+	rotationOffEN           = "🔁 Password rotation reminders are off. Turn them on with /rotate 90d or /rotate service_name 30d"
+	rotationSetRU           = "✅ Напомню сменить пароли, которые старше %d дн."
+	rotationSetEN           = "✅ I will remind you to change the passwords older than %d days"
+	rotationEntrySetRU      = "✅ Напомню сменить пароль %s, когда он будет старше %d дн."
+	rotationEntrySetEN      = "✅ I will remind you to change the password of %s when it is older than %d days"
+	rotationDisabledRU      = "✅ Напоминания о смене паролей выключены"
+	rotationDisabledEN      = "✅ Password rotation reminders are off"
+	rotationEntryDisabledRU = "✅ Не буду напоминать о смене пароля %s"
+	rotationEntryDisabledEN = "✅ No reminders to change the password of %s"
+	rotationPeriodErrRU     = "Период должен быть не меньше дня ⛔️"
+	rotationPeriodErrEN     = "The period must be at least a day ⛔️"
+	rotationReminderRU      = "⏰ Эти пароли давно не менялись, пора их сменить:\n%s"
+	rotationReminderEN      = "⏰ These passwords are older than the rotation period, time to change them:\n%s"
+	rotationSnoozedRU       = "⏰ Напомню через %d дн."
+	rotationSnoozedEN       = "⏰ I will remind you in %d days"
+	rotationGeneratedRU     = "🎲 Новые пароли, смени их на сайтах и сохрани через /set:\n%s"
+	rotationGeneratedEN     = "🎲 New passwords, change them on the sites and save them with /set:\n%s"
+	rotationNothingDueRU    = "Все пароли уже сменены ✅"
+	rotationNothingDueEN    = "All the passwords are changed already ✅"
+	snoozeButtonRU          = "Напомнить через месяц ⏰"
+	snoozeButtonEN          = "Remind in a month ⏰"
+	generateButtonRU        = "Сгенерировать новые 🎲"
+	generateButtonEN        = "Generate new 🎲"
+)
+
+// Group of constants for activity log messages.
+const (
+	logTitleRU         = "📜 Последние действия, сначала новые:"
+	logTitleEN         = "📜 Recent activity, newest first:"
+	logEmptyRU         = "Действий пока не было"
+	logEmptyEN         = "No activity yet"
+	actionGetRU        = "просмотр"
+	actionGetEN        = "viewed"
+	actionSaveRU       = "сохранение"
+	actionSaveEN       = "saved"
+	actionDeleteRU     = "удаление"
+	actionDeleteEN     = "deleted"
+	actionLangRU       = "смена языка"
+	actionLangEN       = "changed the language"
+	actionWipeRU       = "удаление всех данных"
+	actionWipeEN       = "wiped everything"
+	actionFileSaveRU   = "сохранение файла"
+	actionFileSaveEN   = "saved a file"
+	actionFileGetRU    = "просмотр файла"
+	actionFileGetEN    = "viewed a file"
+	actionFileDeleteRU = "удаление файла"
+	actionFileDeleteEN = "deleted a file"
+	actionExportRU     = "экспорт"
+	actionExportEN     = "exported everything"
+	actionExpireRU     = "удаление по сроку"
+	actionExpireEN     = "expired"
+	outcomeOKRU        = "✅"
+	outcomeOKEN        = "✅"
+	outcomeNotFoundRU  = "не найден ❓"
+	outcomeNotFoundEN  = "not found ❓"
+	outcomeLockedRU    = "заблокировано 🔒"
+	outcomeLockedEN    = "locked out 🔒"
+	outcomeDeniedRU    = "нет прав ⛔️"
+	outcomeDeniedEN    = "denied ⛔️"
+	outcomeErrorRU     = "ошибка ❌"
+	outcomeErrorEN     = "error ❌"
+)
+
+// Group of constants for wipe messages.
+const (
+	wipeWarningRU      = "⚠️ Будут навсегда удалены все пароли, файлы, настройки, общие пароли одноразовые ссылки и журнал действий, отменить это нельзя. В журнале останется только очистка."
+	wipeWarningEN      = "⚠️ All your passwords, files, settings, shares, one-time links and the activity log will be deleted for good, this can't be undone. Only the wipe is left in the log."
+	wipeButtonRU       = "Продолжить 🗑"
+	wipeButtonEN       = "Continue 🗑"
+	wipeTypePhraseRU   = "Чтобы подтвердить, отправь в течение %d мин.:\n/wipe %s"
+	wipeTypePhraseEN   = "To confirm, send within %d minutes:\n/wipe %s"
+	wipePhraseRU       = "удалить всё"
+	wipePhraseEN       = "delete everything"
+	wipePhraseErrRU    = "Фраза не совпадает, ничего не удалено"
+	wipePhraseErrEN    = "The phrase doesn't match, nothing is deleted"
+	wipeNotConfirmedRU = "Сначала отправь /wipe и нажми кнопку"
+	wipeNotConfirmedEN = "Send /wipe and press the button first"
+	wipeDoneRU         = "🗑 Все данные удалены"
+	wipeDoneEN         = "🗑 Everything is deleted"
+	duressOnRU         = "🆘 Код под принуждением задан: если отправить его сообщением, все данные будут молча удалены. /duress off — убрать код"
+	duressOnEN         = "🆘 The duress PIN is set: send it as a message and everything is deleted silently. /duress off removes it"
+	duressOffRU        = "Код под принуждением не задан. /duress КОД — задать: если отправить его сообщением, все данные будут молча удалены, как по /wipe"
+	duressOffEN        = "The duress PIN is not set. /duress PIN sets it: send it as a message and everything is deleted silently, like with /wipe"
+	duressSetRU        = "🆘 Код под принуждением задан"
+	duressSetEN        = "🆘 The duress PIN is set"
+	duressRemovedRU    = "Код под принуждением убран"
+	duressRemovedEN    = "The duress PIN is removed"
+	duressPINErrRU     = "Код должен быть не короче %d символов"
+	duressPINErrEN     = "The PIN must be at least %d characters long"
+)
+
+// Group of constants for personal data messages.
+const (
+	mydataTitleRU         = "🗂 Что хранится о тебе:"
+	mydataTitleEN         = "🗂 What is stored about you:"
+	mydataServicesRU      = "🔑 Паролей: %d"
+	mydataServicesEN      = "🔑 Passwords: %d"
+	mydataServicesTimesRU = " (первый сохранён %s, последний изменён %s)"
+	mydataServicesTimesEN = " (first saved %s, last changed %s)"
+	mydataSharesRU        = "🤝 Ты поделился: %d, с тобой поделились: %d"
+	mydataSharesEN        = "🤝 Shared by you: %d, shared with you: %d"
+	mydataOneTimeRU       = "🔗 Одноразовых ссылок: %d"
+	mydataOneTimeEN       = "🔗 One-time links: %d"
+	mydataFilesRU         = "📎 Файлов: %d"
+	mydataFilesEN         = "📎 Files: %d"
+	mydataVaultsRU        = "👥 Хранилищ команды: %d"
+	mydataVaultsEN        = "👥 Team vaults: %d"
+	mydataLangRU          = "🌐 Язык: %s"
+	mydataLangEN          = "🌐 Language: %s"
+	mydataLangAutoRU      = "🌐 Язык: %s (определён автоматически)"
+	mydataLangAutoEN      = "🌐 Language: %s (detected)"
+	mydataLangNoneRU      = "🌐 Язык не выбран"
+	mydataLangNoneEN      = "🌐 Language: not chosen"
+	mydataRotationRU      = "⏰ Напоминания о смене паролей: каждые %d дн."
+	mydataRotationEN      = "⏰ Rotation reminders: every %d days"
+	mydataRotationOffRU   = "⏰ Напоминания о смене паролей выключены"
+	mydataRotationOffEN   = "⏰ Rotation reminders: off"
+	mydataDuressOnRU      = "🆘 Код под принуждением задан"
+	mydataDuressOnEN      = "🆘 Duress PIN: set"
+	mydataDuressOffRU     = "🆘 Код под принуждением не задан"
+	mydataDuressOffEN     = "🆘 Duress PIN: not set"
+	mydataMemberRU        = "🔐 Доступ: %s с %s"
+	mydataMemberEN        = "🔐 Access: %s since %s"
+	mydataAuditRU         = "📜 Журнал действий: %d записей с %s, хранятся %d дн."
+	mydataAuditEN         = "📜 Activity log: %d records since %s, kept for %d days"
+	mydataAuditForeverRU  = "📜 Журнал действий: %d записей с %s, хранятся бессрочно"
+	mydataAuditForeverEN  = "📜 Activity log: %d records since %s, kept forever"
+	mydataAuditNoneRU     = "📜 Журнал действий пуст"
+	mydataAuditNoneEN     = "📜 Activity log: no records"
+	mydataFooterRU        = "Пароли, логины, файлы и имена сервисов хранятся зашифрованными и здесь не показываются. /forgetme удалит всё это."
+	mydataFooterEN        = "Passwords, logins, files and service names are stored encrypted and never shown here. /forgetme deletes all of it."
+	forgetWarningRU       = "⚠️ Будет удалено всё, что хранится о тебе: пароли, файлы, настройки, общие пароли, ссылки, доступ, членство в хранилищах команды и журнал действий."
+	forgetWarningEN       = "⚠️ Everything stored about you will be deleted: passwords, files, settings, shares, links, access, team vault memberships and the activity log."
+	forgetButtonRU        = "Удалить всё обо мне 🗑"
+	forgetButtonEN        = "Forget me 🗑"
+	forgottenRU           = "🗑 Всё о тебе удалено"
+	forgottenEN           = "🗑 Everything about you is deleted"
+)
+
+// Group of constants for file messages.
+const (
+	fileUsageRU       = "Отправь документ или фото с подписью имя_сервиса, чтобы сохранить его. /file имя_сервиса — получить файл, /file имя_сервиса del — удалить"
+	fileUsageEN       = "Send a document or a photo with the caption service_name to save it. /file service_name sends the file back, /file service_name del deletes it"
+	fileSavedRU       = "📎 Файл сохранён для %s, сообщение с ним удалено ✅"
+	fileSavedEN       = "📎 The file is saved for %s, the message with it is deleted ✅"
+	fileCaptionRU     = "📎 %s"
+	fileCaptionEN     = "📎 %s"
+	fileDeletedRU     = "Файл удалён 🗑"
+	fileDeletedEN     = "The file is deleted 🗑"
+	fileNotFoundErrRU = "Файла нет ❓"
+	fileNotFoundErrEN = "No such file ❓"
+	fileTooLargeErrRU = "Файл слишком большой, можно до %d МБ ⛔️"
+	fileTooLargeErrEN = "The file is too large, up to %d MB is allowed ⛔️"
+	filesEmptyRU      = "Файлов пока нет. Отправь документ или фото с подписью имя_сервиса, чтобы сохранить его"
+	filesEmptyEN      = "No files yet. Send a document or a photo with the caption service_name to save it"
+	filesTitleRU      = "📎 Файлы:"
+	filesTitleEN      = "📎 Files:"
+	filePhotoRU       = "фото"
+	filePhotoEN       = "photo"
+)
+
+// Group of constants for expiry messages.
+const (
+	setExpiresRU    = "⏳ Будет удалён %s"
+	setExpiresEN    = "⏳ It's deleted on %s"
+	getExpiresRU    = "⏳ Будет удалён %s"
+	getExpiresEN    = "⏳ Expires on %s"
+	expiryErrRU     = "Срок должен быть не меньше %d ч. ⛔️"
+	expiryErrEN     = "The expiry must be at least %d hours away ⛔️"
+	expiryWarningRU = "⏳ Скоро истечёт срок этих паролей, и они будут удалены:\n%s\nСохрани пароль снова через /set, чтобы оставить его"
+	expiryWarningEN = "⏳ These passwords expire soon and will be deleted:\n%s\nSave a password again with /set to keep it"
+	expiredNoticeRU = "⌛️ Срок этих паролей истёк, они удалены:\n%s"
+	expiredNoticeEN = "⌛️ These passwords have expired and are deleted:\n%s"
+)
+
+// Group of constants for display messages.
+const (
+	displayStatusRU   = "👁 /get показывает %s. /display all|login|password|reveal — изменить"
+	displayStatusEN   = "👁 /get shows %s. /display all|login|password|reveal changes it"
+	displaySetRU      = "👁 Теперь /get показывает %s"
+	displaySetEN      = "👁 Now /get shows %s"
+	displayAllRU      = "логин и пароль"
+	displayAllEN      = "the login and the password"
+	displayLoginRU    = "только логин"
+	displayLoginEN    = "only the login"
+	displayPasswordRU = "только пароль"
+	displayPasswordEN = "only the password"
+	mydataDisplayRU   = "👁 /get показывает %s"
+	mydataDisplayEN   = "👁 /get shows %s"
+	displayRevealRU   = "логин, а пароль по кнопке"
+	displayRevealEN   = "the login and the password on a button"
+
+	revealedPasswordRU = "🔐 %s\n🔑 Пароль: %s\n🗑 Сообщение удалится через %d с"
+	revealedPasswordEN = "🔐 %s\n🔑 Password: %s\n🗑 The message is deleted in %d s"
+)
+
+// Group of constants for command descriptions shown in the Telegram menu.
+const (
+	startDescriptionRU    = "Начать работу и сменить язык"
+	startDescriptionEN    = "Start and change the language"
+	setDescriptionRU      = "имя_сервиса логин пароль [--expires срок] - сохранить пароль"
+	setDescriptionEN      = "service_name login password [--expires ttl] - save the password"
+	getDescriptionRU      = "имя_сервиса - показать пароль"
+	getDescriptionEN      = "service_name - show the password"
+	delDescriptionRU      = "имя_сервиса - удалить пароль"
+	delDescriptionEN      = "service_name - delete the password"
+	adminDescriptionRU    = "управление доступом"
+	adminDescriptionEN    = "access management"
+	vaultDescriptionRU    = "хранилища команды"
+	vaultDescriptionEN    = "team vaults"
+	shareDescriptionRU    = "имя_сервиса @пользователь [срок] [ro] - поделиться паролем"
+	shareDescriptionEN    = "service_name @user [ttl] [ro] - share the password"
+	unshareDescriptionRU  = "имя_сервиса @пользователь - забрать доступ"
+	unshareDescriptionEN  = "service_name @user - take the access back"
+	sharesDescriptionRU   = "мои общие пароли"
+	sharesDescriptionEN   = "my shared passwords"
+	oneTimeDescriptionRU  = "имя_сервиса [срок] - одноразовая ссылка на пароль"
+	oneTimeDescriptionEN  = "service_name [ttl] - one-time link to the password"
+	checkDescriptionRU    = "пароль - проверить пароль по утечкам"
+	checkDescriptionEN    = "password - check the password in known breaches"
+	exportDescriptionRU   = "парольная_фраза - зашифрованный экспорт паролей"
+	exportDescriptionEN   = "passphrase - encrypted export of the passwords"
+	importDescriptionRU   = "[парольная_фраза] - импорт из файла"
+	importDescriptionEN   = "[passphrase] - import from a file"
+	auditDescriptionRU    = "проверить надёжность сохранённых паролей"
+	auditDescriptionEN    = "check the health of the saved passwords"
+	rotateDescriptionRU   = "[имя_сервиса] [период|off] - напоминания о смене паролей"
+	rotateDescriptionEN   = "[service_name] [period|off] - password rotation reminders"
+	logDescriptionRU      = "мои последние действия"
+	logDescriptionEN      = "my recent activity"
+	wipeDescriptionRU     = "удалить все мои пароли и настройки"
+	wipeDescriptionEN     = "delete all my passwords and settings"
+	duressDescriptionRU   = "[код|off] - код под принуждением, молча удаляющий все данные"
+	duressDescriptionEN   = "[PIN|off] - duress PIN silently deleting all the data"
+	myDataDescriptionRU   = "что бот хранит обо мне"
+	myDataDescriptionEN   = "what the bot stores about me"
+	forgetMeDescriptionRU = "удалить всё обо мне"
+	forgetMeDescriptionEN = "delete everything about me"
+	fileDescriptionRU     = "[имя_сервиса] [del] - файлы к паролям"
+	fileDescriptionEN     = "[service_name] [del] - files of the passwords"
+	displayDescriptionRU  = "[all|login|password|reveal] - что показывает /get"
+	displayDescriptionEN  = "[all|login|password|reveal] - what /get shows"
 )
 
 // Group of constants for handling messages from user.
@@ -102,61 +1245,227 @@ const (
 	del    = "del"
 	delErr = "delErr"
 
-	hide = "hide"
-
-	changeLang = "changeLang"
-	change     = "change"
+	hideButton       = "hideButton"
+	changeLangButton = "changeLangButton"
 
 	wrongInputErr      = "Wrong input for command"
 	serviceNotFoundErr = "Service not found"
+	internalErr        = "Internal error"
+	privateOnlyErr     = "Private only"
+	tooManyRequestsErr = "Too many requests"
+	expiredButtonErr   = "Expired button"
+	lockedErr          = "Locked"
+	accessDeniedErr    = "Access denied"
+
+	admin        = "admin"
+	adminUsage   = "adminUsage"
+	adminDone    = "adminDone"
+	adminErr     = "adminErr"
+	adminNoUsers = "adminNoUsers"
+	adminStats   = "adminStats"
+
+	vault                = "vault"
+	vaultUsage           = "vaultUsage"
+	vaultCreated         = "vaultCreated"
+	vaultAdded           = "vaultAdded"
+	vaultDone            = "vaultDone"
+	vaultSent            = "vaultSent"
+	vaultNoVaults        = "vaultNoVaults"
+	vaultExistsErr       = "vaultExistsErr"
+	vaultNotFoundErr     = "vaultNotFoundErr"
+	vaultRoleErr         = "vaultRoleErr"
+	vaultLastOwnerErr    = "vaultLastOwnerErr"
+	vaultMemberErr       = "vaultMemberErr"
+	vaultStartPrivateErr = "vaultStartPrivateErr"
+	groupOnlyErr         = "Group only"
+
+	share          = "share"
+	unshare        = "unshare"
+	shares         = "shares"
+	shareDone      = "shareDone"
+	shareNotice    = "shareNotice"
+	unshareDone    = "unshareDone"
+	sharesEmpty    = "sharesEmpty"
+	sharesItem     = "sharesItem"
+	sharesReadOnly = "sharesReadOnly"
+	sharesUntil    = "sharesUntil"
+	unknownUserErr = "Unknown user"
+	shareSelfErr   = "Share self"
+	readOnlyErr    = "Read-only"
+
+	oneTime            = "onetime"
+	oneTimeOpened      = "oneTimeOpened"
+	oneTimeDisabledErr = "One-time disabled"
+
+	check             = "check"
+	breachFound       = "breachFound"
+	breachNotFound    = "breachNotFound"
+	breachCheckOffErr = "Breach check off"
+
+	export              = "export"
+	importCmd           = "import"
+	exportDone          = "exportDone"
+	exportUnnamed       = "exportUnnamed"
+	importDone          = "importDone"
+	importUsage         = "importUsage"
+	exportEmptyErr      = "Nothing to export"
+	weakPassphraseErr   = "Weak passphrase"
+	badExportErr        = "Bad export"
+	documentTooLargeErr = "Document too large"
+	importReport        = "importReport"
+	importConflicts     = "importConflicts"
+	importResolved      = "importResolved"
+	skipButton          = "skipButton"
+	overwriteButton     = "overwriteButton"
+	keepBothButton      = "keepBothButton"
+	unknownFormatErr    = "Unknown format"
+	encryptedFileErr    = "Encrypted file"
+
+	audit          = "audit"
+	strengthResult = "strengthResult"
+	reuseWarning   = "reuseWarning"
+
+	rotate                = "rotate"
+	rotationStatus        = "rotationStatus"
+	rotationOff           = "rotationOff"
+	rotationSet           = "rotationSet"
+	rotationEntrySet      = "rotationEntrySet"
+	rotationDisabled      = "rotationDisabled"
+	rotationEntryDisabled = "rotationEntryDisabled"
+	rotationPeriodErr     = "rotationPeriodErr"
+	rotationReminder      = "rotationReminder"
+	rotationSnoozed       = "rotationSnoozed"
+	rotationGenerated     = "rotationGenerated"
+	rotationNothingDue    = "rotationNothingDue"
+	snoozeButton          = "snoozeButton"
+	generateButton        = "generateButton"
+
+	logCmd           = "log"
+	logTitle         = "logTitle"
+	logEmpty         = "logEmpty"
+	actionGet        = "actionGet"
+	actionSave       = "actionSave"
+	actionDelete     = "actionDelete"
+	actionLang       = "actionLang"
+	outcomeOK        = "outcomeOK"
+	outcomeNotFound  = "outcomeNotFound"
+	outcomeLocked    = "outcomeLocked"
+	outcomeDenied    = "outcomeDenied"
+	outcomeError     = "outcomeError"
+	scoreVeryWeak    = "scoreVeryWeak"
+	scoreWeak        = "scoreWeak"
+	scoreFair        = "scoreFair"
+	scoreGood        = "scoreGood"
+	scoreStrong      = "scoreStrong"
+	patternCommon    = "patternCommon"
+	patternWord      = "patternWord"
+	patternKeyboard  = "patternKeyboard"
+	patternSequence  = "patternSequence"
+	patternRepeat    = "patternRepeat"
+	patternYear      = "patternYear"
+	auditSummary     = "auditSummary"
+	auditUnnamed     = "auditUnnamed"
+	auditReused      = "auditReused"
+	auditOld         = "auditOld"
+	auditNoLogin     = "auditNoLogin"
+	auditEmpty       = "auditEmpty"
+	actionWipe       = "actionWipe"
+	actionFileSave   = "actionFileSave"
+	actionFileGet    = "actionFileGet"
+	actionFileDelete = "actionFileDelete"
+	actionExport     = "actionExport"
+
+	wipe             = "wipe"
+	wipeWarning      = "wipeWarning"
+	wipeButton       = "wipeButton"
+	wipeTypePhrase   = "wipeTypePhrase"
+	wipePhrase       = "wipePhrase"
+	wipePhraseErr    = "wipePhraseErr"
+	wipeNotConfirmed = "wipeNotConfirmed"
+	wipeDone         = "wipeDone"
+	duress           = "duress"
+	duressOn         = "duressOn"
+	duressOff        = "duressOff"
+	duressSet        = "duressSet"
+	duressRemoved    = "duressRemoved"
+	duressPINErr     = "duressPINErr"
+
+	myData              = "mydata"
+	forgetMeCmd         = "forgetme"
+	mydataTitle         = "mydataTitle"
+	mydataServices      = "mydataServices"
+	mydataServicesTimes = "mydataServicesTimes"
+	mydataShares        = "mydataShares"
+	mydataOneTime       = "mydataOneTime"
+	mydataFiles         = "mydataFiles"
+	mydataVaults        = "mydataVaults"
+	mydataLang          = "mydataLang"
+	mydataLangAuto      = "mydataLangAuto"
+	mydataLangNone      = "mydataLangNone"
+	mydataRotation      = "mydataRotation"
+	mydataRotationOff   = "mydataRotationOff"
+	mydataDuressOn      = "mydataDuressOn"
+	mydataDuressOff     = "mydataDuressOff"
+	mydataMember        = "mydataMember"
+	mydataAudit         = "mydataAudit"
+	mydataAuditForever  = "mydataAuditForever"
+	mydataAuditNone     = "mydataAuditNone"
+	mydataFooter        = "mydataFooter"
+	forgetWarning       = "forgetWarning"
+	forgetButton        = "forgetButton"
+	forgotten           = "forgotten"
+
+	fileCmd         = "file"
+	fileUsage       = "fileUsage"
+	fileSaved       = "fileSaved"
+	fileCaption     = "fileCaption"
+	fileDeleted     = "fileDeleted"
+	fileNotFoundErr = "fileNotFoundErr"
+	fileTooLargeErr = "fileTooLargeErr"
+	filesEmpty      = "filesEmpty"
+	filesTitle      = "filesTitle"
+	filePhoto       = "filePhoto"
+
+	expiresFlag   = "--expires"
+	setExpires    = "setExpires"
+	getExpires    = "getExpires"
+	expiryErr     = "expiryErr"
+	expiryWarning = "expiryWarning"
+	expiredNotice = "expiredNotice"
+	actionExpire  = "actionExpire"
+
+	getLogin        = "getLogin"
+	getPassword     = "getPassword"
+	display         = "display"
+	displayStatus   = "displayStatus"
+	displaySet      = "displaySet"
+	displayAll      = "displayAll"
+	displayLogin    = "displayLogin"
+	displayPassword = "displayPassword"
+	mydataDisplay   = "mydataDisplay"
+
+	displayReveal      = "displayReveal"
+	revealedPassword   = "revealedPassword"
+	showPasswordButton = "showPasswordButton"
+	showForButton      = "showForButton"
+	sendPasswordButton = "sendPasswordButton"
 )
 
+// Group of constants for button labels.
 const (
-	hideKeyboard    = "hideKeyboard"
-	setLangKeyboard = "setLangKeyboard"
-	startKeyboard   = "startKeyboard"
-)
-
-// Map of  keyboard buttons.
-var allKeyboards = map[string]keyboards{
-	hideKeyboard: {
-		Russian: tgapi.NewInlineKeyboardMarkup(
-			tgapi.NewInlineKeyboardRow(
-				tgapi.NewInlineKeyboardButtonData("Спрятать \U0001FAE3", hide),
-			),
-		),
-		English: tgapi.NewInlineKeyboardMarkup(
-			tgapi.NewInlineKeyboardRow(
-				tgapi.NewInlineKeyboardButtonData("Hide \U0001FAE3", hide),
-			),
-		),
-	},
-
-	setLangKeyboard: {
-		Russian: tgapi.NewInlineKeyboardMarkup(
-			tgapi.NewInlineKeyboardRow(
-				tgapi.NewInlineKeyboardButtonData("Русский 🇷🇺", change+"::ru"),
-				tgapi.NewInlineKeyboardButtonData("English 🇺🇸", change+"::en"),
-			),
-		),
-		English: tgapi.NewInlineKeyboardMarkup(
-			tgapi.NewInlineKeyboardRow(
-				tgapi.NewInlineKeyboardButtonData("Русский 🇷🇺", change+"::ru"),
-				tgapi.NewInlineKeyboardButtonData("English 🇺🇸", change+"::en"),
-			),
-		),
-	},
-
-	startKeyboard: {
-		Russian: tgapi.NewInlineKeyboardMarkup(
-			tgapi.NewInlineKeyboardRow(
-				tgapi.NewInlineKeyboardButtonData("Сменить язык 🌍", changeLang),
-			),
-		),
-		English: tgapi.NewInlineKeyboardMarkup(
-			tgapi.NewInlineKeyboardRow(
-				tgapi.NewInlineKeyboardButtonData("Change language 🌍", changeLang),
-			),
-		),
-	},
-}
+	hideButtonRU       = "Спрятать \U0001FAE3"
+	hideButtonEN       = "Hide \U0001FAE3"
+	changeLangButtonRU = "Сменить язык 🌍"
+	changeLangButtonEN = "Change language 🌍"
+	russianButton      = "Русский 🇷🇺"
+	englishButton      = "English 🇺🇸"
+
+	showPasswordButtonRU = "Показать пароль 👁"
+	showPasswordButtonEN = "Show password 👁"
+	showForButtonRU      = "Показать на %d с ⏱"
+	showForButtonEN      = "Show for %ds ⏱"
+	sendPasswordButtonRU = "Прислать отдельно и удалить ✉️"
+	sendPasswordButtonEN = "Send as a self-deleting message ✉️"
+
+	chooseLangMessage = "Choose a new language 🌎"
+)
@@ -0,0 +1,142 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"password-keeper/internal/storage"
+	"password-keeper/internal/usecase"
+	"strconv"
+	"strings"
+	"time"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// readOnlyArgs arguments of /share making the share read-only.
+var readOnlyArgs = map[string]struct{}{
+	"ro":        {},
+	"read-only": {},
+	"readonly":  {},
+}
+
+// handleShare handles share command: /share service @user_or_id [ttl] [read-only].
+func (b *Bot) handleShare(req *Request) error {
+	service := req.Args[0]
+
+	var ttl time.Duration
+	var readOnly bool
+	for _, arg := range req.Args[2:] {
+		if _, ok := readOnlyArgs[strings.ToLower(arg)]; ok {
+			readOnly = true
+			continue
+		}
+
+		d, err := parseTTL(arg)
+		if err != nil {
+			b.replyText(req, wrongInputErr)
+			return ErrWrongInput
+		}
+		ttl = d
+	}
+
+	recipient, err := b.logic.FindUser(req.Args[1])
+	if err == nil {
+		err = b.logic.Share(req.ChatID(), service, recipient, ttl, readOnly)
+	}
+	if err != nil {
+		b.replyShareErr(req, err)
+		return fmt.Errorf("share error: %w", err)
+	}
+
+	b.replyText(req, shareDone)
+	b.notifyShare(req, recipient, service)
+	return nil
+}
+
+// notifyShare tells the recipient about the share, the password itself is not sent.
+func (b *Bot) notifyShare(req *Request, recipient int64, service string) {
+	owner := strconv.FormatInt(req.ChatID(), 10)
+	if from := req.Message.From; from != nil && from.UserName != "" {
+		owner = "@" + from.UserName
+	}
+
+	text := fmt.Sprintf(b.handleMessageLang(shareNotice, recipient), owner, service, service)
+	if _, err := b.client.Send(tgapi.NewMessage(recipient, text)); err != nil {
+		b.logger.Warn(fmt.Sprintf("share notice error: user %d: %v", recipient, err))
+	}
+}
+
+// handleUnshare handles unshare command.
+func (b *Bot) handleUnshare(req *Request) error {
+	recipient, err := b.logic.FindUser(req.Args[1])
+	if err == nil {
+		err = b.logic.Unshare(req.ChatID(), req.Args[0], recipient)
+	}
+	if err != nil {
+		b.replyShareErr(req, err)
+		return fmt.Errorf("unshare error: %w", err)
+	}
+
+	b.replyText(req, unshareDone)
+	return nil
+}
+
+// handleShares handles shares command.
+func (b *Bot) handleShares(req *Request) error {
+	list, err := b.logic.Shares(req.ChatID())
+	if err != nil {
+		b.replyText(req, internalErr)
+		return fmt.Errorf("shares error: %w", err)
+	}
+
+	if len(list) == 0 {
+		b.replyText(req, sharesEmpty)
+		return nil
+	}
+
+	var sb strings.Builder
+	for _, sh := range list {
+		fmt.Fprintf(&sb, b.handleMessageLang(sharesItem, req.ChatID()), sh.Name, sh.Recipient, sh.Accesses)
+		if sh.ReadOnly {
+			sb.WriteString(b.handleMessageLang(sharesReadOnly, req.ChatID()))
+		}
+		if !sh.ExpiresAt.IsZero() {
+			fmt.Fprintf(&sb, b.handleMessageLang(sharesUntil, req.ChatID()), sh.ExpiresAt.UTC().Format("2006-01-02 15:04 UTC"))
+		}
+		sb.WriteString("\n")
+	}
+
+	b.reply(req, tgapi.NewMessage(req.ChatID(), sb.String()))
+	return nil
+}
+
+// replyShareErr replies with the message explaining the error.
+func (b *Bot) replyShareErr(req *Request, err error) {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		b.replyText(req, serviceNotFoundErr)
+	case errors.Is(err, usecase.ErrUnknownUser):
+		b.replyText(req, unknownUserErr)
+	case errors.Is(err, usecase.ErrShareSelf):
+		b.replyText(req, shareSelfErr)
+	default:
+		b.replyText(req, internalErr)
+	}
+}
+
+// parseTTL parses durations like time.ParseDuration and also days, e.g. "7d".
+func parseTTL(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		n, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil || n <= 0 {
+			return 0, ErrWrongInput
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, ErrWrongInput
+	}
+	return d, nil
+}
@@ -0,0 +1,282 @@
+// Package telegramtest provides a fake Telegram Bot API server for end-to-end tests.
+//
+// The server keeps everything in memory: updates pushed by the test are
+// returned from getUpdates, and every other request is recorded, so the test
+// can check what the bot has sent, edited or deleted.
+package telegramtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	Params url.Values
+	// Files uploaded files by the field name, e.g. "document".
+	Files map[string][]byte
+}
+
+// ChatID returns the chat_id parameter.
+func (r Request) ChatID() int64 {
+	id, _ := strconv.ParseInt(r.Params.Get("chat_id"), 10, 64)
+	return id
+}
+
+// MessageID returns the message_id parameter.
+func (r Request) MessageID() int {
+	id, _ := strconv.Atoi(r.Params.Get("message_id"))
+	return id
+}
+
+// Text returns the text parameter.
+func (r Request) Text() string {
+	return r.Params.Get("text")
+}
+
+// ErrTimeout occurs when the expected requests were not received in time.
+var ErrTimeout = errors.New("timeout waiting for requests")
+
+// Server is a fake Telegram Bot API server.
+type Server struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	updates      []tgapi.Update
+	lastUpdateID int
+	lastID       int
+	requests     []Request
+	files        map[string][]byte
+}
+
+// NewServer starts a new server. The caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{lastID: 1000, files: make(map[string][]byte)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Endpoint returns the endpoint to be used instead of tgapi.APIEndpoint.
+func (s *Server) Endpoint() string {
+	return s.URL + "/bot%s/%s"
+}
+
+// PushUpdate queues the update for getUpdates.
+func (s *Server) PushUpdate(update tgapi.Update) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastUpdateID++
+	update.UpdateID = s.lastUpdateID
+	s.updates = append(s.updates, update)
+}
+
+// AddFile makes the file available for getFile and downloads.
+func (s *Server) AddFile(fileID string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files[fileID] = data
+}
+
+// Requests returns the received requests of the method.
+func (s *Server) Requests(method string) []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var requests []Request
+	for _, r := range s.requests {
+		if r.Method == method {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+// WaitRequests waits until the server receives at least n requests of the method.
+func (s *Server) WaitRequests(method string, n int, timeout time.Duration) ([]Request, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		requests := s.Requests(method)
+		if len(requests) >= n {
+			return requests, nil
+		}
+
+		if time.Now().After(deadline) {
+			return requests, fmt.Errorf("%s: got %d, want %d: %w", method, len(requests), n, ErrTimeout)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/file/") {
+		s.download(w, r)
+		return
+	}
+
+	err := r.ParseMultipartForm(32 << 20)
+	if err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result interface{} = true
+	switch method {
+	case "getMe":
+		result = tgapi.User{ID: 1, IsBot: true, FirstName: "Keeper", UserName: "keeper_test_bot"}
+	case "getUpdates":
+		result = s.updates
+		s.updates = nil
+	case "editMessageText":
+		s.requests = append(s.requests, Request{Method: method, Params: r.Form})
+		result = s.message(r.Form, atoi(r.Form.Get("message_id")))
+	case "getFile":
+		fileID := r.Form.Get("file_id")
+		if _, ok := s.files[fileID]; !ok {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(tgapi.APIResponse{Ok: false, ErrorCode: http.StatusBadRequest, Description: "Bad Request: invalid file_id"})
+			return
+		}
+		result = tgapi.File{FileID: fileID, FileSize: len(s.files[fileID]), FilePath: "documents/" + fileID}
+	case "sendMessage", "sendDocument", "sendPhoto":
+		s.requests = append(s.requests, Request{Method: method, Params: r.Form, Files: files(r)})
+		s.lastID++
+		result = s.message(r.Form, s.lastID)
+	default:
+		s.requests = append(s.requests, Request{Method: method, Params: r.Form})
+	}
+
+	raw, err := json.Marshal(result)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tgapi.APIResponse{Ok: true, Result: raw})
+}
+
+// download serves the files added with AddFile.
+func (s *Server) download(w http.ResponseWriter, r *http.Request) {
+	fileID := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	s.mu.Lock()
+	data, ok := s.files[fileID]
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Write(data)
+}
+
+// files returns the uploaded files of the request.
+func files(r *http.Request) map[string][]byte {
+	if r.MultipartForm == nil {
+		return nil
+	}
+
+	files := make(map[string][]byte)
+	for name, headers := range r.MultipartForm.File {
+		f, err := headers[0].Open()
+		if err != nil {
+			continue
+		}
+		files[name], _ = io.ReadAll(f)
+		f.Close()
+	}
+	return files
+}
+
+func (s *Server) message(params url.Values, id int) tgapi.Message {
+	chatID, _ := strconv.ParseInt(params.Get("chat_id"), 10, 64)
+	return tgapi.Message{
+		MessageID: id,
+		Chat:      &tgapi.Chat{ID: chatID, Type: "private"},
+		Text:      params.Get("text"),
+		Date:      int(time.Now().Unix()),
+	}
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// Command returns an update with the command sent by the user to the private chat.
+func Command(chatID int64, messageID int, text string) tgapi.Update {
+	cmd := strings.SplitN(text, " ", 2)[0]
+	return tgapi.Update{
+		Message: &tgapi.Message{
+			MessageID: messageID,
+			From:      &tgapi.User{ID: chatID, LanguageCode: "en"},
+			Chat:      &tgapi.Chat{ID: chatID, Type: "private"},
+			Date:      int(time.Now().Unix()),
+			Text:      text,
+			Entities:  []tgapi.MessageEntity{{Type: "bot_command", Length: len(cmd)}},
+		},
+	}
+}
+
+// Text returns an update with the plain text sent by the user to the private chat.
+func Text(chatID int64, messageID int, text string) tgapi.Update {
+	update := Command(chatID, messageID, text)
+	update.Message.Entities = nil
+	return update
+}
+
+// Document returns an update with the document sent by the user to the private chat.
+// The caption may contain a command, like the text of Command.
+func Document(chatID int64, messageID int, fileID, caption string) tgapi.Update {
+	update := Command(chatID, messageID, caption)
+	msg := update.Message
+	if strings.HasPrefix(caption, "/") {
+		msg.CaptionEntities = msg.Entities
+	}
+	msg.Caption, msg.Text, msg.Entities = caption, "", nil
+	msg.Document = &tgapi.Document{FileID: fileID, FileName: fileID}
+	return update
+}
+
+// Photo returns an update with the photo sent by the user to the private chat.
+// The caption may contain a command, like the text of Command.
+func Photo(chatID int64, messageID int, fileID, caption string) tgapi.Update {
+	update := Document(chatID, messageID, fileID, caption)
+	update.Message.Document = nil
+	update.Message.Photo = []tgapi.PhotoSize{{FileID: fileID + "-thumb", Width: 90}, {FileID: fileID, Width: 1280}}
+	return update
+}
+
+// Callback returns an update with the button pressed under the message.
+func Callback(chatID int64, messageID int, data string) tgapi.Update {
+	return tgapi.Update{
+		CallbackQuery: &tgapi.CallbackQuery{
+			ID:   strconv.Itoa(messageID) + data,
+			From: &tgapi.User{ID: chatID, LanguageCode: "en"},
+			Message: &tgapi.Message{
+				MessageID: messageID,
+				Chat:      &tgapi.Chat{ID: chatID, Type: "private"},
+			},
+			Data: data,
+		},
+	}
+}
@@ -0,0 +1,322 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"password-keeper/internal/entity"
+	"password-keeper/internal/storage"
+	"password-keeper/internal/usecase"
+	"strconv"
+	"strings"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// vaultCommand subcommand of /vault.
+type vaultCommand struct {
+	// group subcommands manage the vault of the group and run in groups only,
+	// the others deal with secrets and run in private chats only.
+	group bool
+	// args number of arguments after the subcommand.
+	args    int
+	handler func(req *Request, args []string) error
+}
+
+func (b *Bot) vaultCommands() map[string]vaultCommand {
+	return map[string]vaultCommand{
+		"create":  {group: true, args: 1, handler: b.vaultCreate},
+		"add":     {group: true, args: 2, handler: b.vaultAdd},
+		"members": {group: true, handler: b.vaultMembers},
+		"role":    {group: true, args: 2, handler: b.vaultRole},
+		"kick":    {group: true, args: 1, handler: b.vaultKick},
+		"leave":   {group: true, handler: b.vaultLeave},
+		"list":    {handler: b.vaultList},
+		"set":     {args: 4, handler: b.vaultSet},
+		// get and del take the service in groups and the vault and the service in private chats
+		"get": {args: -1, handler: b.vaultGet},
+		"del": {args: -1, handler: b.vaultDel},
+	}
+}
+
+// handleVault handles vault command.
+func (b *Bot) handleVault(req *Request) error {
+	if len(req.Args) == 0 || req.Message.From == nil {
+		b.replyText(req, vaultUsage)
+		return nil
+	}
+
+	cmd, ok := b.vaultCommands()[req.Args[0]]
+	if !ok {
+		b.replyText(req, vaultUsage)
+		return nil
+	}
+
+	args := req.Args[1:]
+	isGroup := req.Message.Chat.IsGroup() || req.Message.Chat.IsSuperGroup()
+
+	switch {
+	case cmd.args < 0:
+		// the vault is known from the group
+		want := 2
+		if isGroup {
+			want = 1
+		}
+		if len(args) != want {
+			b.replyText(req, wrongInputErr)
+			return ErrWrongInput
+		}
+	case len(args) != cmd.args:
+		b.replyText(req, wrongInputErr)
+		return ErrWrongInput
+	case cmd.group && !isGroup:
+		b.replyText(req, groupOnlyErr)
+		return ErrForbidden
+	case !cmd.group && !req.Message.Chat.IsPrivate():
+		b.replyText(req, privateOnlyErr)
+		return ErrForbidden
+	}
+
+	if err := cmd.handler(req, args); err != nil {
+		return fmt.Errorf("vault %s error: %w", req.Args[0], err)
+	}
+	return nil
+}
+
+func (b *Bot) vaultCreate(req *Request, args []string) error {
+	err := b.logic.CreateVault(req.ChatID(), req.Message.From.ID, args[0])
+	if err != nil {
+		b.replyVaultErr(req, err)
+		return err
+	}
+
+	b.reply(req, tgapi.NewMessage(req.ChatID(),
+		fmt.Sprintf(b.handleMessageLang(vaultCreated, req.ChatID()), args[0])))
+	return nil
+}
+
+func (b *Bot) vaultAdd(req *Request, args []string) error {
+	userID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		b.replyText(req, wrongInputErr)
+		return ErrWrongInput
+	}
+
+	if err := b.logic.AddVaultMember(req.ChatID(), req.Message.From.ID, userID, args[1]); err != nil {
+		b.replyVaultErr(req, err)
+		return err
+	}
+
+	b.replyText(req, vaultAdded)
+	return nil
+}
+
+func (b *Bot) vaultMembers(req *Request, _ []string) error {
+	members, err := b.logic.VaultMembers(req.ChatID(), req.Message.From.ID)
+	if err != nil {
+		b.replyVaultErr(req, err)
+		return err
+	}
+
+	var sb strings.Builder
+	for _, m := range members {
+		fmt.Fprintf(&sb, "%d %s\n", m.UserID, m.Role)
+	}
+
+	b.reply(req, tgapi.NewMessage(req.ChatID(), sb.String()))
+	return nil
+}
+
+func (b *Bot) vaultRole(req *Request, args []string) error {
+	userID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		b.replyText(req, wrongInputErr)
+		return ErrWrongInput
+	}
+
+	if err := b.logic.SetVaultRole(req.ChatID(), req.Message.From.ID, userID, args[1]); err != nil {
+		b.replyVaultErr(req, err)
+		return err
+	}
+
+	b.replyText(req, vaultDone)
+	return nil
+}
+
+func (b *Bot) vaultKick(req *Request, args []string) error {
+	userID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		b.replyText(req, wrongInputErr)
+		return ErrWrongInput
+	}
+
+	return b.vaultRemove(req, userID)
+}
+
+func (b *Bot) vaultLeave(req *Request, _ []string) error {
+	return b.vaultRemove(req, req.Message.From.ID)
+}
+
+func (b *Bot) vaultRemove(req *Request, userID int64) error {
+	if err := b.logic.RemoveVaultMember(req.ChatID(), req.Message.From.ID, userID); err != nil {
+		b.replyVaultErr(req, err)
+		return err
+	}
+
+	b.replyText(req, vaultDone)
+	return nil
+}
+
+func (b *Bot) vaultList(req *Request, _ []string) error {
+	vaults, err := b.logic.UserVaults(req.Message.From.ID)
+	if err != nil {
+		b.replyVaultErr(req, err)
+		return err
+	}
+
+	if len(vaults) == 0 {
+		b.replyText(req, vaultNoVaults)
+		return nil
+	}
+
+	names := make([]string, 0, len(vaults))
+	for _, v := range vaults {
+		names = append(names, "🗄 "+v.Name)
+	}
+
+	b.reply(req, tgapi.NewMessage(req.ChatID(), strings.Join(names, "\n")))
+	return nil
+}
+
+func (b *Bot) vaultSet(req *Request, args []string) error {
+	v, err := b.logic.FindVault(req.Message.From.ID, args[0])
+	if err == nil {
+		err = b.logic.VaultSave(v.ID, req.Message.From.ID, args[1], args[2], args[3])
+	}
+	if err != nil {
+		b.replyVaultErr(req, err)
+		return err
+	}
+
+	b.replyText(req, set)
+	return nil
+}
+
+// vaultGet sends the password to the private chat of the member,
+// so it's never shown in the group.
+func (b *Bot) vaultGet(req *Request, args []string) error {
+	userID := req.Message.From.ID
+	vaultID, service, err := b.vaultTarget(req, args)
+	if err != nil {
+		b.replyVaultErr(req, err)
+		return err
+	}
+
+	pair, err := b.logic.VaultGet(vaultID, userID, service)
+	if err != nil {
+		b.replyVaultErr(req, err)
+		return err
+	}
+
+	// the command is deleted with the reply in the private chat, the group keeps it
+	var command []int
+	if req.Message.Chat.IsPrivate() {
+		command = append(command, req.Message.MessageID)
+	}
+
+	mode := b.displayMode(userID)
+	msgConfig := tgapi.NewMessage(userID, b.credentials(userID, mode, service, pair))
+	msgConfig.ParseMode = tgapi.ModeHTML
+	hide := b.hideKeyboard(userID, command...)
+	msgConfig.ReplyMarkup = hide
+	masked := reveal{
+		service: service,
+		text:    msgConfig.Text,
+		hide:    hide,
+		lookup: func() (entity.Pair, error) {
+			return b.logic.VaultGet(vaultID, userID, service)
+		},
+	}
+
+	if req.Message.Chat.IsPrivate() {
+		if mode == usecase.DisplayReveal {
+			b.replyMasked(req, masked)
+			return nil
+		}
+		b.reply(req, msgConfig)
+		return nil
+	}
+
+	if mode == usecase.DisplayReveal {
+		msgConfig = b.maskedMessage(userID, masked)
+	}
+	m, err := b.client.Send(msgConfig)
+	if err != nil {
+		// the bot can't start a private chat itself
+		b.replyText(req, vaultStartPrivateErr)
+		return err
+	}
+
+	if mode == usecase.DisplayReveal {
+		b.reveals.put(userID, m.MessageID, masked)
+	}
+	b.hideLater(&m)
+	b.replyText(req, vaultSent)
+	return nil
+}
+
+func (b *Bot) vaultDel(req *Request, args []string) error {
+	vaultID, service, err := b.vaultTarget(req, args)
+	if err == nil {
+		err = b.logic.VaultDelete(vaultID, req.Message.From.ID, service)
+	}
+	if err != nil {
+		b.replyVaultErr(req, err)
+		return err
+	}
+
+	b.replyText(req, del)
+	return nil
+}
+
+// vaultTarget returns the vault and the service from the arguments,
+// in groups the vault is the one of the group.
+func (b *Bot) vaultTarget(req *Request, args []string) (int64, string, error) {
+	if len(args) == 1 {
+		return req.ChatID(), args[0], nil
+	}
+
+	v, err := b.logic.FindVault(req.Message.From.ID, args[0])
+	if err != nil {
+		return 0, "", err
+	}
+	return v.ID, args[1], nil
+}
+
+// replyVaultErr replies with the message explaining the error.
+func (b *Bot) replyVaultErr(req *Request, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrLocked):
+		b.reply(req, tgapi.NewMessage(req.ChatID(), b.lockedMessage(req.Message.From.ID)))
+	case errors.Is(err, storage.ErrNotFound):
+		b.replyText(req, serviceNotFoundErr)
+	case errors.Is(err, usecase.ErrNoVault):
+		b.replyText(req, vaultNotFoundErr)
+	case errors.Is(err, usecase.ErrVaultExists):
+		b.replyText(req, vaultExistsErr)
+	case errors.Is(err, usecase.ErrVaultRole):
+		b.replyText(req, vaultRoleErr)
+	case errors.Is(err, usecase.ErrLastOwner):
+		b.replyText(req, vaultLastOwnerErr)
+	case errors.Is(err, usecase.ErrVaultMember):
+		b.replyText(req, vaultMemberErr)
+	case errors.Is(err, usecase.ErrWrongRole):
+		b.replyText(req, wrongInputErr)
+	default:
+		b.replyText(req, internalErr)
+	}
+}
+
+// replyText replies with the message in the language of the chat.
+func (b *Bot) replyText(req *Request, msg string) {
+	b.reply(req, tgapi.NewMessage(req.ChatID(), b.handleMessageLang(msg, req.ChatID())))
+}
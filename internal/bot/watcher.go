@@ -1,8 +1,10 @@
 package bot
 
 import (
+	"context"
 	"fmt"
 	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"sort"
 	"time"
 )
 
@@ -13,30 +15,147 @@ type MessageInfo struct {
 	createdAt time.Time
 }
 
-// Watch watches messages and deletes them after hideInterval.
-func (b *Bot) Watch() (chan MessageInfo, func()) {
+// Watch watches messages and deletes them hideInterval after their createdAt,
+// the messages queued with another createdAt are deleted in the order of their deadlines.
+// The returned functions stop watching and delete all the queued messages at once,
+// and delete the queued messages of one chat right away.
+// The channel is never closed, so handlers still running may keep sending to it.
+func (b *Bot) Watch() (chan MessageInfo, func(ctx context.Context) error, func(chatID int64)) {
 	messagesCh := make(chan MessageInfo, 10000)
-	cancelCh := make(chan bool)
+	stopCh := make(chan context.Context)
+	chatCh := make(chan int64)
+	doneCh := make(chan struct{})
 
 	go func() {
+		defer close(doneCh)
+
+		// the messages taken from the channel, ordered by deadline
+		var pending []MessageInfo
+		var timer *time.Timer
+		var due <-chan time.Time
+		stopTimer := func() {
+			if timer != nil {
+				timer.Stop()
+			}
+			timer, due = nil, nil
+		}
+
 		for {
+			// the handlers wait while too many messages are pending
+			in := messagesCh
+			if len(pending) >= cap(messagesCh) {
+				in = nil
+			}
+
 			select {
-			case <-cancelCh:
-				close(messagesCh)
+			case ctx := <-stopCh:
+				stopTimer()
+				b.flush(ctx, b.takeQueued(pending, messagesCh))
 				return
-			case msg := <-messagesCh:
-				for time.Now().Unix()-b.hideInterval <= msg.createdAt.Unix() {
-				}
-
-				msgDelConfig := tgapi.NewDeleteMessage(msg.chatID, msg.id)
-				if _, err := b.Send(msgDelConfig); err != nil {
-					b.logger.Warn(fmt.Sprintf("del error: %v", err.Error()))
-				}
+			case chatID := <-chatCh:
+				pending = b.flushChat(chatID, b.takeQueued(pending, messagesCh))
+			case msg := <-in:
+				pending = b.enqueue(pending, msg)
+			case <-due:
+				pending = b.deleteDue(pending)
+			}
+
+			stopTimer()
+			if len(pending) > 0 {
+				timer = time.NewTimer(time.Until(b.deadline(pending[0])))
+				due = timer.C
 			}
 		}
 	}()
 
-	return messagesCh, func() {
-		close(cancelCh)
+	stop := func(ctx context.Context) error {
+		select {
+		case stopCh <- ctx:
+		case <-doneCh:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		select {
+		case <-doneCh:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	flushChat := func(chatID int64) {
+		select {
+		case chatCh <- chatID:
+		case <-doneCh:
+		}
+	}
+
+	return messagesCh, stop, flushChat
+}
+
+// deadline returns the time the message is deleted at.
+func (b *Bot) deadline(msg MessageInfo) time.Time {
+	return msg.createdAt.Add(time.Duration(b.hideInterval) * time.Second)
+}
+
+// enqueue inserts the message into the pending ones after those with the same or an earlier deadline.
+func (b *Bot) enqueue(pending []MessageInfo, msg MessageInfo) []MessageInfo {
+	i := sort.Search(len(pending), func(i int) bool { return pending[i].createdAt.After(msg.createdAt) })
+	pending = append(pending, MessageInfo{})
+	copy(pending[i+1:], pending[i:])
+	pending[i] = msg
+	return pending
+}
+
+// takeQueued moves the messages waiting in the channel to the pending ones.
+func (b *Bot) takeQueued(pending []MessageInfo, messagesCh chan MessageInfo) []MessageInfo {
+	for n := len(messagesCh); n > 0; n-- {
+		pending = b.enqueue(pending, <-messagesCh)
+	}
+	return pending
+}
+
+// deleteDue deletes the pending messages whose deadline has passed and returns the rest.
+func (b *Bot) deleteDue(pending []MessageInfo) []MessageInfo {
+	now := time.Now()
+	for len(pending) > 0 && !b.deadline(pending[0]).After(now) {
+		b.deleteMessage(pending[0])
+		pending = pending[1:]
+	}
+	return pending
+}
+
+// flushChat deletes the pending messages of the chat and returns the others.
+func (b *Bot) flushChat(chatID int64, pending []MessageInfo) []MessageInfo {
+	rest := pending[:0]
+	for _, msg := range pending {
+		if msg.chatID == chatID {
+			b.deleteMessage(msg)
+			continue
+		}
+		rest = append(rest, msg)
+	}
+	return rest
+}
+
+// flush deletes all the pending messages until ctx is done.
+func (b *Bot) flush(ctx context.Context, pending []MessageInfo) {
+	for i, msg := range pending {
+		select {
+		case <-ctx.Done():
+			b.logger.Warn(fmt.Sprintf("%d messages left undeleted: %v", len(pending)-i, ctx.Err()))
+			return
+		default:
+			b.deleteMessage(msg)
+		}
+	}
+}
+
+func (b *Bot) deleteMessage(msg MessageInfo) {
+	msgDelConfig := tgapi.NewDeleteMessage(msg.chatID, msg.id)
+	if _, err := b.client.Request(msgDelConfig); err != nil {
+		b.logger.Warn(fmt.Sprintf("del error: %v", err.Error()))
 	}
 }
@@ -0,0 +1,147 @@
+package bot
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// secretTokenHeader header Telegram puts the webhook secret token in.
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// WebhookConfig contains settings for receiving updates via webhook.
+type WebhookConfig struct {
+	// URL public address Telegram sends updates to.
+	URL string
+	// Listen address of the built-in HTTP server.
+	Listen string
+	// Secret is sent by Telegram in the X-Telegram-Bot-Api-Secret-Token header.
+	Secret string
+	// CertFile and KeyFile enable TLS on the built-in server.
+	// Leave them empty when TLS is terminated by a reverse proxy.
+	CertFile string
+	KeyFile  string
+	// SelfSigned uploads CertFile to Telegram, so it trusts a self-signed certificate.
+	SelfSigned bool
+}
+
+// ErrWrongSecret occurs when the webhook request has a wrong secret token.
+var ErrWrongSecret = errors.New("wrong secret token")
+
+// listenWebhook registers the webhook and starts the HTTP server receiving updates.
+func (b *Bot) listenWebhook() (tgapi.UpdatesChannel, error) {
+	link, err := url.Parse(b.webhook.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parse webhook url: %w", err)
+	}
+
+	ln, err := net.Listen("tcp", b.webhook.Listen)
+	if err != nil {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	if err := b.setWebhook(link); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("set webhook: %w", err)
+	}
+
+	path := link.Path
+	if path == "" {
+		path = "/"
+	}
+
+	updates := make(chan tgapi.Update, b.api.Buffer)
+	mux := http.NewServeMux()
+	mux.Handle(path, b.webhookHandler(updates))
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	b.serverMu.Lock()
+	b.server = server
+	b.serverMu.Unlock()
+
+	go func() {
+		var err error
+		if b.webhook.CertFile != "" && b.webhook.KeyFile != "" {
+			err = server.ServeTLS(ln, b.webhook.CertFile, b.webhook.KeyFile)
+		} else {
+			err = server.Serve(ln)
+		}
+
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			b.logger.Error(fmt.Sprintf("webhook server error: %v", err))
+		}
+	}()
+
+	return updates, nil
+}
+
+// setWebhook tells Telegram where to send updates.
+func (b *Bot) setWebhook(link *url.URL) error {
+	params := make(tgapi.Params)
+	params["url"] = link.String()
+	params.AddNonEmpty("secret_token", b.webhook.Secret)
+
+	var err error
+	if b.webhook.SelfSigned {
+		_, err = b.api.UploadFiles("setWebhook", params, []tgapi.RequestFile{{
+			Name: "certificate",
+			Data: tgapi.FilePath(b.webhook.CertFile),
+		}})
+	} else {
+		_, err = b.api.MakeRequest("setWebhook", params)
+	}
+
+	return err
+}
+
+// webhookHandler accepts updates from Telegram and passes them to the updates channel.
+func (b *Bot) webhookHandler(updates chan<- tgapi.Update) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		secret := r.Header.Get(secretTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(b.webhook.Secret)) != 1 {
+			b.logger.Warn(fmt.Sprintf("webhook error: %v from %s", ErrWrongSecret, r.RemoteAddr))
+			http.Error(w, ErrWrongSecret.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		update, err := b.api.HandleUpdate(r)
+		if err != nil {
+			b.logger.Warn(fmt.Sprintf("webhook error: %v", err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case updates <- *update:
+		case <-r.Context().Done():
+			http.Error(w, r.Context().Err().Error(), http.StatusServiceUnavailable)
+		}
+	}
+}
+
+// stopWebhook stops the HTTP server and waits for the in-flight requests.
+func (b *Bot) stopWebhook(ctx context.Context) {
+	b.serverMu.Lock()
+	server := b.server
+	b.serverMu.Unlock()
+
+	if server == nil {
+		return
+	}
+
+	if err := server.Shutdown(ctx); err != nil {
+		b.logger.Warn(fmt.Sprintf("webhook shutdown error: %v", err))
+		server.Close()
+	}
+}
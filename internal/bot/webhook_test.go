@@ -0,0 +1,96 @@
+package bot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+)
+
+func TestBot_webhookHandler(t *testing.T) {
+	b := &Bot{
+		api:     &tgapi.BotAPI{},
+		logger:  zap.NewNop(),
+		webhook: &WebhookConfig{Secret: "secret"},
+	}
+
+	type args struct {
+		method string
+		secret string
+		body   string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantStatus int
+		wantUpdate bool
+	}{
+		{
+			name: "ok",
+			args: args{
+				method: http.MethodPost,
+				secret: "secret",
+				body:   `{"update_id": 1, "message": {"message_id": 2, "text": "/start"}}`,
+			},
+			wantStatus: http.StatusOK,
+			wantUpdate: true,
+		},
+		{
+			name: "wrong secret",
+			args: args{
+				method: http.MethodPost,
+				secret: "wrong",
+				body:   `{"update_id": 1}`,
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "no secret",
+			args: args{
+				method: http.MethodPost,
+				body:   `{"update_id": 1}`,
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "wrong method",
+			args: args{
+				method: http.MethodGet,
+				secret: "secret",
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "wrong body",
+			args: args{
+				method: http.MethodPost,
+				secret: "secret",
+				body:   `{"update_id":`,
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates := make(chan tgapi.Update, 1)
+
+			r := httptest.NewRequest(tt.args.method, "/", strings.NewReader(tt.args.body))
+			if tt.args.secret != "" {
+				r.Header.Set(secretTokenHeader, tt.args.secret)
+			}
+			w := httptest.NewRecorder()
+
+			b.webhookHandler(updates).ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("webhookHandler() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := len(updates) == 1; got != tt.wantUpdate {
+				t.Errorf("webhookHandler() update received = %v, want %v", got, tt.wantUpdate)
+			}
+		})
+	}
+}
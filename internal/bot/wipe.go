@@ -0,0 +1,147 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"password-keeper/internal/bot/callback"
+	"password-keeper/internal/usecase"
+	"strings"
+	"sync"
+	"time"
+
+	tgapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// wipeConfirmTTL time to press the button and then to send the phrase.
+const wipeConfirmTTL = 2 * time.Minute
+
+// duressOffArg argument of /duress removing the PIN.
+const duressOffArg = "off"
+
+// confirmations chats which pressed the button, until the confirmation expires.
+type confirmations struct {
+	mu      sync.Mutex
+	expires map[int64]time.Time
+}
+
+func newConfirmations() *confirmations {
+	return &confirmations{expires: make(map[int64]time.Time)}
+}
+
+// confirm remembers the chat for wipeConfirmTTL.
+func (c *confirmations) confirm(chatID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for id, expires := range c.expires {
+		if now.After(expires) {
+			delete(c.expires, id)
+		}
+	}
+	c.expires[chatID] = now.Add(wipeConfirmTTL)
+}
+
+// take returns true and forgets the chat when its confirmation hasn't expired.
+func (c *confirmations) take(chatID int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires, ok := c.expires[chatID]
+	delete(c.expires, chatID)
+	return ok && time.Now().Before(expires)
+}
+
+// handleWipe handles wipe command: /wipe warns and shows the button,
+// /wipe phrase deletes everything after the button was pressed.
+func (b *Bot) handleWipe(req *Request) error {
+	chatID := req.ChatID()
+	if len(req.Args) == 0 {
+		msgConfig := tgapi.NewMessage(chatID, b.handleMessageLang(wipeWarning, chatID))
+		msgConfig.ReplyMarkup = tgapi.NewInlineKeyboardMarkup(tgapi.NewInlineKeyboardRow(
+			b.button(chatID, b.handleMessageLang(wipeButton, chatID),
+				callback.Data{Action: callback.ConfirmWipe, Expires: time.Now().Add(wipeConfirmTTL)}),
+		))
+		b.reply(req, msgConfig)
+		return nil
+	}
+
+	if !strings.EqualFold(strings.Join(req.Args, " "), b.handleMessageLang(wipePhrase, chatID)) {
+		b.replyText(req, wipePhraseErr)
+		return nil
+	}
+	if !b.wipes.take(chatID) {
+		b.replyText(req, wipeNotConfirmed)
+		return nil
+	}
+
+	// the language is wiped too, so the reply is localized before
+	done := b.handleMessageLang(wipeDone, chatID)
+	if err := b.logic.Wipe(chatID); err != nil {
+		b.replyText(req, internalErr)
+		return fmt.Errorf("wipe error: %w", err)
+	}
+	b.forgetChat(chatID)
+
+	b.reply(req, tgapi.NewMessage(chatID, done))
+	return nil
+}
+
+// forgetChat drops the import conflicts, the wipe confirmation and the masked messages of the chat
+// and deletes its messages queued for deletion right away, after the chat was wiped.
+func (b *Bot) forgetChat(chatID int64) {
+	b.conflicts.take(chatID)
+	b.wipes.take(chatID)
+	b.reveals.forget(chatID)
+	b.hideChat(chatID)
+}
+
+// confirmWipe handles the button of the wipe warning, the phrase is asked for next.
+func (b *Bot) confirmWipe(query *tgapi.CallbackQuery) {
+	chatID := query.Message.Chat.ID
+	b.wipes.confirm(chatID)
+
+	msg := tgapi.NewEditMessageText(chatID, query.Message.MessageID,
+		fmt.Sprintf(b.handleMessageLang(wipeTypePhrase, chatID),
+			int(wipeConfirmTTL/time.Minute), b.handleMessageLang(wipePhrase, chatID)))
+	if _, err := b.client.Send(msg); err != nil {
+		b.logger.Warn(fmt.Sprintf("send error: %v", err.Error()))
+	}
+}
+
+// handleDuress handles duress command: /duress [PIN|off].
+func (b *Bot) handleDuress(req *Request) error {
+	chatID := req.ChatID()
+	if len(req.Args) == 0 {
+		on, err := b.logic.HasDuressPIN(chatID)
+		if err != nil {
+			b.replyText(req, internalErr)
+			return fmt.Errorf("duress error: %w", err)
+		}
+
+		if on {
+			b.replyText(req, duressOn)
+		} else {
+			b.replyText(req, duressOff)
+		}
+		return nil
+	}
+
+	pin, text := req.Args[0], duressSet
+	if strings.EqualFold(pin, duressOffArg) {
+		pin, text = "", duressRemoved
+	}
+
+	err := b.logic.SetDuressPIN(chatID, pin)
+	switch {
+	case errors.Is(err, usecase.ErrDuressPIN):
+		b.reply(req, tgapi.NewMessage(chatID, fmt.Sprintf(b.handleMessageLang(duressPINErr, chatID), usecase.MinDuressPINLen)))
+		return nil
+	case err != nil:
+		b.replyText(req, internalErr)
+		return fmt.Errorf("duress error: %w", err)
+	}
+
+	b.replyText(req, text)
+	return nil
+}
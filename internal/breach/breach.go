@@ -0,0 +1,79 @@
+// Package breach checks passwords against a local copy of the Have I Been Pwned
+// Pwned Passwords dataset, nothing is sent over the network.
+//
+// The dataset is a directory of range files, as downloaded by the PwnedPasswordsDownloader:
+// the file 21BD1.txt holds the SHA-1 hashes starting with 21BD1, one per line,
+// as the rest of the hash in upper case hex and the number of breaches:
+//
+//	0018A45C4D1DEF81644B54AB7F969B88D65:1
+//	00D4F6E8FA6EECAD2A3AA415EEC418D38EC:2
+package breach
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// prefixLen length of the hash prefix in the names of the range files.
+const prefixLen = 5
+
+// ErrNoRange is returned when the dataset has no file for the prefix of the password, it's incomplete.
+var ErrNoRange = errors.New("range file not found")
+
+// Checker checks passwords against the dataset.
+type Checker struct {
+	dir string
+}
+
+// New creates a checker of the dataset in the directory.
+func New(dir string) (*Checker, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("breach dataset: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("breach dataset: %s is not a directory", dir)
+	}
+
+	return &Checker{dir: dir}, nil
+}
+
+// Count returns the number of breaches the password appears in, 0 if none.
+func (c *Checker) Count(password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLen], hash[prefixLen:]
+
+	f, err := os.Open(filepath.Join(c.dir, prefix+".txt"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, ErrNoRange
+		}
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		i := strings.IndexByte(line, ':')
+		if i < 0 || !strings.EqualFold(line[:i], suffix) {
+			continue
+		}
+
+		count, err := strconv.Atoi(line[i+1:])
+		if err != nil {
+			return 0, fmt.Errorf("range file %s: %w", prefix, err)
+		}
+		return count, nil
+	}
+
+	return 0, scanner.Err()
+}
@@ -0,0 +1,54 @@
+package breach
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChecker_Count(t *testing.T) {
+	dir := t.TempDir()
+
+	// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+	rangeFile := "003D68EB55068C33ACE09247EE4C639306B:3\r\n" +
+		"1E4C9B93F3F0682250B6CF8331B7EE68FD8:9659365\r\n"
+	if err := os.WriteFile(filepath.Join(dir, "5BAA6.txt"), []byte(rangeFile), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	// SHA-1 of "password1" is E38AD214943DAAD1D64C102FAEC29DE4AFE9DA3D
+	if err := os.WriteFile(filepath.Join(dir, "E38AD.txt"), []byte("0000000CAEF405439D57847A8657218C618:1\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	c, err := New(dir)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		password string
+		want     int
+		wantErr  error
+	}{
+		{password: "password", want: 9659365},
+		{password: "password1", want: 0},
+		{password: "correct horse battery staple", wantErr: ErrNoRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			got, err := c.Count(tt.password)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Count() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Count() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := New(filepath.Join(dir, "5BAA6.txt")); err == nil {
+		t.Errorf("New() of a file error = nil")
+	}
+}
@@ -1,7 +1,257 @@
 package entity
 
+import "time"
+
 // Pair login and password pair
 type Pair struct {
+	// Name encrypted name of the service, the service itself is stored hashed.
+	// It's empty for entries saved before the names were stored.
+	Name     string
 	Login    string
 	Password string
+	// UpdatedAt time of the last change, zero for entries saved before it was stored.
+	UpdatedAt time.Time
+	// CreatedAt time of the first save, zero for entries saved before it was stored.
+	CreatedAt time.Time
+	// ExpiresAt time the entry is deleted at, zero means never.
+	ExpiresAt time.Time
+	// ExpiryWarned is true when the owner has been warned about the expiry.
+	ExpiryWarned bool
+}
+
+// Language chat language and the way it was chosen.
+// Auto is true when the language was detected from Telegram user settings
+// and false when the user picked it explicitly.
+type Language struct {
+	Code string
+	Auto bool
+}
+
+// Lockout brute-force protection state of a chat.
+type Lockout struct {
+	// Failures number of failed lookups since WindowStart.
+	Failures    int
+	WindowStart time.Time
+	// Level number of lockouts in a row, the lockout duration doubles with each.
+	Level       int
+	LockedUntil time.Time
+}
+
+// Roles of the members.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Member user of a private deployment.
+type Member struct {
+	UserID   int64
+	Username string
+	Role     string
+	// Revoked members can't use the bot even if they are in the allowlist.
+	Revoked   bool
+	CreatedAt time.Time
+}
+
+// UserData counts and times of everything stored about a chat, never the secrets.
+type UserData struct {
+	Services int
+	// FirstSaved and LastUpdated are zero when the entries were saved before the times were tracked.
+	FirstSaved     time.Time
+	LastUpdated    time.Time
+	SharesGiven    int
+	SharesReceived int
+	OneTime        int
+	AuditRecords   int
+	AuditSince     time.Time
+	Vaults         int
+	DuressPIN      bool
+	Files          int
+}
+
+// File encrypted attachment of a service.
+type File struct {
+	// Service hash of the service name, like in the storage of entries.
+	Service string
+	// Name encrypted service name, so the owner can list the files.
+	Name string
+	// FileName encrypted name of the file, photos have none.
+	FileName string
+	Photo    bool
+	// Data encrypted content of the file, it's not read by GetFiles.
+	Data      []byte
+	CreatedAt time.Time
+}
+
+// Stats usage statistics of the bot.
+type Stats struct {
+	Members  int
+	Chats    int
+	Services int
+}
+
+// Roles of the vault members.
+const (
+	VaultOwner  = "owner"
+	VaultEditor = "editor"
+	VaultViewer = "viewer"
+)
+
+// Vault team vault of a group chat.
+// Its entries are stored like the ones of a user with the vault ID as the owner.
+type Vault struct {
+	// ID is the ID of the group chat.
+	ID        int64
+	Name      string
+	CreatedAt time.Time
+}
+
+// VaultMember user who has access to the vault.
+type VaultMember struct {
+	VaultID int64
+	UserID  int64
+	Role    string
+}
+
+// User Telegram user who has used the bot.
+type User struct {
+	ID       int64
+	Username string
 }
+
+// Share access of the recipient to one entry of the owner.
+// The recipient reads a copy of the entry sealed with the key of the recipient,
+// it's sealed again on each update of the entry.
+type Share struct {
+	Owner int64
+	// Service hash of the service name, like in the storage of entries.
+	Service   string
+	Recipient int64
+	// Name encrypted service name, so the owner can list the shares.
+	Name      string
+	ReadOnly  bool
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	// Sealed copy of the entry, see usecase.sealShare.
+	Sealed []byte
+	// Accesses number of times the recipient has read the entry.
+	Accesses int
+}
+
+// OneTime secret of a one-time link.
+type OneTime struct {
+	ID    string
+	Owner int64
+	// Name encrypted service name, so the owner can be told which link was opened.
+	Name string
+	// Secret is encrypted with the key from the link, it's never stored.
+	Secret    string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// Rotation password rotation settings of a chat.
+type Rotation struct {
+	// Period after which the passwords should be changed, zero means never.
+	Period time.Duration
+	// RemindAt no reminders are sent to the chat before it.
+	RemindAt time.Time
+}
+
+// RotationEntry entry with a rotation period of its own or of its chat.
+type RotationEntry struct {
+	Owner int64
+	// Name encrypted name of the service.
+	Name      string
+	UpdatedAt time.Time
+	// Period of the entry, zero means the period of the chat, negative means never.
+	Period time.Duration
+	Chat   Rotation
+}
+
+// ExpiringEntry entry with an expiry time.
+type ExpiringEntry struct {
+	Owner int64
+	// Service hash of the service name.
+	Service string
+	// Name encrypted name of the service.
+	Name         string
+	ExpiresAt    time.Time
+	ExpiryWarned bool
+}
+
+// Actions of the audit log.
+const (
+	ActionGet    = "get"
+	ActionSave   = "save"
+	ActionDelete = "delete"
+	ActionLang   = "lang"
+	ActionWipe   = "wipe"
+	// ActionExpire is the deletion of an expired entry.
+	ActionExpire = "expire"
+	// ActionFileSave, ActionFileGet and ActionFileDelete are the actions on the files of services.
+	ActionFileSave   = "file_save"
+	ActionFileGet    = "file_get"
+	ActionFileDelete = "file_delete"
+	// ActionExport is the export of all entries of the chat.
+	ActionExport = "export"
+	// ActionForget takes the place of the removed records of a chat, it has no chat.
+	ActionForget = "forget"
+)
+
+// Outcomes of the audit log actions.
+const (
+	OutcomeOK       = "ok"
+	OutcomeNotFound = "not_found"
+	OutcomeLocked   = "locked"
+	OutcomeDenied   = "denied"
+	OutcomeError    = "error"
+)
+
+// AuditRecord record of the append-only audit log.
+// The records are chained: each has the hashes of the previous record
+// and of the previous record of the chat, so removed or altered records are detected.
+type AuditRecord struct {
+	ID     int64
+	ChatID int64
+	Action string
+	// Service hashed service, empty for actions without one.
+	Service   string
+	Outcome   string
+	CreatedAt time.Time
+	// PrevHash, ChatPrevHash and Hash are empty for records added before the chain.
+	PrevHash     string
+	ChatPrevHash string
+	Hash         string
+}
+
+// Anchors of the audit log chain.
+const (
+	// AnchorHead the last appended record.
+	AnchorHead = "head"
+	// AnchorCheckpoint the last record purged by the retention, the remaining chain continues it.
+	AnchorCheckpoint = "checkpoint"
+)
+
+// AuditAnchor sealed reference to a record of the audit log chain,
+// so records removed from the start or the end of the chain are detected too.
+type AuditAnchor struct {
+	RecordID int64
+	Hash     string
+	// Seal keyed hash of the kind of the anchor and the hash of the record.
+	Seal string
+}
+
+// AuditLink link of a removed record of the audit log chain, so the chain is walked over the gap.
+type AuditLink struct {
+	Hash     string
+	PrevHash string
+	// ForgetHash hash of the forget record which took the place of the removed records.
+	ForgetHash string
+	// Seal keyed hash of the link, so links can't be added to hide other removed records.
+	Seal string
+}
+
+// AuditForget seals the forget record continuing the last record prevHash and the last forget record
+// chatPrevHash in place of the removed records, and the links of the removed records.
+type AuditForget func(prevHash, chatPrevHash string, removed []AuditLink) (record AuditRecord, links []AuditLink, headSeal string)
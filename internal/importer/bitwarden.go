@@ -0,0 +1,57 @@
+package importer
+
+import (
+	"encoding/json"
+	"password-keeper/internal/usecase"
+)
+
+// bitwardenLogin type of Bitwarden login items, other types are cards, notes and identities.
+const bitwardenLogin = 1
+
+type bitwardenExport struct {
+	Encrypted bool `json:"encrypted"`
+	Items     []struct {
+		Type  int    `json:"type"`
+		Name  string `json:"name"`
+		Login *struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			URIs     []struct {
+				URI string `json:"uri"`
+			} `json:"uris"`
+		} `json:"login"`
+	} `json:"items"`
+}
+
+// parseBitwardenJSON reads the unencrypted JSON export of Bitwarden.
+func parseBitwardenJSON(data []byte) ([]usecase.Entry, error) {
+	var export bitwardenExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, ErrUnknownFormat
+	}
+
+	if export.Encrypted {
+		return nil, ErrEncrypted
+	}
+	if export.Items == nil {
+		return nil, ErrUnknownFormat
+	}
+
+	var entries []usecase.Entry
+	for _, item := range export.Items {
+		if item.Type != bitwardenLogin || item.Login == nil {
+			continue
+		}
+
+		var uri string
+		if len(item.Login.URIs) > 0 {
+			uri = item.Login.URIs[0].URI
+		}
+
+		if e, ok := entry(item.Name, uri, item.Login.Username, item.Login.Password); ok {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries, nil
+}
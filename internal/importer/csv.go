@@ -0,0 +1,95 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"password-keeper/internal/usecase"
+	"strings"
+)
+
+// csvColumns names of the columns in the exports, the header is compared in lower case.
+var csvColumns = map[string][]string{
+	"title":    {"title", "name"},
+	"url":      {"url", "website", "login_uri"},
+	"login":    {"username", "login_username"},
+	"password": {"password", "login_password"},
+	"type":     {"type"},
+}
+
+// parseCSV reads the CSV exports, the format is detected by the header:
+//
+//	Bitwarden: folder,favorite,type,name,notes,fields,reprompt,login_uri,login_username,login_password,login_totp
+//	1Password: Title,Url,Username,Password,OTPAuth,Favorite,Archived,Tags,Notes
+//	Chrome:    name,url,username,password,note
+//	Firefox:   url,username,password,httpRealm,formActionOrigin,guid,timeCreated,timeLastUsed,timePasswordChanged
+func parseCSV(data []byte) (string, []usecase.Entry, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	records, err := r.ReadAll()
+	if err != nil || len(records) == 0 {
+		return "", nil, ErrUnknownFormat
+	}
+
+	header := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		header[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	columns := make(map[string]int, len(csvColumns))
+	for column, names := range csvColumns {
+		columns[column] = -1
+		for _, name := range names {
+			if i, ok := header[name]; ok {
+				columns[column] = i
+				break
+			}
+		}
+	}
+
+	if columns["password"] < 0 || (columns["title"] < 0 && columns["url"] < 0) {
+		return "", nil, ErrUnknownFormat
+	}
+
+	var entries []usecase.Entry
+	for _, record := range records[1:] {
+		field := func(column string) string {
+			i := columns[column]
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return record[i]
+		}
+
+		// Bitwarden exports cards and notes too
+		if t := field("type"); t != "" && t != "login" {
+			continue
+		}
+
+		if e, ok := entry(field("title"), field("url"), field("login"), field("password")); ok {
+			entries = append(entries, e)
+		}
+	}
+
+	return csvFormat(header), entries, nil
+}
+
+// csvFormat returns the password manager the header belongs to.
+func csvFormat(header map[string]int) string {
+	has := func(name string) bool {
+		_, ok := header[name]
+		return ok
+	}
+
+	switch {
+	case has("login_password"):
+		return Bitwarden
+	case has("guid") || has("httprealm"):
+		return Firefox
+	case has("title"):
+		return OnePassword
+	default:
+		return Chrome
+	}
+}
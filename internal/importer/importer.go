@@ -0,0 +1,80 @@
+// Package importer reads the exports of other password managers.
+//
+// Supported formats are Bitwarden JSON and CSV, KeePass 2.x XML,
+// 1Password CSV and Chrome and Firefox CSV. The format is detected by the content,
+// so the name of the file doesn't matter.
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"password-keeper/internal/usecase"
+	"strings"
+)
+
+// Formats of the files.
+const (
+	Bitwarden   = "Bitwarden"
+	KeePass     = "KeePass"
+	OnePassword = "1Password"
+	Chrome      = "Chrome"
+	Firefox     = "Firefox"
+)
+
+var (
+	// ErrUnknownFormat is returned when the file is not an export of a supported password manager.
+	ErrUnknownFormat = errors.New("unknown format")
+	// ErrEncrypted is returned for password protected exports, they can't be read without the password.
+	ErrEncrypted = errors.New("export is encrypted")
+)
+
+// Parse detects the format of the file and returns its format and the entries.
+// Entries without a name or a URL and entries without a login and a password are skipped.
+func Parse(data []byte) (format string, entries []usecase.Entry, err error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	switch trimmed := bytes.TrimSpace(data); {
+	case len(trimmed) == 0:
+		return "", nil, ErrUnknownFormat
+	case trimmed[0] == '{':
+		entries, err = parseBitwardenJSON(trimmed)
+		return Bitwarden, entries, err
+	case trimmed[0] == '<':
+		entries, err = parseKeePass(trimmed)
+		return KeePass, entries, err
+	default:
+		return parseCSV(data)
+	}
+}
+
+// entry returns the entry with the service named after the title or the host of the URL.
+// Whitespace is replaced, because the commands split the service name by it.
+func entry(title, uri, login, password string) (usecase.Entry, bool) {
+	name := strings.Join(strings.Fields(title), "_")
+	if name == "" {
+		name = host(uri)
+	}
+
+	if name == "" || (login == "" && password == "") {
+		return usecase.Entry{}, false
+	}
+	return usecase.Entry{Service: name, Login: login, Password: password}, true
+}
+
+// host returns the host of the URL without www.
+func host(uri string) string {
+	uri = strings.TrimSpace(uri)
+	if uri == "" {
+		return ""
+	}
+	if !strings.Contains(uri, "://") {
+		uri = "https://" + uri
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
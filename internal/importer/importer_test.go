@@ -0,0 +1,121 @@
+package importer
+
+import (
+	"errors"
+	"password-keeper/internal/usecase"
+	"reflect"
+	"testing"
+)
+
+const bitwardenJSON = `{
+  "encrypted": false,
+  "folders": [],
+  "items": [
+    {"type": 1, "name": "My Mail", "login": {"username": "me@example.com", "password": "p1", "uris": [{"uri": "https://mail.example.com"}]}},
+    {"type": 1, "name": "", "login": {"username": "bob", "password": "p2", "uris": [{"uri": "https://www.bank.com/login"}]}},
+    {"type": 2, "name": "Note", "notes": "secret note"},
+    {"type": 1, "name": "Empty", "login": {"username": "", "password": ""}}
+  ]
+}`
+
+const bitwardenCSV = "folder,favorite,type,name,notes,fields,reprompt,login_uri,login_username,login_password,login_totp\n" +
+	",,login,My Mail,,,0,https://mail.example.com,me@example.com,p1,\n" +
+	",,note,Note,secret note,,0,,,,\n"
+
+const keepassXML = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+  <Meta><RecycleBinUUID>bin</RecycleBinUUID></Meta>
+  <Root>
+    <Group>
+      <UUID>root</UUID>
+      <Name>Database</Name>
+      <Entry>
+        <String><Key>Title</Key><Value>My Mail</Value></String>
+        <String><Key>UserName</Key><Value>me@example.com</Value></String>
+        <String><Key>Password</Key><Value ProtectInMemory="True">p1</Value></String>
+        <String><Key>URL</Key><Value>https://mail.example.com</Value></String>
+        <History>
+          <Entry>
+            <String><Key>Title</Key><Value>Old Mail</Value></String>
+            <String><Key>Password</Key><Value>old</Value></String>
+          </Entry>
+        </History>
+      </Entry>
+      <Group>
+        <UUID>work</UUID>
+        <Entry>
+          <String><Key>Title</Key><Value></Value></String>
+          <String><Key>UserName</Key><Value>bob</Value></String>
+          <String><Key>Password</Key><Value>p2</Value></String>
+          <String><Key>URL</Key><Value>www.bank.com</Value></String>
+        </Entry>
+      </Group>
+      <Group>
+        <UUID>bin</UUID>
+        <Entry>
+          <String><Key>Title</Key><Value>Deleted</Value></String>
+          <String><Key>Password</Key><Value>p3</Value></String>
+        </Entry>
+      </Group>
+    </Group>
+  </Root>
+</KeePassFile>`
+
+const onePasswordCSV = "Title,Url,Username,Password,OTPAuth,Favorite,Archived,Tags,Notes\n" +
+	"My Mail,https://mail.example.com,me@example.com,p1,,false,false,,\n"
+
+const chromeCSV = "name,url,username,password,note\n" +
+	"mail.example.com,https://mail.example.com/,me@example.com,p1,\n" +
+	",https://www.bank.com/login,bob,\"p2,with comma\",\n"
+
+const firefoxCSV = "\xef\xbb\xbf\"url\",\"username\",\"password\",\"httpRealm\",\"formActionOrigin\",\"guid\",\"timeCreated\",\"timeLastUsed\",\"timePasswordChanged\"\n" +
+	"\"https://mail.example.com\",\"me@example.com\",\"p1\",,\"https://mail.example.com\",\"{1}\",\"1\",\"1\",\"1\"\n"
+
+func TestParse(t *testing.T) {
+	mail := usecase.Entry{Service: "My_Mail", Login: "me@example.com", Password: "p1"}
+	hostMail := usecase.Entry{Service: "mail.example.com", Login: "me@example.com", Password: "p1"}
+	bank := usecase.Entry{Service: "bank.com", Login: "bob", Password: "p2"}
+
+	tests := []struct {
+		name       string
+		data       string
+		wantFormat string
+		want       []usecase.Entry
+		wantErr    error
+	}{
+		{name: "bitwarden json", data: bitwardenJSON, wantFormat: Bitwarden, want: []usecase.Entry{mail, bank}},
+		{name: "bitwarden csv", data: bitwardenCSV, wantFormat: Bitwarden, want: []usecase.Entry{mail}},
+		{name: "keepass", data: keepassXML, wantFormat: KeePass, want: []usecase.Entry{mail, bank}},
+		{name: "1password", data: onePasswordCSV, wantFormat: OnePassword, want: []usecase.Entry{mail}},
+		{
+			name:       "chrome",
+			data:       chromeCSV,
+			wantFormat: Chrome,
+			want:       []usecase.Entry{hostMail, {Service: "bank.com", Login: "bob", Password: "p2,with comma"}},
+		},
+		{name: "firefox", data: firefoxCSV, wantFormat: Firefox, want: []usecase.Entry{hostMail}},
+		{name: "encrypted bitwarden", data: `{"encrypted": true, "encKeyValidation_DO_NOT_EDIT": "x"}`, wantErr: ErrEncrypted},
+		{name: "other json", data: `{"entries": []}`, wantErr: ErrUnknownFormat},
+		{name: "other xml", data: `<html></html>`, wantErr: ErrUnknownFormat},
+		{name: "other csv", data: "a,b\n1,2\n", wantErr: ErrUnknownFormat},
+		{name: "empty", data: " ", wantErr: ErrUnknownFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, got, err := Parse([]byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if format != tt.wantFormat {
+				t.Errorf("Parse() format = %q, want %q", format, tt.wantFormat)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
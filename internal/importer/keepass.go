@@ -0,0 +1,64 @@
+package importer
+
+import (
+	"encoding/xml"
+	"password-keeper/internal/usecase"
+)
+
+type keepassFile struct {
+	XMLName xml.Name `xml:"KeePassFile"`
+	Meta    struct {
+		RecycleBinUUID string `xml:"RecycleBinUUID"`
+	} `xml:"Meta"`
+	Root struct {
+		Groups []keepassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type keepassGroup struct {
+	UUID    string         `xml:"UUID"`
+	Entries []keepassEntry `xml:"Entry"`
+	Groups  []keepassGroup `xml:"Group"`
+}
+
+// keepassEntry the history of the entry is not read, only the current values.
+type keepassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	} `xml:"String"`
+}
+
+// parseKeePass reads the XML export of KeePass 2.x, the recycle bin is skipped.
+func parseKeePass(data []byte) ([]usecase.Entry, error) {
+	var file keepassFile
+	if err := xml.Unmarshal(data, &file); err != nil {
+		return nil, ErrUnknownFormat
+	}
+
+	var entries []usecase.Entry
+	var walk func(groups []keepassGroup)
+	walk = func(groups []keepassGroup) {
+		for _, g := range groups {
+			if g.UUID != "" && g.UUID == file.Meta.RecycleBinUUID {
+				continue
+			}
+
+			for _, ke := range g.Entries {
+				fields := make(map[string]string, len(ke.Strings))
+				for _, s := range ke.Strings {
+					fields[s.Key] = s.Value
+				}
+
+				if e, ok := entry(fields["Title"], fields["URL"], fields["UserName"], fields["Password"]); ok {
+					entries = append(entries, e)
+				}
+			}
+
+			walk(g.Groups)
+		}
+	}
+	walk(file.Root.Groups)
+
+	return entries, nil
+}
@@ -0,0 +1,155 @@
+// Package onetime serves one-time secret links.
+//
+// A link looks like https://example.com/s/ID#KEY. Opening it shows a page,
+// which doesn't destroy the secret, so link previews can't burn it.
+// The reveal button on the page takes the encrypted secret with POST /s/ID,
+// the server deletes it and the page decrypts it with the KEY from the fragment,
+// which browsers never send to the server.
+package onetime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"password-keeper/internal/usecase"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Path prefix of the links.
+const Path = "/s/"
+
+// purgeInterval how often expired secrets are deleted.
+const purgeInterval = time.Minute
+
+// NotifyFunc tells the owner that the link to the service was opened.
+type NotifyFunc func(owner int64, service string)
+
+// Server serves one-time secret links.
+type Server struct {
+	logic  *usecase.UseCase
+	notify NotifyFunc
+	logger *zap.Logger
+	server *http.Server
+
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+// New creates the server listening on addr.
+func New(addr string, logic *usecase.UseCase, notify NotifyFunc, logger *zap.Logger) *Server {
+	s := &Server{
+		logic:  logic,
+		notify: notify,
+		logger: logger,
+		quit:   make(chan struct{}),
+	}
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Start serves the links and purges expired secrets until Shutdown.
+func (s *Server) Start() error {
+	go s.purge()
+
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops the server.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.quitOnce.Do(func() { close(s.quit) })
+	return s.server.Shutdown(ctx)
+}
+
+// Handler returns the handler of the links.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(Path, s.handleSecret)
+	mux.HandleFunc("/onetime.js", s.handleScript)
+	return mux
+}
+
+func (s *Server) handleSecret(w http.ResponseWriter, r *http.Request) {
+	setHeaders(w)
+
+	id := strings.TrimPrefix(r.URL.Path, Path)
+	if id == "" || strings.Contains(id, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page)
+	case http.MethodPost:
+		s.take(w, id)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// take returns the encrypted secret and destroys it.
+func (s *Server) take(w http.ResponseWriter, id string) {
+	ot, err := s.logic.TakeOneTime(id)
+	if err != nil {
+		if errors.Is(err, usecase.ErrOneTimeGone) {
+			http.Error(w, http.StatusText(http.StatusGone), http.StatusGone)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, ot.Secret)
+
+	if s.notify != nil {
+		s.notify(ot.Owner, ot.Name)
+	}
+}
+
+func (s *Server) handleScript(w http.ResponseWriter, _ *http.Request) {
+	setHeaders(w)
+	w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+	fmt.Fprint(w, script)
+}
+
+// purge deletes expired secrets, so they don't stay in the database forever.
+func (s *Server) purge() {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.quit:
+			return
+		case <-ticker.C:
+			if n, err := s.logic.PurgeOneTime(); err == nil && n > 0 {
+				s.logger.Info(fmt.Sprintf("purged %d expired one-time secrets", n))
+			}
+		}
+	}
+}
+
+// setHeaders forbids caching, framing and leaking the link via Referer.
+func setHeaders(w http.ResponseWriter) {
+	h := w.Header()
+	h.Set("Cache-Control", "no-store")
+	h.Set("Referrer-Policy", "no-referrer")
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("X-Frame-Options", "DENY")
+	h.Set("Content-Security-Policy", "default-src 'none'; script-src 'self'; connect-src 'self'; style-src 'unsafe-inline'; frame-ancestors 'none'")
+}
@@ -0,0 +1,148 @@
+package onetime
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"password-keeper/internal/storage"
+	"password-keeper/internal/usecase"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func newServer(t *testing.T) (*usecase.UseCase, *httptest.Server, chan string) {
+	s, err := storage.New("test", "file::memory:?cache=shared")
+	if err != nil {
+		t.Fatalf("storage.New() error = %v", err)
+	}
+
+	logic, err := usecase.New(s, "1234567890123456", zap.NewNop())
+	if err != nil {
+		t.Fatalf("usecase.New() error = %v", err)
+	}
+
+	opened := make(chan string, 1)
+	srv := New("", logic, func(owner int64, service string) { opened <- service }, zap.NewNop())
+
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return logic, ts, opened
+}
+
+func TestServer(t *testing.T) {
+	logic, ts, opened := newServer(t)
+
+	const owner = 11001
+	if err := logic.Save(owner, "vpn", "alice", "pa$$word"); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	id, key, err := logic.CreateOneTime(owner, "vpn", time.Hour)
+	if err != nil {
+		t.Fatalf("CreateOneTime() error = %v", err)
+	}
+
+	// the page doesn't destroy the secret, so link previews can't burn it
+	for i := 0; i < 2; i++ {
+		resp, err := http.Get(ts.URL + Path + id)
+		if err != nil {
+			t.Fatalf("GET error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET status = %d, want %d", resp.StatusCode, http.StatusOK)
+		}
+		if resp.Header.Get("Cache-Control") != "no-store" {
+			t.Errorf("Cache-Control = %q, want no-store", resp.Header.Get("Cache-Control"))
+		}
+	}
+
+	resp, err := http.Post(ts.URL+Path+id, "", nil)
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	if strings.Contains(string(body), "pa$$word") {
+		t.Errorf("the secret is sent unencrypted")
+	}
+
+	rawKey, err := base64.RawURLEncoding.DecodeString(key)
+	if err != nil {
+		t.Fatalf("decode key error = %v", err)
+	}
+	plain, err := usecase.OpenOneTime(rawKey, string(body))
+	if err != nil {
+		t.Fatalf("OpenOneTime() error = %v", err)
+	}
+
+	var payload usecase.OneTimePayload
+	if err := json.Unmarshal(plain, &payload); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if want := (usecase.OneTimePayload{Service: "vpn", Login: "alice", Password: "pa$$word"}); payload != want {
+		t.Errorf("payload = %+v, want %+v", payload, want)
+	}
+
+	select {
+	case service := <-opened:
+		if service != "vpn" {
+			t.Errorf("notify service = %q, want %q", service, "vpn")
+		}
+	default:
+		t.Errorf("the owner is not notified")
+	}
+
+	// the second view gets nothing
+	resp, err = http.Post(ts.URL+Path+id, "", nil)
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusGone {
+		t.Errorf("second POST status = %d, want %d", resp.StatusCode, http.StatusGone)
+	}
+}
+
+func TestServer_methods(t *testing.T) {
+	_, ts, _ := newServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{name: "script", method: http.MethodGet, path: "/onetime.js", want: http.StatusOK},
+		{name: "unknown", method: http.MethodPost, path: Path + "unknown", want: http.StatusGone},
+		{name: "no id", method: http.MethodGet, path: Path, want: http.StatusNotFound},
+		{name: "put", method: http.MethodPut, path: Path + "id", want: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, ts.URL+tt.path, nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package onetime
+
+// page is shown by the link, the secret is taken only after the click.
+const page = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>One-time secret</title>
+<style>
+body { font-family: sans-serif; max-width: 32em; margin: 3em auto; padding: 0 1em; }
+pre { background: #f3f3f3; padding: 1em; white-space: pre-wrap; word-break: break-all; }
+button { font-size: 1.1em; padding: .5em 1em; }
+</style>
+</head>
+<body>
+<h1>🔐 One-time secret</h1>
+<p id="hint">The secret can be viewed only once, it's destroyed after the click.</p>
+<button id="reveal">Reveal</button>
+<pre id="secret" hidden></pre>
+<script src="/onetime.js"></script>
+</body>
+</html>
+`
+
+// script takes the secret and decrypts it with the key from the fragment.
+// The format is the one of usecase.SealOneTime: base64url(nonce | AES-GCM ciphertext).
+const script = `"use strict";
+
+function decode(s) {
+  s = s.replace(/-/g, "+").replace(/_/g, "/");
+  while (s.length % 4) s += "=";
+  return Uint8Array.from(atob(s), c => c.charCodeAt(0));
+}
+
+async function reveal() {
+  const button = document.getElementById("reveal");
+  const hint = document.getElementById("hint");
+  const out = document.getElementById("secret");
+  button.disabled = true;
+
+  try {
+    const resp = await fetch(location.pathname, { method: "POST", cache: "no-store" });
+    if (!resp.ok) {
+      hint.textContent = "The secret has already been viewed or has expired.";
+      return;
+    }
+
+    const raw = decode(await resp.text());
+    const key = await crypto.subtle.importKey("raw", decode(location.hash.slice(1)), "AES-GCM", false, ["decrypt"]);
+    const plain = await crypto.subtle.decrypt({ name: "AES-GCM", iv: raw.slice(0, 12) }, key, raw.slice(12));
+    const secret = JSON.parse(new TextDecoder().decode(plain));
+
+    out.textContent = secret.service + "\nLogin: " + secret.login + "\nPassword: " + secret.password;
+    out.hidden = false;
+    hint.textContent = "The secret is destroyed on the server, save it now.";
+    history.replaceState(null, "", location.pathname);
+  } catch (e) {
+    hint.textContent = "Can't decrypt the secret, the link is broken.";
+  } finally {
+    button.hidden = true;
+  }
+}
+
+document.getElementById("reveal").addEventListener("click", reveal);
+`
@@ -19,28 +19,346 @@ type Name int
 // GetService - get service.
 // GetLang - get lang.
 // DeleteService - delete service.
+// GetLockout - get lockout state.
+// SetLockout - add or update lockout state.
+// GetMember - get member.
+// SaveMember - add or update member.
+// GetMembers - get all members.
+// CountMembers - count members.
+// CountChats - count chats.
+// CountServices - count services.
+// CreateVault - add vault.
+// GetVault - get vault.
+// GetVaultByName - get vault by name.
+// GetUserVaults - get vaults of user.
+// SaveVaultMember - add or update vault member.
+// GetVaultMember - get vault member.
+// GetVaultMembers - get all vault members.
+// DeleteVaultMember - delete vault member.
+// SaveUser - add or update user.
+// GetUserByName - get user by username.
+// SaveShare - add or update share.
+// GetShare - get share of recipient.
+// GetShares - get shares of owner with the number of accesses.
+// DeleteShare - delete share.
+// DeleteShares - delete all shares of service.
+// AddShareAccess - add share access.
+// SaveOneTime - add one-time secret.
+// GetOneTime - get one-time secret.
+// DeleteOneTime - delete one-time secret.
+// DeleteExpiredOneTime - delete expired one-time secrets.
+// GetServices - get all services of owner.
+// SetServiceRotation - set rotation period of service.
+// GetRotation - get rotation settings of chat.
+// SetRotation - add or update rotation settings of chat.
+// GetRotationEntries - get services with rotation period and the settings of their chats.
+// AddAuditRecord - add audit log record.
+// GetAuditRecords - get the latest audit log records of chat.
+// DeleteAuditRecords - delete audit log records up to the id.
+// GetLastAuditHash - get hash of the last audit log record.
+// GetLastChatAuditHash - get hash of the last audit log record of chat.
+// GetAuditChain - get audit log records with their hashes after the id.
+// GetAuditCutoff - get id and hash of the last audit log record older than the time.
+// GetAuditAnchor - get sealed anchor of the audit log chain.
+// SetAuditHead - set head anchor of the audit log chain to the record.
+// SetAuditCheckpoint - set checkpoint anchor of the audit log chain unless it is further already.
+// LockAuditHead - get hash of the last audit log record and lock it until the transaction ends.
+// GetUserAuditLinks - get hashes of the sealed audit log records of chat with the hashes of their previous records.
+// AddAuditLink - add link of the removed audit log record.
+// GetAuditLinks - get links of the removed audit log records.
+// DeleteAuditLinks - delete links of the removed audit log records whose forget record is deleted.
+// GetDuressPIN - get duress PIN hash of chat.
+// SetDuressPIN - add or update duress PIN hash of chat.
+// DeleteDuressPIN - delete duress PIN hash of chat.
+// WipeServices - delete all services of owner.
+// WipeChat - delete chat settings.
+// WipeRotation - delete rotation settings of chat.
+// WipeShares - delete shares of owner and to recipient.
+// WipeShareAccesses - delete accesses to shares of owner and by recipient.
+// WipeOneTime - delete one-time secrets of owner.
+// DeleteUserLockout - delete lockout state of chat.
+// DeleteUserMember - delete member.
+// DeleteUserName - delete user.
+// DeleteUserVaults - delete vault memberships of user.
+// DeleteUserAudit - delete audit log records of chat.
+// GetUserData - get counts and times of everything stored about chat.
+// SaveFile - add or update file of service.
+// GetFile - get file of service.
+// GetFiles - get files of owner without the data.
+// DeleteFile - delete file of service.
+// WipeFiles - delete all files of owner.
+// GetExpiringEntries - get services expiring before the time.
+// SetExpiryWarned - mark the owner warned about the expiry of service.
+// DeleteExpiredService - delete service if it has expired by the time.
+// GetDisplayMode - get the way chat is shown its entries.
+// SetDisplayMode - add or update the way chat is shown its entries.
+// DeleteDisplayMode - delete the way chat is shown its entries.
 const (
 	AddService = iota
 	AddOrUpdateChatLang
 	GetService
 	GetLang
 	DeleteService
+	GetLockout
+	SetLockout
+	GetMember
+	SaveMember
+	GetMembers
+	CountMembers
+	CountChats
+	CountServices
+	CreateVault
+	GetVault
+	GetVaultByName
+	GetUserVaults
+	SaveVaultMember
+	GetVaultMember
+	GetVaultMembers
+	DeleteVaultMember
+	SaveUser
+	GetUserByName
+	SaveShare
+	GetShare
+	GetShares
+	DeleteShare
+	DeleteShares
+	AddShareAccess
+	SaveOneTime
+	GetOneTime
+	DeleteOneTime
+	DeleteExpiredOneTime
+	GetServices
+	SetServiceRotation
+	GetRotation
+	SetRotation
+	GetRotationEntries
+	AddAuditRecord
+	GetAuditRecords
+	DeleteAuditRecords
+	GetLastAuditHash
+	GetLastChatAuditHash
+	GetAuditChain
+	GetAuditCutoff
+	GetAuditAnchor
+	SetAuditHead
+	SetAuditCheckpoint
+	LockAuditHead
+	GetUserAuditLinks
+	AddAuditLink
+	GetAuditLinks
+	DeleteAuditLinks
+	GetDuressPIN
+	SetDuressPIN
+	DeleteDuressPIN
+	WipeServices
+	WipeChat
+	WipeRotation
+	WipeShares
+	WipeShareAccesses
+	WipeOneTime
+	DeleteUserLockout
+	DeleteUserMember
+	DeleteUserName
+	DeleteUserVaults
+	DeleteUserAudit
+	GetUserData
+	SaveFile
+	GetFile
+	GetFiles
+	DeleteFile
+	WipeFiles
+	GetExpiringEntries
+	SetExpiryWarned
+	DeleteExpiredService
+	GetDisplayMode
+	SetDisplayMode
+	DeleteDisplayMode
 )
 
 var queriesSqlite = map[Name]Query{
-	AddService:          "INSERT INTO services (service, login, password, owner) VALUES (?, ?, ?, ?) ON CONFLICT DO UPDATE SET login = ?, password = ?, owner = ?",
-	AddOrUpdateChatLang: "INSERT INTO chats (chat_id, chat_lang) VALUES (?, ?) ON CONFLICT DO UPDATE SET chat_lang = ?",
-	GetService:          "SELECT login, password FROM services WHERE service = ? and owner = ?",
-	GetLang:             "SELECT chat_lang FROM chats WHERE chat_id = ?",
-	DeleteService:       "DELETE FROM services WHERE service = ? and owner = ?",
+	AddService:           "INSERT INTO services (service, name, login, password, owner, updated_at, created_at, expires_at, expiry_warned) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT DO UPDATE SET name = ?, login = ?, password = ?, owner = ?, updated_at = ?, expires_at = ?, expiry_warned = ?",
+	AddOrUpdateChatLang:  "INSERT INTO chats (chat_id, chat_lang, chat_lang_auto) VALUES (?, ?, ?) ON CONFLICT DO UPDATE SET chat_lang = ?, chat_lang_auto = ?",
+	GetService:           "SELECT name, login, password, updated_at, created_at, expires_at, expiry_warned FROM services WHERE service = ? and owner = ?",
+	GetLang:              "SELECT chat_lang, chat_lang_auto FROM chats WHERE chat_id = ?",
+	DeleteService:        "DELETE FROM services WHERE service = ? and owner = ?",
+	GetLockout:           "SELECT failures, window_start, level, locked_until FROM lockouts WHERE chat_id = ?",
+	SetLockout:           "INSERT INTO lockouts (chat_id, failures, window_start, level, locked_until) VALUES (?, ?, ?, ?, ?) ON CONFLICT DO UPDATE SET failures = ?, window_start = ?, level = ?, locked_until = ?",
+	GetMember:            "SELECT user_id, username, role, revoked, created_at FROM members WHERE user_id = ?",
+	SaveMember:           "INSERT INTO members (user_id, username, role, revoked, created_at) VALUES (?, ?, ?, ?, ?) ON CONFLICT DO UPDATE SET username = ?, role = ?, revoked = ?",
+	GetMembers:           "SELECT user_id, username, role, revoked, created_at FROM members ORDER BY created_at, user_id",
+	CountMembers:         "SELECT COUNT(*) FROM members WHERE NOT revoked",
+	CountChats:           "SELECT COUNT(*) FROM chats",
+	CountServices:        "SELECT COUNT(*) FROM services",
+	CreateVault:          "INSERT INTO vaults (vault_id, name, created_at) VALUES (?, ?, ?)",
+	GetVault:             "SELECT vault_id, name, created_at FROM vaults WHERE vault_id = ?",
+	GetVaultByName:       "SELECT vault_id, name, created_at FROM vaults WHERE name = ?",
+	GetUserVaults:        "SELECT v.vault_id, v.name, v.created_at FROM vaults v JOIN vault_members m ON m.vault_id = v.vault_id WHERE m.user_id = ? ORDER BY v.name",
+	SaveVaultMember:      "INSERT INTO vault_members (vault_id, user_id, role) VALUES (?, ?, ?) ON CONFLICT DO UPDATE SET role = ?",
+	GetVaultMember:       "SELECT vault_id, user_id, role FROM vault_members WHERE vault_id = ? and user_id = ?",
+	GetVaultMembers:      "SELECT vault_id, user_id, role FROM vault_members WHERE vault_id = ? ORDER BY user_id",
+	DeleteVaultMember:    "DELETE FROM vault_members WHERE vault_id = ? and user_id = ?",
+	SaveUser:             "INSERT INTO users (user_id, username) VALUES (?, ?) ON CONFLICT DO UPDATE SET username = ?",
+	GetUserByName:        "SELECT user_id, username FROM users WHERE username = ?",
+	SaveShare:            "INSERT INTO shares (owner, service, recipient, name, read_only, expires_at, created_at, sealed) VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT DO UPDATE SET name = ?, read_only = ?, expires_at = ?, sealed = ?",
+	GetShare:             "SELECT owner, service, recipient, name, read_only, expires_at, created_at, sealed, 0 FROM shares WHERE recipient = ? and service = ? ORDER BY created_at DESC",
+	GetShares:            "SELECT s.owner, s.service, s.recipient, s.name, s.read_only, s.expires_at, s.created_at, s.sealed, (SELECT COUNT(*) FROM share_accesses a WHERE a.owner = s.owner and a.service = s.service and a.recipient = s.recipient) FROM shares s WHERE s.owner = ? ORDER BY s.created_at",
+	DeleteShare:          "DELETE FROM shares WHERE owner = ? and service = ? and recipient = ?",
+	DeleteShares:         "DELETE FROM shares WHERE owner = ? and service = ?",
+	AddShareAccess:       "INSERT INTO share_accesses (owner, service, recipient, accessed_at) VALUES (?, ?, ?, ?)",
+	SaveOneTime:          "INSERT INTO onetime_secrets (id, owner, name, secret, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+	GetOneTime:           "SELECT id, owner, name, secret, expires_at, created_at FROM onetime_secrets WHERE id = ?",
+	DeleteOneTime:        "DELETE FROM onetime_secrets WHERE id = ?",
+	DeleteExpiredOneTime: "DELETE FROM onetime_secrets WHERE expires_at <= ?",
+	GetServices:          "SELECT name, login, password, updated_at, created_at, expires_at, expiry_warned FROM services WHERE owner = ?",
+	SetServiceRotation:   "UPDATE services SET rotate_days = ? WHERE service = ? and owner = ?",
+	GetRotation:          "SELECT period_days, remind_at FROM rotations WHERE chat_id = ?",
+	SetRotation:          "INSERT INTO rotations (chat_id, period_days, remind_at) VALUES (?, ?, ?) ON CONFLICT DO UPDATE SET period_days = ?, remind_at = ?",
+	GetRotationEntries:   "SELECT s.owner, s.name, s.updated_at, s.rotate_days, COALESCE(r.period_days, 0), COALESCE(r.remind_at, 0) FROM services s LEFT JOIN rotations r ON r.chat_id = s.owner WHERE s.rotate_days > 0 OR r.period_days > 0",
+	AddAuditRecord:       "INSERT INTO audit_log (chat_id, action, service, outcome, created_at, prev_hash, chat_prev_hash, hash) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+	GetAuditRecords:      "SELECT chat_id, action, service, outcome, created_at FROM audit_log WHERE chat_id = ? ORDER BY id DESC LIMIT ?",
+	DeleteAuditRecords:   "DELETE FROM audit_log WHERE id <= ?",
+	GetLastAuditHash:     "SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1",
+	GetLastChatAuditHash: "SELECT hash FROM audit_log WHERE chat_id = ? ORDER BY id DESC LIMIT 1",
+	GetAuditChain:        "SELECT id, chat_id, action, service, outcome, created_at, prev_hash, chat_prev_hash, hash FROM audit_log WHERE id > ? ORDER BY id LIMIT ?",
+	GetAuditCutoff:       "SELECT id, hash FROM audit_log WHERE created_at < ? ORDER BY id DESC LIMIT 1",
+	GetAuditAnchor:       "SELECT record_id, hash, seal FROM audit_anchors WHERE kind = ?",
+	SetAuditHead:         "INSERT INTO audit_anchors (kind, record_id, hash, seal) SELECT ?, id, hash, ? FROM audit_log WHERE prev_hash = ? AND hash <> '' ON CONFLICT (kind) DO UPDATE SET record_id = excluded.record_id, hash = excluded.hash, seal = excluded.seal",
+	SetAuditCheckpoint:   "INSERT INTO audit_anchors (kind, record_id, hash, seal) VALUES (?, ?, ?, ?) ON CONFLICT (kind) DO UPDATE SET record_id = excluded.record_id, hash = excluded.hash, seal = excluded.seal WHERE audit_anchors.record_id < excluded.record_id",
+	LockAuditHead:        "SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1",
+	GetUserAuditLinks:    "SELECT hash, prev_hash FROM audit_log WHERE chat_id = ? AND hash <> '' ORDER BY id",
+	AddAuditLink:         "INSERT INTO audit_forgotten (hash, prev_hash, forget_hash, seal) VALUES (?, ?, ?, ?)",
+	GetAuditLinks:        "SELECT hash, prev_hash, forget_hash, seal FROM audit_forgotten",
+	DeleteAuditLinks:     "DELETE FROM audit_forgotten WHERE forget_hash NOT IN (SELECT hash FROM audit_log)",
+	GetDuressPIN:         "SELECT pin FROM duress_pins WHERE chat_id = ?",
+	SetDuressPIN:         "INSERT INTO duress_pins (chat_id, pin) VALUES (?, ?) ON CONFLICT DO UPDATE SET pin = ?",
+	DeleteDuressPIN:      "DELETE FROM duress_pins WHERE chat_id = ?",
+	WipeServices:         "DELETE FROM services WHERE owner = ?",
+	WipeChat:             "DELETE FROM chats WHERE chat_id = ?",
+	WipeRotation:         "DELETE FROM rotations WHERE chat_id = ?",
+	WipeShares:           "DELETE FROM shares WHERE owner = ? OR recipient = ?",
+	WipeShareAccesses:    "DELETE FROM share_accesses WHERE owner = ? OR recipient = ?",
+	WipeOneTime:          "DELETE FROM onetime_secrets WHERE owner = ?",
+	DeleteUserLockout:    "DELETE FROM lockouts WHERE chat_id = ?",
+	DeleteUserMember:     "DELETE FROM members WHERE user_id = ?",
+	DeleteUserName:       "DELETE FROM users WHERE user_id = ?",
+	DeleteUserVaults:     "DELETE FROM vault_members WHERE user_id = ?",
+	DeleteUserAudit:      "DELETE FROM audit_log WHERE chat_id = ?",
+	GetUserData: "SELECT (SELECT COUNT(*) FROM services WHERE owner = ?), " +
+		"(SELECT COALESCE(MIN(created_at), 0) FROM services WHERE owner = ? AND created_at > 0), " +
+		"(SELECT COALESCE(MAX(updated_at), 0) FROM services WHERE owner = ?), " +
+		"(SELECT COUNT(*) FROM shares WHERE owner = ?), " +
+		"(SELECT COUNT(*) FROM shares WHERE recipient = ?), " +
+		"(SELECT COUNT(*) FROM onetime_secrets WHERE owner = ?), " +
+		"(SELECT COUNT(*) FROM audit_log WHERE chat_id = ?), " +
+		"(SELECT COALESCE(MIN(created_at), 0) FROM audit_log WHERE chat_id = ?), " +
+		"(SELECT COUNT(*) FROM vault_members WHERE user_id = ?), " +
+		"(SELECT COUNT(*) FROM duress_pins WHERE chat_id = ?), " +
+		"(SELECT COUNT(*) FROM files WHERE owner = ?)",
+	SaveFile: "INSERT INTO files (owner, service, name, file_name, photo, data, created_at) VALUES (?, ?, ?, ?, ?, ?, ?) " +
+		"ON CONFLICT DO UPDATE SET name = ?, file_name = ?, photo = ?, data = ?, created_at = ?",
+	GetFile:              "SELECT name, file_name, photo, data, created_at FROM files WHERE owner = ? AND service = ?",
+	GetFiles:             "SELECT service, name, file_name, photo, created_at FROM files WHERE owner = ?",
+	DeleteFile:           "DELETE FROM files WHERE owner = ? AND service = ?",
+	WipeFiles:            "DELETE FROM files WHERE owner = ?",
+	GetExpiringEntries:   "SELECT owner, service, name, expires_at, expiry_warned FROM services WHERE expires_at > 0 AND expires_at <= ?",
+	SetExpiryWarned:      "UPDATE services SET expiry_warned = TRUE WHERE owner = ? AND service = ?",
+	DeleteExpiredService: "DELETE FROM services WHERE owner = ? AND service = ? AND expires_at > 0 AND expires_at <= ?",
+	GetDisplayMode:       "SELECT mode FROM display_modes WHERE chat_id = ?",
+	SetDisplayMode:       "INSERT INTO display_modes (chat_id, mode) VALUES (?, ?) ON CONFLICT DO UPDATE SET mode = ?",
+	DeleteDisplayMode:    "DELETE FROM display_modes WHERE chat_id = ?",
 }
 
 var queriesPostgres = map[Name]Query{
-	AddService:          "INSERT INTO services (service, login, password, owner) VALUES ($1, $2, $3, $4) ON CONFLICT (owner) DO UPDATE SET login = $5, password = $6, owner = $7",
-	AddOrUpdateChatLang: "INSERT INTO chats (chat_id, chat_lang) VALUES ($1, $2) ON CONFLICT (chat_id) DO UPDATE SET chat_lang = $3",
-	GetService:          "SELECT login, password FROM services WHERE service = $1 and owner = $2",
-	GetLang:             "SELECT chat_lang FROM chats WHERE chat_id = $1",
-	DeleteService:       "DELETE FROM services WHERE service = $1 and owner = $2",
+	AddService:           "INSERT INTO services (service, name, login, password, owner, updated_at, created_at, expires_at, expiry_warned) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT (owner, service) DO UPDATE SET name = $10, login = $11, password = $12, owner = $13, updated_at = $14, expires_at = $15, expiry_warned = $16",
+	AddOrUpdateChatLang:  "INSERT INTO chats (chat_id, chat_lang, chat_lang_auto) VALUES ($1, $2, $3) ON CONFLICT (chat_id) DO UPDATE SET chat_lang = $4, chat_lang_auto = $5",
+	GetService:           "SELECT name, login, password, updated_at, created_at, expires_at, expiry_warned FROM services WHERE service = $1 and owner = $2",
+	GetLang:              "SELECT chat_lang, chat_lang_auto FROM chats WHERE chat_id = $1",
+	DeleteService:        "DELETE FROM services WHERE service = $1 and owner = $2",
+	GetLockout:           "SELECT failures, window_start, level, locked_until FROM lockouts WHERE chat_id = $1",
+	SetLockout:           "INSERT INTO lockouts (chat_id, failures, window_start, level, locked_until) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (chat_id) DO UPDATE SET failures = $6, window_start = $7, level = $8, locked_until = $9",
+	GetMember:            "SELECT user_id, username, role, revoked, created_at FROM members WHERE user_id = $1",
+	SaveMember:           "INSERT INTO members (user_id, username, role, revoked, created_at) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (user_id) DO UPDATE SET username = $6, role = $7, revoked = $8",
+	GetMembers:           "SELECT user_id, username, role, revoked, created_at FROM members ORDER BY created_at, user_id",
+	CountMembers:         "SELECT COUNT(*) FROM members WHERE NOT revoked",
+	CountChats:           "SELECT COUNT(*) FROM chats",
+	CountServices:        "SELECT COUNT(*) FROM services",
+	CreateVault:          "INSERT INTO vaults (vault_id, name, created_at) VALUES ($1, $2, $3)",
+	GetVault:             "SELECT vault_id, name, created_at FROM vaults WHERE vault_id = $1",
+	GetVaultByName:       "SELECT vault_id, name, created_at FROM vaults WHERE name = $1",
+	GetUserVaults:        "SELECT v.vault_id, v.name, v.created_at FROM vaults v JOIN vault_members m ON m.vault_id = v.vault_id WHERE m.user_id = $1 ORDER BY v.name",
+	SaveVaultMember:      "INSERT INTO vault_members (vault_id, user_id, role) VALUES ($1, $2, $3) ON CONFLICT (vault_id, user_id) DO UPDATE SET role = $4",
+	GetVaultMember:       "SELECT vault_id, user_id, role FROM vault_members WHERE vault_id = $1 and user_id = $2",
+	GetVaultMembers:      "SELECT vault_id, user_id, role FROM vault_members WHERE vault_id = $1 ORDER BY user_id",
+	DeleteVaultMember:    "DELETE FROM vault_members WHERE vault_id = $1 and user_id = $2",
+	SaveUser:             "INSERT INTO users (user_id, username) VALUES ($1, $2) ON CONFLICT (user_id) DO UPDATE SET username = $3",
+	GetUserByName:        "SELECT user_id, username FROM users WHERE username = $1",
+	SaveShare:            "INSERT INTO shares (owner, service, recipient, name, read_only, expires_at, created_at, sealed) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (owner, service, recipient) DO UPDATE SET name = $9, read_only = $10, expires_at = $11, sealed = $12",
+	GetShare:             "SELECT owner, service, recipient, name, read_only, expires_at, created_at, sealed, 0 FROM shares WHERE recipient = $1 and service = $2 ORDER BY created_at DESC",
+	GetShares:            "SELECT s.owner, s.service, s.recipient, s.name, s.read_only, s.expires_at, s.created_at, s.sealed, (SELECT COUNT(*) FROM share_accesses a WHERE a.owner = s.owner and a.service = s.service and a.recipient = s.recipient) FROM shares s WHERE s.owner = $1 ORDER BY s.created_at",
+	DeleteShare:          "DELETE FROM shares WHERE owner = $1 and service = $2 and recipient = $3",
+	DeleteShares:         "DELETE FROM shares WHERE owner = $1 and service = $2",
+	AddShareAccess:       "INSERT INTO share_accesses (owner, service, recipient, accessed_at) VALUES ($1, $2, $3, $4)",
+	SaveOneTime:          "INSERT INTO onetime_secrets (id, owner, name, secret, expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
+	GetOneTime:           "SELECT id, owner, name, secret, expires_at, created_at FROM onetime_secrets WHERE id = $1",
+	DeleteOneTime:        "DELETE FROM onetime_secrets WHERE id = $1",
+	DeleteExpiredOneTime: "DELETE FROM onetime_secrets WHERE expires_at <= $1",
+	GetServices:          "SELECT name, login, password, updated_at, created_at, expires_at, expiry_warned FROM services WHERE owner = $1",
+	SetServiceRotation:   "UPDATE services SET rotate_days = $1 WHERE service = $2 and owner = $3",
+	GetRotation:          "SELECT period_days, remind_at FROM rotations WHERE chat_id = $1",
+	SetRotation:          "INSERT INTO rotations (chat_id, period_days, remind_at) VALUES ($1, $2, $3) ON CONFLICT (chat_id) DO UPDATE SET period_days = $4, remind_at = $5",
+	GetRotationEntries:   "SELECT s.owner, s.name, s.updated_at, s.rotate_days, COALESCE(r.period_days, 0), COALESCE(r.remind_at, 0) FROM services s LEFT JOIN rotations r ON r.chat_id = s.owner WHERE s.rotate_days > 0 OR r.period_days > 0",
+	AddAuditRecord:       "INSERT INTO audit_log (chat_id, action, service, outcome, created_at, prev_hash, chat_prev_hash, hash) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+	GetAuditRecords:      "SELECT chat_id, action, service, outcome, created_at FROM audit_log WHERE chat_id = $1 ORDER BY id DESC LIMIT $2",
+	DeleteAuditRecords:   "DELETE FROM audit_log WHERE id <= $1",
+	GetLastAuditHash:     "SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1",
+	GetLastChatAuditHash: "SELECT hash FROM audit_log WHERE chat_id = $1 ORDER BY id DESC LIMIT 1",
+	GetAuditChain:        "SELECT id, chat_id, action, service, outcome, created_at, prev_hash, chat_prev_hash, hash FROM audit_log WHERE id > $1 ORDER BY id LIMIT $2",
+	GetAuditCutoff:       "SELECT id, hash FROM audit_log WHERE created_at < $1 ORDER BY id DESC LIMIT 1",
+	GetAuditAnchor:       "SELECT record_id, hash, seal FROM audit_anchors WHERE kind = $1",
+	SetAuditHead:         "INSERT INTO audit_anchors (kind, record_id, hash, seal) SELECT CAST($1 AS VARCHAR(20)), id, hash, CAST($2 AS TEXT) FROM audit_log WHERE prev_hash = $3 AND hash <> '' ON CONFLICT (kind) DO UPDATE SET record_id = excluded.record_id, hash = excluded.hash, seal = excluded.seal",
+	SetAuditCheckpoint:   "INSERT INTO audit_anchors (kind, record_id, hash, seal) VALUES ($1, $2, $3, $4) ON CONFLICT (kind) DO UPDATE SET record_id = excluded.record_id, hash = excluded.hash, seal = excluded.seal WHERE audit_anchors.record_id < excluded.record_id",
+	LockAuditHead:        "SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1 FOR UPDATE",
+	GetUserAuditLinks:    "SELECT hash, prev_hash FROM audit_log WHERE chat_id = $1 AND hash <> '' ORDER BY id",
+	AddAuditLink:         "INSERT INTO audit_forgotten (hash, prev_hash, forget_hash, seal) VALUES ($1, $2, $3, $4)",
+	GetAuditLinks:        "SELECT hash, prev_hash, forget_hash, seal FROM audit_forgotten",
+	DeleteAuditLinks:     "DELETE FROM audit_forgotten WHERE forget_hash NOT IN (SELECT hash FROM audit_log)",
+	GetDuressPIN:         "SELECT pin FROM duress_pins WHERE chat_id = $1",
+	SetDuressPIN:         "INSERT INTO duress_pins (chat_id, pin) VALUES ($1, $2) ON CONFLICT (chat_id) DO UPDATE SET pin = $3",
+	DeleteDuressPIN:      "DELETE FROM duress_pins WHERE chat_id = $1",
+	WipeServices:         "DELETE FROM services WHERE owner = $1",
+	WipeChat:             "DELETE FROM chats WHERE chat_id = $1",
+	WipeRotation:         "DELETE FROM rotations WHERE chat_id = $1",
+	WipeShares:           "DELETE FROM shares WHERE owner = $1 OR recipient = $2",
+	WipeShareAccesses:    "DELETE FROM share_accesses WHERE owner = $1 OR recipient = $2",
+	WipeOneTime:          "DELETE FROM onetime_secrets WHERE owner = $1",
+	DeleteUserLockout:    "DELETE FROM lockouts WHERE chat_id = $1",
+	DeleteUserMember:     "DELETE FROM members WHERE user_id = $1",
+	DeleteUserName:       "DELETE FROM users WHERE user_id = $1",
+	DeleteUserVaults:     "DELETE FROM vault_members WHERE user_id = $1",
+	DeleteUserAudit:      "DELETE FROM audit_log WHERE chat_id = $1",
+	GetUserData: "SELECT (SELECT COUNT(*) FROM services WHERE owner = $1), " +
+		"(SELECT COALESCE(MIN(created_at), 0) FROM services WHERE owner = $2 AND created_at > 0), " +
+		"(SELECT COALESCE(MAX(updated_at), 0) FROM services WHERE owner = $3), " +
+		"(SELECT COUNT(*) FROM shares WHERE owner = $4), " +
+		"(SELECT COUNT(*) FROM shares WHERE recipient = $5), " +
+		"(SELECT COUNT(*) FROM onetime_secrets WHERE owner = $6), " +
+		"(SELECT COUNT(*) FROM audit_log WHERE chat_id = $7), " +
+		"(SELECT COALESCE(MIN(created_at), 0) FROM audit_log WHERE chat_id = $8), " +
+		"(SELECT COUNT(*) FROM vault_members WHERE user_id = $9), " +
+		"(SELECT COUNT(*) FROM duress_pins WHERE chat_id = $10), " +
+		"(SELECT COUNT(*) FROM files WHERE owner = $11)",
+	SaveFile: "INSERT INTO files (owner, service, name, file_name, photo, data, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) " +
+		"ON CONFLICT (owner, service) DO UPDATE SET name = $8, file_name = $9, photo = $10, data = $11, created_at = $12",
+	GetFile:              "SELECT name, file_name, photo, data, created_at FROM files WHERE owner = $1 AND service = $2",
+	GetFiles:             "SELECT service, name, file_name, photo, created_at FROM files WHERE owner = $1",
+	DeleteFile:           "DELETE FROM files WHERE owner = $1 AND service = $2",
+	WipeFiles:            "DELETE FROM files WHERE owner = $1",
+	GetExpiringEntries:   "SELECT owner, service, name, expires_at, expiry_warned FROM services WHERE expires_at > 0 AND expires_at <= $1",
+	SetExpiryWarned:      "UPDATE services SET expiry_warned = TRUE WHERE owner = $1 AND service = $2",
+	DeleteExpiredService: "DELETE FROM services WHERE owner = $1 AND service = $2 AND expires_at > 0 AND expires_at <= $3",
+	GetDisplayMode:       "SELECT mode FROM display_modes WHERE chat_id = $1",
+	SetDisplayMode:       "INSERT INTO display_modes (chat_id, mode) VALUES ($1, $2) ON CONFLICT (chat_id) DO UPDATE SET mode = $3",
+	DeleteDisplayMode:    "DELETE FROM display_modes WHERE chat_id = $1",
 }
 
 // ErrNotFound occurs when query was not found.
@@ -2,12 +2,18 @@ package sqlite
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"password-keeper/internal/entity"
 	"password-keeper/internal/storage/queries"
+	"password-keeper/internal/storage/service"
 	"reflect"
+	"sort"
+	"strconv"
 	"testing"
+	"time"
 )
 
 var st *Sqlite3
@@ -209,7 +215,7 @@ func TestDB_GetLang(t *testing.T) {
 				t.Errorf("GetLang() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
+			if got.Code != tt.want {
 				t.Errorf("GetLang() got = %v, want %v", got, tt.want)
 			}
 		})
@@ -282,7 +288,7 @@ func TestDB_Save(t *testing.T) {
 func TestDB_SetLang(t *testing.T) {
 	type args struct {
 		chatID int64
-		lang   string
+		lang   entity.Language
 	}
 	tests := []struct {
 		name    string
@@ -293,21 +299,28 @@ func TestDB_SetLang(t *testing.T) {
 			name: "ok",
 			args: args{
 				chatID: 111,
-				lang:   "ru",
+				lang:   entity.Language{Code: "ru"},
 			},
 		},
 		{
 			name: "ok 2",
 			args: args{
 				chatID: 222,
-				lang:   "en",
+				lang:   entity.Language{Code: "en"},
 			},
 		},
 		{
 			name: "duplicate",
 			args: args{
 				chatID: 222,
-				lang:   "en",
+				lang:   entity.Language{Code: "en"},
+			},
+		},
+		{
+			name: "auto",
+			args: args{
+				chatID: 333,
+				lang:   entity.Language{Code: "ru", Auto: true},
 			},
 		},
 	}
@@ -329,3 +342,804 @@ func TestDB_SetLang(t *testing.T) {
 		})
 	}
 }
+
+func TestDB_Lockout(t *testing.T) {
+	type args struct {
+		chatID  int64
+		lockout entity.Lockout
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			args: args{
+				chatID: 111,
+				lockout: entity.Lockout{
+					Failures:    2,
+					WindowStart: time.Unix(1700000000, 0),
+				},
+			},
+		},
+		{
+			name: "locked",
+			args: args{
+				chatID: 111,
+				lockout: entity.Lockout{
+					Level:       1,
+					WindowStart: time.Unix(1700000000, 0),
+					LockedUntil: time.Unix(1700000060, 0),
+				},
+			},
+		},
+		{
+			name: "zero",
+			args: args{
+				chatID: 222,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := st.SetLockout(tt.args.chatID, tt.args.lockout); (err != nil) != tt.wantErr {
+				t.Errorf("SetLockout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr {
+				got, err := st.GetLockout(tt.args.chatID)
+				if err != nil {
+					t.Errorf("can't get the record: %v", err)
+				}
+				if !reflect.DeepEqual(got, tt.args.lockout) {
+					t.Errorf("GetLockout() got = %v, want %v", got, tt.args.lockout)
+				}
+			}
+		})
+	}
+}
+
+func TestDB_Member(t *testing.T) {
+	type args struct {
+		member entity.Member
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			args: args{
+				member: entity.Member{
+					UserID:    1001,
+					Username:  "alice",
+					Role:      entity.RoleUser,
+					CreatedAt: time.Unix(1700000000, 0),
+				},
+			},
+		},
+		{
+			name: "revoked",
+			args: args{
+				member: entity.Member{
+					UserID:    1001,
+					Username:  "alice",
+					Role:      entity.RoleUser,
+					Revoked:   true,
+					CreatedAt: time.Unix(1700000000, 0),
+				},
+			},
+		},
+		{
+			name: "admin",
+			args: args{
+				member: entity.Member{
+					UserID:    1002,
+					Role:      entity.RoleAdmin,
+					CreatedAt: time.Unix(1700000060, 0),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := st.SaveMember(tt.args.member); (err != nil) != tt.wantErr {
+				t.Errorf("SaveMember() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr {
+				got, err := st.GetMember(tt.args.member.UserID)
+				if err != nil {
+					t.Errorf("can't get the record: %v", err)
+				}
+				if !reflect.DeepEqual(got, tt.args.member) {
+					t.Errorf("GetMember() got = %v, want %v", got, tt.args.member)
+				}
+			}
+		})
+	}
+
+	members, err := st.GetMembers()
+	if err != nil {
+		t.Fatalf("GetMembers() error = %v", err)
+	}
+	if len(members) != 2 {
+		t.Errorf("GetMembers() len = %d, want 2", len(members))
+	}
+
+	stats, err := st.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+	// the revoked member is not counted
+	if stats.Members != 1 {
+		t.Errorf("GetStats() members = %d, want 1", stats.Members)
+	}
+}
+
+func TestDB_Vault(t *testing.T) {
+	v := entity.Vault{ID: -1001, Name: "team", CreatedAt: time.Unix(1700000000, 0)}
+	if err := st.CreateVault(v); err != nil {
+		t.Fatalf("CreateVault() error = %v", err)
+	}
+	if err := st.CreateVault(entity.Vault{ID: -1002, Name: "team"}); err == nil {
+		t.Errorf("CreateVault() with a taken name error = nil")
+	}
+
+	got, err := st.GetVaultByName("team")
+	if err != nil {
+		t.Fatalf("GetVaultByName() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("GetVaultByName() got = %v, want %v", got, v)
+	}
+
+	members := []entity.VaultMember{
+		{VaultID: v.ID, UserID: 1, Role: entity.VaultOwner},
+		{VaultID: v.ID, UserID: 2, Role: entity.VaultViewer},
+	}
+	for _, m := range members {
+		if err := st.SaveVaultMember(m); err != nil {
+			t.Fatalf("SaveVaultMember() error = %v", err)
+		}
+	}
+
+	members[1].Role = entity.VaultEditor
+	if err := st.SaveVaultMember(members[1]); err != nil {
+		t.Fatalf("SaveVaultMember() error = %v", err)
+	}
+
+	gotMembers, err := st.GetVaultMembers(v.ID)
+	if err != nil {
+		t.Fatalf("GetVaultMembers() error = %v", err)
+	}
+	if !reflect.DeepEqual(gotMembers, members) {
+		t.Errorf("GetVaultMembers() got = %v, want %v", gotMembers, members)
+	}
+
+	vaults, err := st.GetUserVaults(2)
+	if err != nil {
+		t.Fatalf("GetUserVaults() error = %v", err)
+	}
+	if !reflect.DeepEqual(vaults, []entity.Vault{v}) {
+		t.Errorf("GetUserVaults() got = %v, want %v", vaults, []entity.Vault{v})
+	}
+
+	if err := st.DeleteVaultMember(v.ID, 2); err != nil {
+		t.Fatalf("DeleteVaultMember() error = %v", err)
+	}
+	if _, err := st.GetVaultMember(v.ID, 2); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetVaultMember() error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	// a vault keeps many entries
+	for _, service := range []string{"github", "gitlab"} {
+		if err := st.Save(v.ID, service, entity.Pair{Login: service, Password: "pass"}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	for _, service := range []string{"github", "gitlab"} {
+		pair, err := st.Get(v.ID, service)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if pair.Login != service {
+			t.Errorf("Get() login = %q, want %q", pair.Login, service)
+		}
+	}
+}
+
+func TestDB_Share(t *testing.T) {
+	if err := st.SaveUser(entity.User{ID: 8002, Username: "bob"}); err != nil {
+		t.Fatalf("SaveUser() error = %v", err)
+	}
+	u, err := st.GetUserByName("bob")
+	if err != nil || u.ID != 8002 {
+		t.Errorf("GetUserByName() = %v, %v, want 8002", u, err)
+	}
+
+	sh := entity.Share{
+		Owner:     8001,
+		Service:   "hash",
+		Recipient: 8002,
+		Name:      "name",
+		ReadOnly:  true,
+		ExpiresAt: time.Unix(1700003600, 0),
+		CreatedAt: time.Unix(1700000000, 0),
+		Sealed:    []byte("sealed"),
+	}
+	if err := st.SaveShare(sh); err != nil {
+		t.Fatalf("SaveShare() error = %v", err)
+	}
+
+	got, err := st.GetShare(sh.Recipient, sh.Service)
+	if err != nil {
+		t.Fatalf("GetShare() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, sh) {
+		t.Errorf("GetShare() got = %v, want %v", got, sh)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := st.AddShareAccess(sh, time.Unix(1700000100, 0)); err != nil {
+			t.Fatalf("AddShareAccess() error = %v", err)
+		}
+	}
+
+	list, err := st.GetShares(sh.Owner)
+	if err != nil {
+		t.Fatalf("GetShares() error = %v", err)
+	}
+	sh.Accesses = 2
+	if !reflect.DeepEqual(list, []entity.Share{sh}) {
+		t.Errorf("GetShares() got = %v, want %v", list, []entity.Share{sh})
+	}
+
+	if err := st.DeleteShares(sh.Owner, sh.Service); err != nil {
+		t.Fatalf("DeleteShares() error = %v", err)
+	}
+	if err := st.DeleteShare(sh.Owner, sh.Service, sh.Recipient); err == nil {
+		t.Errorf("DeleteShare() of the deleted share error = nil")
+	}
+}
+
+func TestDB_OneTime(t *testing.T) {
+	ot := entity.OneTime{
+		ID:        "abc",
+		Owner:     12001,
+		Name:      "name",
+		Secret:    "secret",
+		ExpiresAt: time.Unix(1700003600, 0),
+		CreatedAt: time.Unix(1700000000, 0),
+	}
+	old := entity.OneTime{ID: "old", Owner: 12001, Secret: "secret", ExpiresAt: time.Unix(1700000000, 0)}
+
+	for _, secret := range []entity.OneTime{ot, old} {
+		if err := st.SaveOneTime(secret); err != nil {
+			t.Fatalf("SaveOneTime() error = %v", err)
+		}
+	}
+
+	got, err := st.TakeOneTime(ot.ID)
+	if err != nil {
+		t.Fatalf("TakeOneTime() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, ot) {
+		t.Errorf("TakeOneTime() got = %v, want %v", got, ot)
+	}
+	if _, err := st.TakeOneTime(ot.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("TakeOneTime() twice error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	n, err := st.DeleteExpiredOneTime(time.Unix(1700000001, 0))
+	if err != nil || n != 1 {
+		t.Errorf("DeleteExpiredOneTime() = %d, %v, want 1", n, err)
+	}
+}
+
+func TestDB_GetAll(t *testing.T) {
+	const owner = 12101
+	want := []entity.Pair{
+		{Name: "name1", Login: "login1", Password: "pass1", UpdatedAt: time.Unix(1700000000, 0)},
+		{Name: "name2", Login: "login2", Password: "pass2"},
+	}
+	for i, pair := range want {
+		if err := st.Save(owner, fmt.Sprintf("service%d", i), pair); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	got, err := st.GetAll(owner)
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].Name < got[j].Name })
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAll() got = %v, want %v", got, want)
+	}
+}
+
+func TestDB_Rotation(t *testing.T) {
+	const owner, other = 12201, 12202
+	created := time.Unix(1700000000, 0)
+	for _, pair := range []entity.Pair{
+		{Name: "name1", UpdatedAt: created, CreatedAt: created},
+		{Name: "name1", UpdatedAt: created.Add(time.Hour), CreatedAt: created.Add(time.Hour)},
+	} {
+		if err := st.Save(owner, "service1", pair); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	if err := st.Save(other, "service2", entity.Pair{Name: "name2", UpdatedAt: created}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// the update keeps the creation time
+	pair, err := st.Get(owner, "service1")
+	if err != nil || !pair.CreatedAt.Equal(created) || !pair.UpdatedAt.Equal(created.Add(time.Hour)) {
+		t.Errorf("Get() = %+v, %v, want created at %v and updated an hour later", pair, err, created)
+	}
+
+	if _, err := st.GetRotation(owner); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetRotation() error = %v, want %v", err, sql.ErrNoRows)
+	}
+	if err := st.SetServiceRotation(owner, "missing", 24*time.Hour); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("SetServiceRotation() error = %v, want %v", err, service.ErrNotFound)
+	}
+
+	rotation := entity.Rotation{Period: 90 * 24 * time.Hour, RemindAt: created}
+	if err := st.SetRotation(owner, rotation); err != nil {
+		t.Fatalf("SetRotation() error = %v", err)
+	}
+	if err := st.SetServiceRotation(other, "service2", 30*24*time.Hour); err != nil {
+		t.Fatalf("SetServiceRotation() error = %v", err)
+	}
+
+	got, err := st.GetRotation(owner)
+	if err != nil || !reflect.DeepEqual(got, rotation) {
+		t.Errorf("GetRotation() = %v, %v, want %v", got, err, rotation)
+	}
+
+	entries, err := st.GetRotationEntries()
+	if err != nil {
+		t.Fatalf("GetRotationEntries() error = %v", err)
+	}
+	want := map[int64]entity.RotationEntry{
+		owner: {Owner: owner, Name: "name1", UpdatedAt: created.Add(time.Hour), Chat: rotation},
+		other: {Owner: other, Name: "name2", UpdatedAt: created, Period: 30 * 24 * time.Hour},
+	}
+	for _, e := range entries {
+		if w, ok := want[e.Owner]; ok {
+			if !reflect.DeepEqual(e, w) {
+				t.Errorf("GetRotationEntries() got = %v, want %v", e, w)
+			}
+			delete(want, e.Owner)
+		}
+	}
+	if len(want) != 0 {
+		t.Errorf("GetRotationEntries() missing %v", want)
+	}
+}
+
+func TestDB_AuditRecords(t *testing.T) {
+	const chatID = 12301
+	records := []entity.AuditRecord{
+		{ChatID: chatID, Action: entity.ActionSave, Service: "hash", Outcome: entity.OutcomeOK, CreatedAt: time.Unix(1000, 0)},
+		{ChatID: chatID, Action: entity.ActionGet, Service: "hash", Outcome: entity.OutcomeOK, CreatedAt: time.Unix(2000, 0)},
+		{ChatID: chatID, Action: entity.ActionLang, Outcome: entity.OutcomeOK, CreatedAt: time.Unix(3000, 0)},
+	}
+	for _, r := range records {
+		if err := st.AddAuditRecord(r, "seal-"+r.Hash); err != nil {
+			t.Fatalf("AddAuditRecord() error = %v", err)
+		}
+	}
+
+	got, err := st.GetAuditRecords(chatID, 2)
+	if err != nil {
+		t.Fatalf("GetAuditRecords() error = %v", err)
+	}
+	if want := []entity.AuditRecord{records[2], records[1]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAuditRecords() got = %v, want %v", got, want)
+	}
+
+	// the log is append-only
+	if _, err := st.DB.Exec("UPDATE audit_log SET outcome = 'error' WHERE chat_id = 12301"); err == nil {
+		t.Errorf("UPDATE of audit log error = nil, want the trigger to abort it")
+	}
+	got, err = st.GetAuditRecords(chatID, 1)
+	if err != nil || got[0].Outcome != entity.OutcomeOK {
+		t.Errorf("GetAuditRecords() after update = %v, %v, want the record unchanged", got, err)
+	}
+
+	cutoff, err := st.GetAuditCutoff(time.Unix(2500, 0))
+	if err != nil || cutoff.ID <= 0 {
+		t.Fatalf("GetAuditCutoff() = %v, %v, want the record", cutoff, err)
+	}
+	checkpoint := entity.AuditAnchor{RecordID: cutoff.ID, Hash: cutoff.Hash, Seal: "seal"}
+	if n, err := st.DeleteAuditRecords(checkpoint); err != nil || n < 2 {
+		t.Errorf("DeleteAuditRecords() = %d, %v, want at least 2", n, err)
+	}
+	got, err = st.GetAuditRecords(chatID, 10)
+	if err != nil || !reflect.DeepEqual(got, records[2:]) {
+		t.Errorf("GetAuditRecords() after delete = %v, %v, want %v", got, err, records[2:])
+	}
+
+	// the checkpoint doesn't go back
+	if _, err := st.DeleteAuditRecords(entity.AuditAnchor{RecordID: 1, Seal: "older"}); err != nil {
+		t.Errorf("DeleteAuditRecords() error = %v", err)
+	}
+	if got, err := st.GetAuditAnchor(entity.AnchorCheckpoint); err != nil || got != checkpoint {
+		t.Errorf("GetAuditAnchor() = %v, %v, want %v", got, err, checkpoint)
+	}
+}
+
+func TestDB_AuditChain(t *testing.T) {
+	const chatID, otherID = 12401, 12402
+	records := []entity.AuditRecord{
+		{ChatID: chatID, Action: entity.ActionSave, Outcome: entity.OutcomeOK, CreatedAt: time.Unix(1000, 0), PrevHash: "chain-0", Hash: "chain-1"},
+		{ChatID: otherID, Action: entity.ActionGet, Outcome: entity.OutcomeOK, CreatedAt: time.Unix(2000, 0), PrevHash: "chain-1", Hash: "chain-2"},
+	}
+	for _, r := range records {
+		if err := st.AddAuditRecord(r, "seal-"+r.Hash); err != nil {
+			t.Fatalf("AddAuditRecord() error = %v", err)
+		}
+	}
+
+	if last, err := st.GetLastAuditHash(); err != nil || last != "chain-2" {
+		t.Errorf("GetLastAuditHash() = %q, %v, want %q", last, err, "chain-2")
+	}
+	if last, err := st.GetLastChatAuditHash(chatID); err != nil || last != "chain-1" {
+		t.Errorf("GetLastChatAuditHash() = %q, %v, want %q", last, err, "chain-1")
+	}
+	if _, err := st.GetLastChatAuditHash(12403); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetLastChatAuditHash() of chat without records error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	// the chain can't fork
+	fork := entity.AuditRecord{ChatID: chatID, Action: entity.ActionGet, Outcome: entity.OutcomeOK, PrevHash: "chain-1", Hash: "fork"}
+	if err := st.AddAuditRecord(fork, "seal-fork"); err == nil {
+		t.Errorf("AddAuditRecord() of fork error = nil, want unique violation")
+	}
+
+	// the head is the last record, the failed fork doesn't move it
+	head, err := st.GetAuditAnchor(entity.AnchorHead)
+	if err != nil || head.RecordID <= 0 || head.Hash != "chain-2" || head.Seal != "seal-chain-2" {
+		t.Errorf("GetAuditAnchor() = %v, %v, want the head at %q", head, err, "chain-2")
+	}
+
+	got, err := st.GetAuditChain(0, 1000)
+	if err != nil {
+		t.Fatalf("GetAuditChain() error = %v", err)
+	}
+	var chain []entity.AuditRecord
+	for _, r := range got {
+		if r.ChatID == chatID || r.ChatID == otherID {
+			if r.ID <= 0 {
+				t.Errorf("GetAuditChain() record id = %d, want positive", r.ID)
+			}
+			r.ID = 0
+			chain = append(chain, r)
+		}
+	}
+	if !reflect.DeepEqual(chain, records) {
+		t.Errorf("GetAuditChain() got = %v, want %v", chain, records)
+	}
+}
+
+func TestDB_Wipe(t *testing.T) {
+	const chatID, other = 12501, 12502
+	for _, owner := range []int64{chatID, other} {
+		if err := st.Save(owner, "service", entity.Pair{Name: "name"}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if err := st.SetLang(owner, entity.Language{Code: "ru"}); err != nil {
+			t.Fatalf("SetLang() error = %v", err)
+		}
+		if err := st.SetDuressPIN(owner, "pin"); err != nil {
+			t.Fatalf("SetDuressPIN() error = %v", err)
+		}
+		if err := st.SaveFile(owner, entity.File{Service: "service", Data: []byte("data")}); err != nil {
+			t.Fatalf("SaveFile() error = %v", err)
+		}
+		if err := st.SetDisplayMode(owner, "login"); err != nil {
+			t.Fatalf("SetDisplayMode() error = %v", err)
+		}
+	}
+	if err := st.SetRotation(chatID, entity.Rotation{Period: 24 * time.Hour}); err != nil {
+		t.Fatalf("SetRotation() error = %v", err)
+	}
+	for _, share := range []entity.Share{
+		{Owner: chatID, Service: "service", Recipient: other},
+		{Owner: other, Service: "service", Recipient: chatID},
+	} {
+		if err := st.SaveShare(share); err != nil {
+			t.Fatalf("SaveShare() error = %v", err)
+		}
+		if err := st.AddShareAccess(share, time.Unix(1000, 0)); err != nil {
+			t.Fatalf("AddShareAccess() error = %v", err)
+		}
+	}
+	if err := st.SaveOneTime(entity.OneTime{ID: "wipe", Owner: chatID, ExpiresAt: time.Unix(2000, 0)}); err != nil {
+		t.Fatalf("SaveOneTime() error = %v", err)
+	}
+
+	last, err := st.GetLastAuditHash()
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("GetLastAuditHash() error = %v", err)
+	}
+	record := entity.AuditRecord{ChatID: chatID, Action: entity.ActionGet, Outcome: entity.OutcomeOK, CreatedAt: time.Unix(1000, 0), PrevHash: last, Hash: "wipe-1"}
+	if err := st.AddAuditRecord(record, "seal-wipe-1"); err != nil {
+		t.Fatalf("AddAuditRecord() error = %v", err)
+	}
+
+	var forgotten []entity.AuditLink
+	forget := func(prevHash, chatPrevHash string, removed []entity.AuditLink) (entity.AuditRecord, []entity.AuditLink, string) {
+		forgotten = removed
+		r := entity.AuditRecord{Action: entity.ActionForget, Outcome: entity.OutcomeOK, CreatedAt: time.Unix(2000, 0), PrevHash: prevHash, ChatPrevHash: chatPrevHash, Hash: "wipe-forget"}
+		return r, nil, "seal-wipe-forget"
+	}
+	if err := st.Wipe(chatID, forget); err != nil {
+		t.Fatalf("Wipe() error = %v", err)
+	}
+	if want := []entity.AuditLink{{Hash: "wipe-1", PrevHash: last}}; !reflect.DeepEqual(forgotten, want) {
+		t.Errorf("Wipe() forgot %v, want %v", forgotten, want)
+	}
+
+	for _, table := range []string{"services", "chats", "rotations", "shares", "share_accesses", "onetime_secrets", "duress_pins", "files", "display_modes", "audit_log"} {
+		column := map[string]string{"services": "owner", "shares": "owner", "share_accesses": "owner", "onetime_secrets": "owner", "files": "owner"}[table]
+		if column == "" {
+			column = "chat_id"
+		}
+
+		var n int
+		query := "SELECT COUNT(*) FROM " + table + " WHERE " + column + " = " + strconv.Itoa(chatID)
+		if table == "shares" || table == "share_accesses" {
+			query += " OR recipient = " + strconv.Itoa(chatID)
+		}
+		if err := st.DB.QueryRow(query).Scan(&n); err != nil || n != 0 {
+			t.Errorf("%s of wiped chat = %d, %v, want 0", table, n, err)
+		}
+	}
+
+	// the other chat keeps its data
+	if _, err := st.Get(other, "service"); err != nil {
+		t.Errorf("Get() of other chat error = %v", err)
+	}
+	if pin, err := st.GetDuressPIN(other); err != nil || pin != "pin" {
+		t.Errorf("GetDuressPIN() of other chat = %q, %v, want %q", pin, err, "pin")
+	}
+	if _, err := st.GetFile(other, "service"); err != nil {
+		t.Errorf("GetFile() of other chat error = %v", err)
+	}
+	if mode, err := st.GetDisplayMode(other); err != nil || mode != "login" {
+		t.Errorf("GetDisplayMode() of other chat = %q, %v, want %q", mode, err, "login")
+	}
+}
+
+func TestDB_DeleteUser(t *testing.T) {
+	const chatID, other = 12601, 12602
+	created := time.Unix(1700000000, 0)
+	for _, owner := range []int64{chatID, other} {
+		if err := st.Save(owner, "service1", entity.Pair{Name: "name", CreatedAt: created, UpdatedAt: created}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if err := st.SaveMember(entity.Member{UserID: owner, Role: "user"}); err != nil {
+			t.Fatalf("SaveMember() error = %v", err)
+		}
+	}
+	if err := st.Save(chatID, "service2", entity.Pair{Name: "name", CreatedAt: created.Add(time.Hour), UpdatedAt: created.Add(2 * time.Hour)}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := st.SaveShare(entity.Share{Owner: other, Service: "service1", Recipient: chatID}); err != nil {
+		t.Fatalf("SaveShare() error = %v", err)
+	}
+	if err := st.SaveUser(entity.User{ID: chatID, Username: "forgotten"}); err != nil {
+		t.Fatalf("SaveUser() error = %v", err)
+	}
+	if err := st.SetLockout(chatID, entity.Lockout{Failures: 3}); err != nil {
+		t.Fatalf("SetLockout() error = %v", err)
+	}
+	if err := st.SaveVaultMember(entity.VaultMember{VaultID: -12603, UserID: chatID, Role: entity.VaultOwner}); err != nil {
+		t.Fatalf("SaveVaultMember() error = %v", err)
+	}
+	// the records of the other chat are between the records of the chat
+	last, err := st.GetLastAuditHash()
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("GetLastAuditHash() error = %v", err)
+	}
+	var removed []entity.AuditLink
+	for i, owner := range []int64{chatID, other, chatID} {
+		r := entity.AuditRecord{ChatID: owner, Action: entity.ActionGet, Outcome: entity.OutcomeOK, CreatedAt: created, PrevHash: last, Hash: fmt.Sprintf("forgetme-%d", i)}
+		if err := st.AddAuditRecord(r, "seal-"+r.Hash); err != nil {
+			t.Fatalf("AddAuditRecord() error = %v", err)
+		}
+		if owner == chatID {
+			removed = append(removed, entity.AuditLink{Hash: r.Hash, PrevHash: r.PrevHash})
+		}
+		last = r.Hash
+	}
+
+	want := entity.UserData{
+		Services:       2,
+		FirstSaved:     created,
+		LastUpdated:    created.Add(2 * time.Hour),
+		SharesReceived: 1,
+		AuditRecords:   2,
+		AuditSince:     created,
+		Vaults:         1,
+	}
+	if got, err := st.GetUserData(chatID); err != nil || got != want {
+		t.Errorf("GetUserData() = %+v, %v, want %+v", got, err, want)
+	}
+
+	var forgetPrev string
+	var forgetRemoved, links []entity.AuditLink
+	forget := func(prevHash, chatPrevHash string, removed []entity.AuditLink) (entity.AuditRecord, []entity.AuditLink, string) {
+		forgetPrev, forgetRemoved = prevHash, removed
+		r := entity.AuditRecord{Action: entity.ActionForget, Outcome: entity.OutcomeOK, CreatedAt: created, PrevHash: prevHash, ChatPrevHash: chatPrevHash, Hash: "forgetme-forget"}
+		for _, l := range removed {
+			l.ForgetHash, l.Seal = r.Hash, "seal-"+l.Hash
+			links = append(links, l)
+		}
+		return r, links, "seal-forget"
+	}
+	if err := st.DeleteUser(chatID, forget); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+
+	// the audit log records are deleted too, the forget record continues the last one with their links
+	if got, err := st.GetUserData(chatID); err != nil || got != (entity.UserData{}) {
+		t.Errorf("GetUserData() after DeleteUser() = %+v, %v, want nothing", got, err)
+	}
+	if forgetPrev != last || !reflect.DeepEqual(forgetRemoved, removed) {
+		t.Errorf("DeleteUser() forgot %v after %q, want %v after %q", forgetRemoved, forgetPrev, removed, last)
+	}
+	var gotLinks []entity.AuditLink
+	all, err := st.GetAuditLinks()
+	for _, l := range all {
+		if l.ForgetHash == "forgetme-forget" {
+			gotLinks = append(gotLinks, l)
+		}
+	}
+	if err != nil || !reflect.DeepEqual(gotLinks, links) {
+		t.Errorf("GetAuditLinks() = %v, %v, want %v", gotLinks, err, links)
+	}
+	if head, err := st.GetAuditAnchor(entity.AnchorHead); err != nil || head.Hash != "forgetme-forget" || head.Seal != "seal-forget" {
+		t.Errorf("GetAuditAnchor() = %v, %v, want the head at %q", head, err, "forgetme-forget")
+	}
+	if _, err := st.GetMember(chatID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetMember() error = %v, want %v", err, sql.ErrNoRows)
+	}
+	if _, err := st.GetUserByName("forgotten"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetUserByName() error = %v, want %v", err, sql.ErrNoRows)
+	}
+	if _, err := st.GetLockout(chatID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetLockout() error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	// the other user keeps the data but the share with the deleted one
+	if got, err := st.GetUserData(other); err != nil || got.Services != 1 || got.SharesGiven != 0 {
+		t.Errorf("GetUserData() of other user = %+v, %v, want 1 service and no shares", got, err)
+	}
+	if _, err := st.GetMember(other); err != nil {
+		t.Errorf("GetMember() of other user error = %v", err)
+	}
+}
+
+func TestDB_Files(t *testing.T) {
+	const chatID = 12701
+	created := time.Unix(1700000000, 0)
+	files := []entity.File{
+		{Service: "service1", Name: "name1", FileName: "id_rsa", Data: []byte("key"), CreatedAt: created},
+		{Service: "service2", Name: "name2", Photo: true, Data: []byte("photo"), CreatedAt: created},
+	}
+	for _, f := range files {
+		if err := st.SaveFile(chatID, f); err != nil {
+			t.Fatalf("SaveFile() error = %v", err)
+		}
+	}
+
+	// saving again replaces the file
+	files[0].FileName, files[0].Data = "id_ed25519", []byte("new key")
+	if err := st.SaveFile(chatID, files[0]); err != nil {
+		t.Fatalf("SaveFile() error = %v", err)
+	}
+
+	for _, want := range files {
+		got, err := st.GetFile(chatID, want.Service)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("GetFile() = %+v, %v, want %+v", got, err, want)
+		}
+	}
+	if _, err := st.GetFile(chatID+1, "service1"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetFile() of other chat error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	list, err := st.GetFiles(chatID)
+	if err != nil {
+		t.Fatalf("GetFiles() error = %v", err)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Service < list[j].Service })
+	for i := range files {
+		files[i].Data = nil
+	}
+	if !reflect.DeepEqual(list, files) {
+		t.Errorf("GetFiles() = %+v, want %+v", list, files)
+	}
+
+	if d, err := st.GetUserData(chatID); err != nil || d.Files != 2 {
+		t.Errorf("GetUserData() files = %d, %v, want 2", d.Files, err)
+	}
+
+	if err := st.DeleteFile(chatID, "service1"); err != nil {
+		t.Errorf("DeleteFile() error = %v", err)
+	}
+	if err := st.DeleteFile(chatID, "service1"); !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("DeleteFile() of deleted file error = %v, want %v", err, service.ErrNotFound)
+	}
+}
+
+func TestDB_Expiring(t *testing.T) {
+	const chatID = 12801
+	expires := time.Unix(1800000000, 0)
+	pairs := map[string]entity.Pair{
+		"trial":     {Name: "trial", Login: "me", Password: "key", ExpiresAt: expires},
+		"contract":  {Name: "contract", Login: "me", Password: "pass", ExpiresAt: expires.Add(time.Hour), ExpiryWarned: true},
+		"permanent": {Name: "permanent", Login: "me", Password: "pass"},
+	}
+	for service, pair := range pairs {
+		if err := st.Save(chatID, service, pair); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	if got, err := st.Get(chatID, "contract"); err != nil || !reflect.DeepEqual(got, pairs["contract"]) {
+		t.Errorf("Get() = %+v, %v, want %+v", got, err, pairs["contract"])
+	}
+
+	entries, err := st.GetExpiringEntries(expires.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetExpiringEntries() error = %v", err)
+	}
+	var got []entity.ExpiringEntry
+	for _, e := range entries {
+		if e.Owner == chatID {
+			got = append(got, e)
+		}
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].ExpiresAt.Before(got[j].ExpiresAt) })
+	want := []entity.ExpiringEntry{
+		{Owner: chatID, Service: "trial", Name: "trial", ExpiresAt: expires},
+		{Owner: chatID, Service: "contract", Name: "contract", ExpiresAt: expires.Add(time.Hour), ExpiryWarned: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetExpiringEntries() = %+v, want %+v", got, want)
+	}
+
+	if err := st.SetExpiryWarned(chatID, "trial"); err != nil {
+		t.Errorf("SetExpiryWarned() error = %v", err)
+	}
+	if got, err := st.Get(chatID, "trial"); err != nil || !got.ExpiryWarned {
+		t.Errorf("Get() after SetExpiryWarned() = %+v, %v, want warned", got, err)
+	}
+
+	tests := []struct {
+		name    string
+		service string
+		wantErr error
+	}{
+		{name: "not expired yet", service: "contract", wantErr: service.ErrNotFound},
+		{name: "never expires", service: "permanent", wantErr: service.ErrNotFound},
+		{name: "expired", service: "trial"},
+		{name: "deleted", service: "trial", wantErr: service.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := st.DeleteExpired(chatID, tt.service, expires); !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeleteExpired() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,9 +2,11 @@ package sqllike
 
 import (
 	"database/sql"
+	"errors"
 	"password-keeper/internal/entity"
 	"password-keeper/internal/storage/queries"
 	"password-keeper/internal/storage/service"
+	"time"
 )
 
 // DB is sql-like storage.
@@ -18,7 +20,9 @@ func (db DB) Save(chatID int64, service string, pair entity.Pair) error {
 	if err != nil {
 		return err
 	}
-	_, err = prep.Exec(service, pair.Login, pair.Password, chatID, pair.Login, pair.Password, chatID)
+	updatedAt, expiresAt := unix(pair.UpdatedAt), unix(pair.ExpiresAt)
+	_, err = prep.Exec(service, pair.Name, pair.Login, pair.Password, chatID, updatedAt, unix(pair.CreatedAt), expiresAt, pair.ExpiryWarned,
+		pair.Name, pair.Login, pair.Password, chatID, updatedAt, expiresAt, pair.ExpiryWarned)
 	return err
 }
 
@@ -29,9 +33,32 @@ func (db DB) Get(chatID int64, service string) (entity.Pair, error) {
 		return entity.Pair{}, err
 	}
 
-	var pair entity.Pair
-	err = prep.QueryRow(service, chatID).Scan(&pair.Login, &pair.Password)
-	return pair, err
+	return scanPair(prep.QueryRow(service, chatID))
+}
+
+// GetAll gets all services of chat.
+func (db DB) GetAll(chatID int64) ([]entity.Pair, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetServices)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := prep.Query(chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pairs []entity.Pair
+	for rows.Next() {
+		pair, err := scanPair(rows)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair)
+	}
+
+	return pairs, rows.Err()
 }
 
 // Delete deletes service from chat.
@@ -56,23 +83,1159 @@ func (db DB) Delete(chatID int64, serviceName string) error {
 }
 
 // GetLang gets language for chat.
-func (db DB) GetLang(chatID int64) (string, error) {
+func (db DB) GetLang(chatID int64) (entity.Language, error) {
 	prep, err := queries.GetPreparedStatement(queries.GetLang)
 	if err != nil {
-		return "", err
+		return entity.Language{}, err
 	}
 
-	var lang string
-	err = prep.QueryRow(chatID).Scan(&lang)
+	var lang entity.Language
+	err = prep.QueryRow(chatID).Scan(&lang.Code, &lang.Auto)
 	return lang, err
 }
 
 // SetLang sets language for chat.
-func (db DB) SetLang(chatID int64, lang string) error {
+func (db DB) SetLang(chatID int64, lang entity.Language) error {
 	prep, err := queries.GetPreparedStatement(queries.AddOrUpdateChatLang)
 	if err != nil {
 		return err
 	}
-	_, err = prep.Exec(chatID, lang, lang)
+	_, err = prep.Exec(chatID, lang.Code, lang.Auto, lang.Code, lang.Auto)
+	return err
+}
+
+// GetLockout gets lockout state for chat.
+func (db DB) GetLockout(chatID int64) (entity.Lockout, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetLockout)
+	if err != nil {
+		return entity.Lockout{}, err
+	}
+
+	var l entity.Lockout
+	var windowStart, lockedUntil int64
+	err = prep.QueryRow(chatID).Scan(&l.Failures, &windowStart, &l.Level, &lockedUntil)
+	if err != nil {
+		return entity.Lockout{}, err
+	}
+
+	l.WindowStart = unixTime(windowStart)
+	l.LockedUntil = unixTime(lockedUntil)
+	return l, nil
+}
+
+// SetLockout sets lockout state for chat.
+func (db DB) SetLockout(chatID int64, l entity.Lockout) error {
+	prep, err := queries.GetPreparedStatement(queries.SetLockout)
+	if err != nil {
+		return err
+	}
+
+	windowStart, lockedUntil := unix(l.WindowStart), unix(l.LockedUntil)
+	_, err = prep.Exec(
+		chatID, l.Failures, windowStart, l.Level, lockedUntil,
+		l.Failures, windowStart, l.Level, lockedUntil,
+	)
+	return err
+}
+
+// GetMember gets member by user ID.
+func (db DB) GetMember(userID int64) (entity.Member, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetMember)
+	if err != nil {
+		return entity.Member{}, err
+	}
+
+	return scanMember(prep.QueryRow(userID))
+}
+
+// SaveMember adds or updates member.
+func (db DB) SaveMember(m entity.Member) error {
+	prep, err := queries.GetPreparedStatement(queries.SaveMember)
+	if err != nil {
+		return err
+	}
+
+	_, err = prep.Exec(
+		m.UserID, m.Username, m.Role, m.Revoked, unix(m.CreatedAt),
+		m.Username, m.Role, m.Revoked,
+	)
+	return err
+}
+
+// GetMembers gets all members.
+func (db DB) GetMembers() ([]entity.Member, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetMembers)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := prep.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []entity.Member
+	for rows.Next() {
+		m, err := scanMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+
+	return members, rows.Err()
+}
+
+// GetStats counts members, chats and services.
+func (db DB) GetStats() (entity.Stats, error) {
+	var stats entity.Stats
+	counters := []struct {
+		query int
+		dest  *int
+	}{
+		{queries.CountMembers, &stats.Members},
+		{queries.CountChats, &stats.Chats},
+		{queries.CountServices, &stats.Services},
+	}
+
+	for _, c := range counters {
+		prep, err := queries.GetPreparedStatement(c.query)
+		if err != nil {
+			return entity.Stats{}, err
+		}
+
+		if err := prep.QueryRow().Scan(c.dest); err != nil {
+			return entity.Stats{}, err
+		}
+	}
+
+	return stats, nil
+}
+
+// CreateVault adds vault.
+func (db DB) CreateVault(v entity.Vault) error {
+	prep, err := queries.GetPreparedStatement(queries.CreateVault)
+	if err != nil {
+		return err
+	}
+
+	_, err = prep.Exec(v.ID, v.Name, unix(v.CreatedAt))
+	return err
+}
+
+// GetVault gets vault by ID.
+func (db DB) GetVault(vaultID int64) (entity.Vault, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetVault)
+	if err != nil {
+		return entity.Vault{}, err
+	}
+
+	return scanVault(prep.QueryRow(vaultID))
+}
+
+// GetVaultByName gets vault by name.
+func (db DB) GetVaultByName(name string) (entity.Vault, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetVaultByName)
+	if err != nil {
+		return entity.Vault{}, err
+	}
+
+	return scanVault(prep.QueryRow(name))
+}
+
+// GetUserVaults gets vaults the user is a member of.
+func (db DB) GetUserVaults(userID int64) ([]entity.Vault, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetUserVaults)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := prep.Query(userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vaults []entity.Vault
+	for rows.Next() {
+		v, err := scanVault(rows)
+		if err != nil {
+			return nil, err
+		}
+		vaults = append(vaults, v)
+	}
+
+	return vaults, rows.Err()
+}
+
+// SaveVaultMember adds or updates vault member.
+func (db DB) SaveVaultMember(m entity.VaultMember) error {
+	prep, err := queries.GetPreparedStatement(queries.SaveVaultMember)
+	if err != nil {
+		return err
+	}
+
+	_, err = prep.Exec(m.VaultID, m.UserID, m.Role, m.Role)
+	return err
+}
+
+// GetVaultMember gets vault member.
+func (db DB) GetVaultMember(vaultID, userID int64) (entity.VaultMember, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetVaultMember)
+	if err != nil {
+		return entity.VaultMember{}, err
+	}
+
+	var m entity.VaultMember
+	err = prep.QueryRow(vaultID, userID).Scan(&m.VaultID, &m.UserID, &m.Role)
+	return m, err
+}
+
+// GetVaultMembers gets all vault members.
+func (db DB) GetVaultMembers(vaultID int64) ([]entity.VaultMember, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetVaultMembers)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := prep.Query(vaultID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []entity.VaultMember
+	for rows.Next() {
+		var m entity.VaultMember
+		if err := rows.Scan(&m.VaultID, &m.UserID, &m.Role); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+
+	return members, rows.Err()
+}
+
+// DeleteVaultMember deletes vault member.
+func (db DB) DeleteVaultMember(vaultID, userID int64) error {
+	prep, err := queries.GetPreparedStatement(queries.DeleteVaultMember)
+	if err != nil {
+		return err
+	}
+
+	r, err := prep.Exec(vaultID, userID)
+	if err != nil {
+		return err
+	}
+	a, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if a == 0 {
+		return service.ErrNotFound
+	}
+	return nil
+}
+
+// SaveUser adds or updates user.
+func (db DB) SaveUser(u entity.User) error {
+	prep, err := queries.GetPreparedStatement(queries.SaveUser)
+	if err != nil {
+		return err
+	}
+
+	_, err = prep.Exec(u.ID, u.Username, u.Username)
+	return err
+}
+
+// GetUserByName gets user by username.
+func (db DB) GetUserByName(username string) (entity.User, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetUserByName)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	var u entity.User
+	err = prep.QueryRow(username).Scan(&u.ID, &u.Username)
+	return u, err
+}
+
+// SaveShare adds or updates share.
+func (db DB) SaveShare(sh entity.Share) error {
+	prep, err := queries.GetPreparedStatement(queries.SaveShare)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := unix(sh.ExpiresAt)
+	_, err = prep.Exec(
+		sh.Owner, sh.Service, sh.Recipient, sh.Name, sh.ReadOnly, expiresAt, unix(sh.CreatedAt), sh.Sealed,
+		sh.Name, sh.ReadOnly, expiresAt, sh.Sealed,
+	)
+	return err
+}
+
+// GetShare gets the latest share of the service with the recipient.
+func (db DB) GetShare(recipient int64, service string) (entity.Share, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetShare)
+	if err != nil {
+		return entity.Share{}, err
+	}
+
+	return scanShare(prep.QueryRow(recipient, service))
+}
+
+// GetShares gets all shares of the owner.
+func (db DB) GetShares(owner int64) ([]entity.Share, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetShares)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := prep.Query(owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shares []entity.Share
+	for rows.Next() {
+		sh, err := scanShare(rows)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, sh)
+	}
+
+	return shares, rows.Err()
+}
+
+// DeleteShare deletes share.
+func (db DB) DeleteShare(owner int64, serviceName string, recipient int64) error {
+	prep, err := queries.GetPreparedStatement(queries.DeleteShare)
+	if err != nil {
+		return err
+	}
+
+	r, err := prep.Exec(owner, serviceName, recipient)
+	if err != nil {
+		return err
+	}
+	a, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if a == 0 {
+		return service.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteShares deletes all shares of the service.
+func (db DB) DeleteShares(owner int64, serviceName string) error {
+	prep, err := queries.GetPreparedStatement(queries.DeleteShares)
+	if err != nil {
+		return err
+	}
+
+	_, err = prep.Exec(owner, serviceName)
+	return err
+}
+
+// AddShareAccess adds share access.
+func (db DB) AddShareAccess(sh entity.Share, at time.Time) error {
+	prep, err := queries.GetPreparedStatement(queries.AddShareAccess)
+	if err != nil {
+		return err
+	}
+
+	_, err = prep.Exec(sh.Owner, sh.Service, sh.Recipient, unix(at))
+	return err
+}
+
+// SaveOneTime adds one-time secret.
+func (db DB) SaveOneTime(ot entity.OneTime) error {
+	prep, err := queries.GetPreparedStatement(queries.SaveOneTime)
+	if err != nil {
+		return err
+	}
+
+	_, err = prep.Exec(ot.ID, ot.Owner, ot.Name, ot.Secret, unix(ot.ExpiresAt), unix(ot.CreatedAt))
+	return err
+}
+
+// TakeOneTime gets and deletes one-time secret.
+// When it's taken concurrently, only one caller gets it.
+func (db DB) TakeOneTime(id string) (entity.OneTime, error) {
+	get, err := queries.GetPreparedStatement(queries.GetOneTime)
+	if err != nil {
+		return entity.OneTime{}, err
+	}
+
+	var ot entity.OneTime
+	var expiresAt, createdAt int64
+	err = get.QueryRow(id).Scan(&ot.ID, &ot.Owner, &ot.Name, &ot.Secret, &expiresAt, &createdAt)
+	if err != nil {
+		return entity.OneTime{}, err
+	}
+	ot.ExpiresAt = unixTime(expiresAt)
+	ot.CreatedAt = unixTime(createdAt)
+
+	del, err := queries.GetPreparedStatement(queries.DeleteOneTime)
+	if err != nil {
+		return entity.OneTime{}, err
+	}
+
+	r, err := del.Exec(id)
+	if err != nil {
+		return entity.OneTime{}, err
+	}
+	a, err := r.RowsAffected()
+	if err != nil {
+		return entity.OneTime{}, err
+	}
+	if a == 0 {
+		return entity.OneTime{}, sql.ErrNoRows
+	}
+
+	return ot, nil
+}
+
+// DeleteExpiredOneTime deletes one-time secrets expired before t.
+func (db DB) DeleteExpiredOneTime(t time.Time) (int64, error) {
+	prep, err := queries.GetPreparedStatement(queries.DeleteExpiredOneTime)
+	if err != nil {
+		return 0, err
+	}
+
+	r, err := prep.Exec(unix(t))
+	if err != nil {
+		return 0, err
+	}
+	return r.RowsAffected()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPair(row scanner) (entity.Pair, error) {
+	var pair entity.Pair
+	var updatedAt, createdAt, expiresAt int64
+	err := row.Scan(&pair.Name, &pair.Login, &pair.Password, &updatedAt, &createdAt, &expiresAt, &pair.ExpiryWarned)
+	pair.UpdatedAt = unixTime(updatedAt)
+	pair.CreatedAt = unixTime(createdAt)
+	pair.ExpiresAt = unixTime(expiresAt)
+	return pair, err
+}
+
+func scanMember(row scanner) (entity.Member, error) {
+	var m entity.Member
+	var createdAt int64
+	if err := row.Scan(&m.UserID, &m.Username, &m.Role, &m.Revoked, &createdAt); err != nil {
+		return entity.Member{}, err
+	}
+
+	m.CreatedAt = unixTime(createdAt)
+	return m, nil
+}
+
+func scanVault(row scanner) (entity.Vault, error) {
+	var v entity.Vault
+	var createdAt int64
+	if err := row.Scan(&v.ID, &v.Name, &createdAt); err != nil {
+		return entity.Vault{}, err
+	}
+
+	v.CreatedAt = unixTime(createdAt)
+	return v, nil
+}
+
+func scanShare(row scanner) (entity.Share, error) {
+	var sh entity.Share
+	var expiresAt, createdAt int64
+	err := row.Scan(&sh.Owner, &sh.Service, &sh.Recipient, &sh.Name, &sh.ReadOnly, &expiresAt, &createdAt, &sh.Sealed, &sh.Accesses)
+	if err != nil {
+		return entity.Share{}, err
+	}
+
+	sh.ExpiresAt = unixTime(expiresAt)
+	sh.CreatedAt = unixTime(createdAt)
+	return sh, nil
+}
+
+// SetServiceRotation sets rotation period of service.
+func (db DB) SetServiceRotation(chatID int64, serviceName string, period time.Duration) error {
+	prep, err := queries.GetPreparedStatement(queries.SetServiceRotation)
+	if err != nil {
+		return err
+	}
+
+	r, err := prep.Exec(days(period), serviceName, chatID)
+	if err != nil {
+		return err
+	}
+	a, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if a == 0 {
+		return service.ErrNotFound
+	}
+	return nil
+}
+
+// GetRotation gets rotation settings of chat.
+func (db DB) GetRotation(chatID int64) (entity.Rotation, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetRotation)
+	if err != nil {
+		return entity.Rotation{}, err
+	}
+
+	var periodDays, remindAt int64
+	if err := prep.QueryRow(chatID).Scan(&periodDays, &remindAt); err != nil {
+		return entity.Rotation{}, err
+	}
+	return entity.Rotation{Period: duration(periodDays), RemindAt: unixTime(remindAt)}, nil
+}
+
+// SetRotation sets rotation settings of chat.
+func (db DB) SetRotation(chatID int64, r entity.Rotation) error {
+	prep, err := queries.GetPreparedStatement(queries.SetRotation)
+	if err != nil {
+		return err
+	}
+
+	periodDays, remindAt := days(r.Period), unix(r.RemindAt)
+	_, err = prep.Exec(chatID, periodDays, remindAt, periodDays, remindAt)
+	return err
+}
+
+// GetRotationEntries gets all services with rotation period, their own or of their chats.
+func (db DB) GetRotationEntries() ([]entity.RotationEntry, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetRotationEntries)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := prep.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []entity.RotationEntry
+	for rows.Next() {
+		var e entity.RotationEntry
+		var updatedAt, periodDays, chatPeriodDays, remindAt int64
+		if err := rows.Scan(&e.Owner, &e.Name, &updatedAt, &periodDays, &chatPeriodDays, &remindAt); err != nil {
+			return nil, err
+		}
+		e.UpdatedAt = unixTime(updatedAt)
+		e.Period = duration(periodDays)
+		e.Chat = entity.Rotation{Period: duration(chatPeriodDays), RemindAt: unixTime(remindAt)}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// GetExpiringEntries gets services expiring before t.
+func (db DB) GetExpiringEntries(t time.Time) ([]entity.ExpiringEntry, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetExpiringEntries)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := prep.Query(unix(t))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []entity.ExpiringEntry
+	for rows.Next() {
+		var e entity.ExpiringEntry
+		var expiresAt int64
+		if err := rows.Scan(&e.Owner, &e.Service, &e.Name, &expiresAt, &e.ExpiryWarned); err != nil {
+			return nil, err
+		}
+		e.ExpiresAt = unixTime(expiresAt)
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// SetExpiryWarned marks the owner warned about the expiry of service.
+func (db DB) SetExpiryWarned(chatID int64, serviceName string) error {
+	prep, err := queries.GetPreparedStatement(queries.SetExpiryWarned)
+	if err != nil {
+		return err
+	}
+
+	_, err = prep.Exec(chatID, serviceName)
+	return err
+}
+
+// DeleteExpired deletes service if it has expired by t,
+// so the entry saved again in the meantime is kept.
+func (db DB) DeleteExpired(chatID int64, serviceName string, t time.Time) error {
+	prep, err := queries.GetPreparedStatement(queries.DeleteExpiredService)
+	if err != nil {
+		return err
+	}
+
+	r, err := prep.Exec(chatID, serviceName, unix(t))
+	if err != nil {
+		return err
+	}
+
+	n, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return service.ErrNotFound
+	}
+	return nil
+}
+
+// AddAuditRecord adds audit log record and sets the head anchor to it in one transaction.
+func (db DB) AddAuditRecord(r entity.AuditRecord, headSeal string) error {
+	add, err := queries.GetPreparedStatement(queries.AddAuditRecord)
+	if err != nil {
+		return err
+	}
+	head, err := queries.GetPreparedStatement(queries.SetAuditHead)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Stmt(add).Exec(r.ChatID, r.Action, r.Service, r.Outcome, unix(r.CreatedAt), r.PrevHash, r.ChatPrevHash, r.Hash)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Stmt(head).Exec(entity.AnchorHead, headSeal, r.PrevHash); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetLastAuditHash gets hash of the last audit log record.
+func (db DB) GetLastAuditHash() (string, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetLastAuditHash)
+	if err != nil {
+		return "", err
+	}
+
+	var hash string
+	err = prep.QueryRow().Scan(&hash)
+	return hash, err
+}
+
+// GetLastChatAuditHash gets hash of the last audit log record of user.
+func (db DB) GetLastChatAuditHash(chatID int64) (string, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetLastChatAuditHash)
+	if err != nil {
+		return "", err
+	}
+
+	var hash string
+	err = prep.QueryRow(chatID).Scan(&hash)
+	return hash, err
+}
+
+// GetAuditChain gets up to limit audit log records with id greater than afterID, oldest first.
+func (db DB) GetAuditChain(afterID int64, limit int) ([]entity.AuditRecord, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetAuditChain)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := prep.Query(afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []entity.AuditRecord
+	for rows.Next() {
+		var r entity.AuditRecord
+		var createdAt int64
+		err := rows.Scan(&r.ID, &r.ChatID, &r.Action, &r.Service, &r.Outcome, &createdAt, &r.PrevHash, &r.ChatPrevHash, &r.Hash)
+		if err != nil {
+			return nil, err
+		}
+		r.CreatedAt = unixTime(createdAt)
+		records = append(records, r)
+	}
+
+	return records, rows.Err()
+}
+
+// GetAuditRecords gets the latest audit log records of chat, newest first.
+func (db DB) GetAuditRecords(chatID int64, limit int) ([]entity.AuditRecord, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetAuditRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := prep.Query(chatID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []entity.AuditRecord
+	for rows.Next() {
+		var r entity.AuditRecord
+		var createdAt int64
+		if err := rows.Scan(&r.ChatID, &r.Action, &r.Service, &r.Outcome, &createdAt); err != nil {
+			return nil, err
+		}
+		r.CreatedAt = unixTime(createdAt)
+		records = append(records, r)
+	}
+
+	return records, rows.Err()
+}
+
+// DeleteAuditRecords deletes audit log records up to the record of the checkpoint, with the links
+// of the records removed by the deleted forget records, and saves the checkpoint anchor in one transaction.
+func (db DB) DeleteAuditRecords(checkpoint entity.AuditAnchor) (int64, error) {
+	del, err := queries.GetPreparedStatement(queries.DeleteAuditRecords)
+	if err != nil {
+		return 0, err
+	}
+	links, err := queries.GetPreparedStatement(queries.DeleteAuditLinks)
+	if err != nil {
+		return 0, err
+	}
+	save, err := queries.GetPreparedStatement(queries.SetAuditCheckpoint)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	r, err := tx.Stmt(del).Exec(checkpoint.RecordID)
+	if err != nil {
+		return 0, err
+	}
+	n, err := r.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Stmt(links).Exec(); err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Stmt(save).Exec(entity.AnchorCheckpoint, checkpoint.RecordID, checkpoint.Hash, checkpoint.Seal)
+	if err != nil {
+		return 0, err
+	}
+	return n, tx.Commit()
+}
+
+// GetAuditCutoff gets id and hash of the last audit log record older than t.
+func (db DB) GetAuditCutoff(t time.Time) (entity.AuditRecord, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetAuditCutoff)
+	if err != nil {
+		return entity.AuditRecord{}, err
+	}
+
+	var r entity.AuditRecord
+	err = prep.QueryRow(unix(t)).Scan(&r.ID, &r.Hash)
+	return r, err
+}
+
+// GetAuditLinks gets links of the removed audit log records.
+func (db DB) GetAuditLinks() ([]entity.AuditLink, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetAuditLinks)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := prep.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []entity.AuditLink
+	for rows.Next() {
+		var l entity.AuditLink
+		if err := rows.Scan(&l.Hash, &l.PrevHash, &l.ForgetHash, &l.Seal); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
+// GetAuditAnchor gets anchor of the audit log chain of the kind.
+func (db DB) GetAuditAnchor(kind string) (entity.AuditAnchor, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetAuditAnchor)
+	if err != nil {
+		return entity.AuditAnchor{}, err
+	}
+
+	var a entity.AuditAnchor
+	err = prep.QueryRow(kind).Scan(&a.RecordID, &a.Hash, &a.Seal)
+	return a, err
+}
+
+// day rotation periods are stored in days.
+const day = 24 * time.Hour
+
+// days returns the period in whole days, rounded down.
+func days(d time.Duration) int64 {
+	return int64(d / day)
+}
+
+// duration is the reverse of days.
+func duration(days int64) time.Duration {
+	return time.Duration(days) * day
+}
+
+// unix returns unix seconds of t, zero time is stored as 0.
+func unix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// unixTime is the reverse of unix.
+func unixTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// GetDuressPIN gets duress PIN hash of chat.
+func (db DB) GetDuressPIN(chatID int64) (string, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetDuressPIN)
+	if err != nil {
+		return "", err
+	}
+
+	var pin string
+	err = prep.QueryRow(chatID).Scan(&pin)
+	return pin, err
+}
+
+// SetDuressPIN adds or updates duress PIN hash of chat.
+func (db DB) SetDuressPIN(chatID int64, pin string) error {
+	prep, err := queries.GetPreparedStatement(queries.SetDuressPIN)
+	if err != nil {
+		return err
+	}
+
+	_, err = prep.Exec(chatID, pin, pin)
+	return err
+}
+
+// DeleteDuressPIN deletes duress PIN hash of chat.
+func (db DB) DeleteDuressPIN(chatID int64) error {
+	prep, err := queries.GetPreparedStatement(queries.DeleteDuressPIN)
+	if err != nil {
+		return err
+	}
+
+	_, err = prep.Exec(chatID)
+	return err
+}
+
+// Wipe deletes services, files, settings, shares, one-time secrets and the audit log records of chat
+// in one transaction, the forget record sealed by forget takes the place of the audit log records.
+func (db DB) Wipe(chatID int64, forget entity.AuditForget) error {
+	return db.deleteAll(wipeQueries(chatID), chatID, forget)
+}
+
+// DeleteUser deletes everything of Wipe, the lockout, the membership, the username,
+// the vault memberships and the audit log records of user in one transaction,
+// the forget record sealed by forget takes the place of the audit log records.
+func (db DB) DeleteUser(chatID int64, forget entity.AuditForget) error {
+	return db.deleteAll(append(wipeQueries(chatID),
+		deleteQuery{queries.DeleteUserLockout, []interface{}{chatID}},
+		deleteQuery{queries.DeleteUserMember, []interface{}{chatID}},
+		deleteQuery{queries.DeleteUserName, []interface{}{chatID}},
+		deleteQuery{queries.DeleteUserVaults, []interface{}{chatID}},
+	), chatID, forget)
+}
+
+// GetUserData gets counts and times of everything stored about chat.
+func (db DB) GetUserData(chatID int64) (entity.UserData, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetUserData)
+	if err != nil {
+		return entity.UserData{}, err
+	}
+
+	args := make([]interface{}, 11)
+	for i := range args {
+		args[i] = chatID
+	}
+
+	var d entity.UserData
+	var firstSaved, lastUpdated, auditSince int64
+	var duress int
+	err = prep.QueryRow(args...).Scan(&d.Services, &firstSaved, &lastUpdated, &d.SharesGiven, &d.SharesReceived,
+		&d.OneTime, &d.AuditRecords, &auditSince, &d.Vaults, &duress, &d.Files)
+	if err != nil {
+		return entity.UserData{}, err
+	}
+	d.FirstSaved = unixTime(firstSaved)
+	d.LastUpdated = unixTime(lastUpdated)
+	d.AuditSince = unixTime(auditSince)
+	d.DuressPIN = duress > 0
+	return d, nil
+}
+
+// deleteQuery query deleting rows, or updating the ones left, with its arguments.
+type deleteQuery struct {
+	name int
+	args []interface{}
+}
+
+// wipeQueries returns the queries deleting services, files, settings, shares and one-time secrets of chat.
+func wipeQueries(chatID int64) []deleteQuery {
+	return []deleteQuery{
+		{queries.WipeServices, []interface{}{chatID}},
+		{queries.WipeChat, []interface{}{chatID}},
+		{queries.WipeRotation, []interface{}{chatID}},
+		{queries.WipeShares, []interface{}{chatID, chatID}},
+		{queries.WipeShareAccesses, []interface{}{chatID, chatID}},
+		{queries.WipeOneTime, []interface{}{chatID}},
+		{queries.DeleteDuressPIN, []interface{}{chatID}},
+		{queries.WipeFiles, []interface{}{chatID}},
+		{queries.DeleteDisplayMode, []interface{}{chatID}},
+	}
+}
+
+// deleteAll runs the queries in one transaction
+// and deletes the audit log records of chat after them, see forgetAudit.
+func (db DB) deleteAll(qs []deleteQuery, chatID int64, forget entity.AuditForget) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, q := range qs {
+		prep, err := queries.GetPreparedStatement(q.name)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Stmt(prep).Exec(q.args...); err != nil {
+			return err
+		}
+	}
+
+	if err := forgetAudit(tx, chatID, forget); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// forgetAudit deletes the audit log records of chat and appends the forget record sealed by forget
+// in their place, with the links of the deleted records. The transaction holds the write lock
+// from its beginning on SQLite, see storage.New, and the last record is locked on Postgres.
+func forgetAudit(tx *sql.Tx, chatID int64, forget entity.AuditForget) error {
+	stmts := make(map[int]*sql.Stmt)
+	for _, name := range []int{queries.LockAuditHead, queries.GetLastChatAuditHash, queries.DeleteUserAudit,
+		queries.AddAuditLink, queries.AddAuditRecord, queries.SetAuditHead} {
+		prep, err := queries.GetPreparedStatement(name)
+		if err != nil {
+			return err
+		}
+		stmts[name] = tx.Stmt(prep)
+	}
+
+	var last, chatLast string
+	if err := stmts[queries.LockAuditHead].QueryRow().Scan(&last); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if err := stmts[queries.GetLastChatAuditHash].QueryRow(int64(0)).Scan(&chatLast); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	removed, err := userAuditLinks(tx, chatID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := stmts[queries.DeleteUserAudit].Exec(chatID); err != nil {
+		return err
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	// the forget record continues the last record even if it was removed,
+	// so a record appended concurrently after it is a fork the storage doesn't allow
+	r, links, headSeal := forget(last, chatLast, removed)
+	for _, l := range links {
+		if _, err := stmts[queries.AddAuditLink].Exec(l.Hash, l.PrevHash, l.ForgetHash, l.Seal); err != nil {
+			return err
+		}
+	}
+	_, err = stmts[queries.AddAuditRecord].Exec(r.ChatID, r.Action, r.Service, r.Outcome, unix(r.CreatedAt), r.PrevHash, r.ChatPrevHash, r.Hash)
+	if err != nil {
+		return err
+	}
+	_, err = stmts[queries.SetAuditHead].Exec(entity.AnchorHead, headSeal, r.PrevHash)
+	return err
+}
+
+// userAuditLinks gets the links of the sealed audit log records of chat, oldest first.
+func userAuditLinks(tx *sql.Tx, chatID int64) ([]entity.AuditLink, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetUserAuditLinks)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Stmt(prep).Query(chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []entity.AuditLink
+	for rows.Next() {
+		var l entity.AuditLink
+		if err := rows.Scan(&l.Hash, &l.PrevHash); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
+// SaveFile adds or updates file of service.
+func (db DB) SaveFile(chatID int64, f entity.File) error {
+	prep, err := queries.GetPreparedStatement(queries.SaveFile)
+	if err != nil {
+		return err
+	}
+
+	createdAt := unix(f.CreatedAt)
+	_, err = prep.Exec(chatID, f.Service, f.Name, f.FileName, f.Photo, f.Data, createdAt,
+		f.Name, f.FileName, f.Photo, f.Data, createdAt)
+	return err
+}
+
+// GetFile gets file of service.
+func (db DB) GetFile(chatID int64, name string) (entity.File, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetFile)
+	if err != nil {
+		return entity.File{}, err
+	}
+
+	f := entity.File{Service: name}
+	var createdAt int64
+	err = prep.QueryRow(chatID, name).Scan(&f.Name, &f.FileName, &f.Photo, &f.Data, &createdAt)
+	if err != nil {
+		return entity.File{}, err
+	}
+	f.CreatedAt = unixTime(createdAt)
+	return f, nil
+}
+
+// GetFiles gets files of chat without the data.
+func (db DB) GetFiles(chatID int64) ([]entity.File, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := prep.Query(chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []entity.File
+	for rows.Next() {
+		var f entity.File
+		var createdAt int64
+		if err := rows.Scan(&f.Service, &f.Name, &f.FileName, &f.Photo, &createdAt); err != nil {
+			return nil, err
+		}
+		f.CreatedAt = unixTime(createdAt)
+		files = append(files, f)
+	}
+
+	return files, rows.Err()
+}
+
+// DeleteFile deletes file of service.
+func (db DB) DeleteFile(chatID int64, name string) error {
+	prep, err := queries.GetPreparedStatement(queries.DeleteFile)
+	if err != nil {
+		return err
+	}
+
+	r, err := prep.Exec(chatID, name)
+	if err != nil {
+		return err
+	}
+
+	n, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return service.ErrNotFound
+	}
+	return nil
+}
+
+// GetDisplayMode gets the way chat is shown its entries.
+func (db DB) GetDisplayMode(chatID int64) (string, error) {
+	prep, err := queries.GetPreparedStatement(queries.GetDisplayMode)
+	if err != nil {
+		return "", err
+	}
+
+	var mode string
+	err = prep.QueryRow(chatID).Scan(&mode)
+	return mode, err
+}
+
+// SetDisplayMode adds or updates the way chat is shown its entries.
+func (db DB) SetDisplayMode(chatID int64, mode string) error {
+	prep, err := queries.GetPreparedStatement(queries.SetDisplayMode)
+	if err != nil {
+		return err
+	}
+
+	_, err = prep.Exec(chatID, mode, mode)
+	return err
+}
+
+// DeleteDisplayMode deletes the way chat is shown its entries.
+func (db DB) DeleteDisplayMode(chatID int64) error {
+	prep, err := queries.GetPreparedStatement(queries.DeleteDisplayMode)
+	if err != nil {
+		return err
+	}
+
+	_, err = prep.Exec(chatID)
 	return err
 }
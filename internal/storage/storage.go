@@ -10,16 +10,74 @@ import (
 	"password-keeper/internal/storage/queries"
 	"password-keeper/internal/storage/service"
 	"password-keeper/internal/storage/sqlite"
+	"strings"
 	"sync"
+	"time"
 )
 
 // RealStorage is an interface that allows to use different storages.
 type RealStorage interface {
 	Save(chatID int64, service string, pair entity.Pair) error
 	Get(chatID int64, service string) (entity.Pair, error)
+	GetAll(chatID int64) ([]entity.Pair, error)
 	Delete(chatID int64, service string) error
-	GetLang(chatID int64) (string, error)
-	SetLang(chatID int64, lang string) error
+	GetLang(chatID int64) (entity.Language, error)
+	SetLang(chatID int64, lang entity.Language) error
+	GetLockout(chatID int64) (entity.Lockout, error)
+	SetLockout(chatID int64, lockout entity.Lockout) error
+	GetMember(userID int64) (entity.Member, error)
+	SaveMember(member entity.Member) error
+	GetMembers() ([]entity.Member, error)
+	GetStats() (entity.Stats, error)
+	CreateVault(vault entity.Vault) error
+	GetVault(vaultID int64) (entity.Vault, error)
+	GetVaultByName(name string) (entity.Vault, error)
+	GetUserVaults(userID int64) ([]entity.Vault, error)
+	SaveVaultMember(member entity.VaultMember) error
+	GetVaultMember(vaultID, userID int64) (entity.VaultMember, error)
+	GetVaultMembers(vaultID int64) ([]entity.VaultMember, error)
+	DeleteVaultMember(vaultID, userID int64) error
+	SaveUser(user entity.User) error
+	GetUserByName(username string) (entity.User, error)
+	SaveShare(share entity.Share) error
+	GetShare(recipient int64, service string) (entity.Share, error)
+	GetShares(owner int64) ([]entity.Share, error)
+	DeleteShare(owner int64, service string, recipient int64) error
+	DeleteShares(owner int64, service string) error
+	AddShareAccess(share entity.Share, at time.Time) error
+	SaveOneTime(secret entity.OneTime) error
+	TakeOneTime(id string) (entity.OneTime, error)
+	DeleteExpiredOneTime(t time.Time) (int64, error)
+	SetServiceRotation(chatID int64, service string, period time.Duration) error
+	GetRotation(chatID int64) (entity.Rotation, error)
+	SetRotation(chatID int64, rotation entity.Rotation) error
+	GetRotationEntries() ([]entity.RotationEntry, error)
+	AddAuditRecord(record entity.AuditRecord, headSeal string) error
+	GetAuditRecords(chatID int64, limit int) ([]entity.AuditRecord, error)
+	DeleteAuditRecords(checkpoint entity.AuditAnchor) (int64, error)
+	GetLastAuditHash() (string, error)
+	GetLastChatAuditHash(chatID int64) (string, error)
+	GetAuditChain(afterID int64, limit int) ([]entity.AuditRecord, error)
+	GetAuditCutoff(t time.Time) (entity.AuditRecord, error)
+	GetAuditAnchor(kind string) (entity.AuditAnchor, error)
+	GetAuditLinks() ([]entity.AuditLink, error)
+	GetDuressPIN(chatID int64) (string, error)
+	SetDuressPIN(chatID int64, pin string) error
+	DeleteDuressPIN(chatID int64) error
+	Wipe(chatID int64, forget entity.AuditForget) error
+	DeleteUser(chatID int64, forget entity.AuditForget) error
+	GetUserData(chatID int64) (entity.UserData, error)
+	SaveFile(chatID int64, f entity.File) error
+	GetFile(chatID int64, serviceName string) (entity.File, error)
+	GetFiles(chatID int64) ([]entity.File, error)
+	DeleteFile(chatID int64, serviceName string) error
+	GetExpiringEntries(t time.Time) ([]entity.ExpiringEntry, error)
+	SetExpiryWarned(chatID int64, serviceName string) error
+	DeleteExpired(chatID int64, serviceName string, t time.Time) error
+	GetDisplayMode(chatID int64) (string, error)
+	SetDisplayMode(chatID int64, mode string) error
+	DeleteDisplayMode(chatID int64) error
+	Close() error
 }
 
 // Storage is a struct that contains all methods for working with user services
@@ -27,6 +85,11 @@ type Storage struct {
 	ramStorage  *sync.Map
 	realStorage RealStorage
 	langStorage *sync.Map
+	lockStorage *sync.Map
+	// memberStorage caches members, they are checked on every update.
+	memberStorage *sync.Map
+	// userStorage caches usernames, they are saved on every update.
+	userStorage *sync.Map
 }
 
 // ErrNotFound is returned when user service is not found.
@@ -53,7 +116,7 @@ func New(storageType, dsn string) (*Storage, error) {
 			return nil, fmt.Errorf("prepare db: %w", err)
 		}
 	case "sqlite", "test":
-		db, err := sql.Open("sqlite", dsn)
+		db, err := sql.Open("sqlite", immediateTx(dsn))
 		if err != nil {
 			return nil, fmt.Errorf("open db: %w", err)
 		}
@@ -78,12 +141,27 @@ func New(storageType, dsn string) (*Storage, error) {
 		return nil, fmt.Errorf("unknown storage type: %s", storageType)
 	}
 	return &Storage{
-		ramStorage:  &sync.Map{},
-		langStorage: &sync.Map{},
-		realStorage: rs,
+		ramStorage:    &sync.Map{},
+		langStorage:   &sync.Map{},
+		lockStorage:   &sync.Map{},
+		memberStorage: &sync.Map{},
+		userStorage:   &sync.Map{},
+		realStorage:   rs,
 	}, nil
 }
 
+// immediateTx makes the SQLite transactions take the write lock when they begin,
+// so the audit log head read in Wipe and DeleteUser isn't appended to before they commit.
+func immediateTx(dsn string) string {
+	if strings.Contains(dsn, "_txlock=") {
+		return dsn
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&_txlock=immediate"
+	}
+	return dsn + "?_txlock=immediate"
+}
+
 // Save saves user service
 func (s *Storage) Save(chatID int64, service string, pair entity.Pair) error {
 	us, err := s.getUserStorage(chatID)
@@ -145,6 +223,15 @@ func (s *Storage) Get(chatID int64, service string) (entity.Pair, error) {
 	return pair, nil
 }
 
+// GetAll gets all user services, they are always read from the database.
+func (s *Storage) GetAll(chatID int64) ([]entity.Pair, error) {
+	pairs, err := s.realStorage.GetAll(chatID)
+	if err != nil {
+		return nil, fmt.Errorf("get all: %w", err)
+	}
+	return pairs, nil
+}
+
 // Delete deletes user service
 func (s *Storage) Delete(chatID int64, serviceName string) error {
 	us, err := s.getUserStorage(chatID)
@@ -163,28 +250,67 @@ func (s *Storage) Delete(chatID int64, serviceName string) error {
 	return nil
 }
 
+// GetExpiringEntries gets the services of all users expiring before t.
+func (s *Storage) GetExpiringEntries(t time.Time) ([]entity.ExpiringEntry, error) {
+	entries, err := s.realStorage.GetExpiringEntries(t)
+	if err != nil {
+		return nil, fmt.Errorf("get expiring entries: %w", err)
+	}
+	return entries, nil
+}
+
+// SetExpiryWarned marks the user warned about the expiry of the service.
+// The cached entry is dropped rather than updated, so a concurrent Save is never overwritten.
+func (s *Storage) SetExpiryWarned(chatID int64, serviceName string) error {
+	if err := s.realStorage.SetExpiryWarned(chatID, serviceName); err != nil {
+		return fmt.Errorf("set expiry warned: %w", err)
+	}
+
+	if us, err := s.getUserStorage(chatID); err == nil {
+		us.Delete(serviceName)
+	}
+	return nil
+}
+
+// DeleteExpired deletes the service if it has expired by t.
+// The database goes first: when the entry was saved again in the meantime,
+// nothing is deleted and the cache keeps the new entry.
+func (s *Storage) DeleteExpired(chatID int64, serviceName string, t time.Time) error {
+	if err := s.realStorage.DeleteExpired(chatID, serviceName, t); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("delete expired: %w", err)
+	}
+
+	if us, err := s.getUserStorage(chatID); err == nil {
+		us.Delete(serviceName)
+	}
+	return nil
+}
+
 // GetLang gets user language
-func (s *Storage) GetLang(chatID int64) (string, error) {
-	lang, loaded := s.langStorage.LoadOrStore(chatID, "en")
-	if !loaded {
+func (s *Storage) GetLang(chatID int64) (entity.Language, error) {
+	lang, ok := s.langStorage.Load(chatID)
+	if !ok {
 		lang, err := s.realStorage.GetLang(chatID)
 		if err != nil {
-			return "", fmt.Errorf("get lang: %w", err)
+			return entity.Language{}, fmt.Errorf("get lang: %w", err)
 		}
 		s.langStorage.Store(chatID, lang)
 		return lang, nil
 	}
 
-	l, ok := lang.(string)
+	l, ok := lang.(entity.Language)
 	if !ok {
-		return "", ErrNotFound
+		return entity.Language{}, ErrNotFound
 	}
 
 	return l, nil
 }
 
 // SetLang sets user language
-func (s *Storage) SetLang(chatID int64, lang string) error {
+func (s *Storage) SetLang(chatID int64, lang entity.Language) error {
 	s.langStorage.Store(chatID, lang)
 	err := s.realStorage.SetLang(chatID, lang)
 	if err != nil {
@@ -192,3 +318,544 @@ func (s *Storage) SetLang(chatID int64, lang string) error {
 	}
 	return nil
 }
+
+// GetLockout gets user lockout state.
+// A chat without failed lookups gets the zero state.
+func (s *Storage) GetLockout(chatID int64) (entity.Lockout, error) {
+	if l, ok := s.lockStorage.Load(chatID); ok {
+		if lockout, ok := l.(entity.Lockout); ok {
+			return lockout, nil
+		}
+	}
+
+	l, err := s.realStorage.GetLockout(chatID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return entity.Lockout{}, fmt.Errorf("get lockout: %w", err)
+	}
+
+	s.lockStorage.Store(chatID, l)
+	return l, nil
+}
+
+// SetLockout sets user lockout state.
+func (s *Storage) SetLockout(chatID int64, lockout entity.Lockout) error {
+	s.lockStorage.Store(chatID, lockout)
+	if err := s.realStorage.SetLockout(chatID, lockout); err != nil {
+		return fmt.Errorf("set lockout: %w", err)
+	}
+	return nil
+}
+
+// GetMember gets member by user ID.
+func (s *Storage) GetMember(userID int64) (entity.Member, error) {
+	if m, ok := s.memberStorage.Load(userID); ok {
+		if member, ok := m.(entity.Member); ok {
+			return member, nil
+		}
+	}
+
+	m, err := s.realStorage.GetMember(userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Member{}, ErrNotFound
+		}
+		return entity.Member{}, fmt.Errorf("get member: %w", err)
+	}
+
+	s.memberStorage.Store(userID, m)
+	return m, nil
+}
+
+// SaveMember adds or updates member.
+func (s *Storage) SaveMember(member entity.Member) error {
+	if err := s.realStorage.SaveMember(member); err != nil {
+		return fmt.Errorf("save member: %w", err)
+	}
+
+	s.memberStorage.Store(member.UserID, member)
+	return nil
+}
+
+// GetMembers gets all members.
+func (s *Storage) GetMembers() ([]entity.Member, error) {
+	members, err := s.realStorage.GetMembers()
+	if err != nil {
+		return nil, fmt.Errorf("get members: %w", err)
+	}
+	return members, nil
+}
+
+// GetStats gets usage statistics.
+func (s *Storage) GetStats() (entity.Stats, error) {
+	stats, err := s.realStorage.GetStats()
+	if err != nil {
+		return entity.Stats{}, fmt.Errorf("get stats: %w", err)
+	}
+	return stats, nil
+}
+
+// CreateVault adds vault.
+func (s *Storage) CreateVault(vault entity.Vault) error {
+	if err := s.realStorage.CreateVault(vault); err != nil {
+		return fmt.Errorf("create vault: %w", err)
+	}
+	return nil
+}
+
+// GetVault gets vault by ID.
+func (s *Storage) GetVault(vaultID int64) (entity.Vault, error) {
+	v, err := s.realStorage.GetVault(vaultID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Vault{}, ErrNotFound
+		}
+		return entity.Vault{}, fmt.Errorf("get vault: %w", err)
+	}
+	return v, nil
+}
+
+// GetVaultByName gets vault by name.
+func (s *Storage) GetVaultByName(name string) (entity.Vault, error) {
+	v, err := s.realStorage.GetVaultByName(name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Vault{}, ErrNotFound
+		}
+		return entity.Vault{}, fmt.Errorf("get vault by name: %w", err)
+	}
+	return v, nil
+}
+
+// GetUserVaults gets vaults the user is a member of.
+func (s *Storage) GetUserVaults(userID int64) ([]entity.Vault, error) {
+	vaults, err := s.realStorage.GetUserVaults(userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user vaults: %w", err)
+	}
+	return vaults, nil
+}
+
+// SaveVaultMember adds or updates vault member.
+func (s *Storage) SaveVaultMember(member entity.VaultMember) error {
+	if err := s.realStorage.SaveVaultMember(member); err != nil {
+		return fmt.Errorf("save vault member: %w", err)
+	}
+	return nil
+}
+
+// GetVaultMember gets vault member.
+func (s *Storage) GetVaultMember(vaultID, userID int64) (entity.VaultMember, error) {
+	m, err := s.realStorage.GetVaultMember(vaultID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.VaultMember{}, ErrNotFound
+		}
+		return entity.VaultMember{}, fmt.Errorf("get vault member: %w", err)
+	}
+	return m, nil
+}
+
+// GetVaultMembers gets all vault members.
+func (s *Storage) GetVaultMembers(vaultID int64) ([]entity.VaultMember, error) {
+	members, err := s.realStorage.GetVaultMembers(vaultID)
+	if err != nil {
+		return nil, fmt.Errorf("get vault members: %w", err)
+	}
+	return members, nil
+}
+
+// DeleteVaultMember deletes vault member.
+func (s *Storage) DeleteVaultMember(vaultID, userID int64) error {
+	err := s.realStorage.DeleteVaultMember(vaultID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("delete vault member: %w", err)
+	}
+	return nil
+}
+
+// SaveUser adds or updates user, it's written to the database only when the username changes.
+func (s *Storage) SaveUser(user entity.User) error {
+	if username, ok := s.userStorage.Load(user.ID); ok && username == user.Username {
+		return nil
+	}
+
+	if err := s.realStorage.SaveUser(user); err != nil {
+		return fmt.Errorf("save user: %w", err)
+	}
+
+	s.userStorage.Store(user.ID, user.Username)
+	return nil
+}
+
+// GetUserByName gets user by username.
+func (s *Storage) GetUserByName(username string) (entity.User, error) {
+	u, err := s.realStorage.GetUserByName(username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.User{}, ErrNotFound
+		}
+		return entity.User{}, fmt.Errorf("get user by name: %w", err)
+	}
+	return u, nil
+}
+
+// SaveShare adds or updates share.
+func (s *Storage) SaveShare(share entity.Share) error {
+	if err := s.realStorage.SaveShare(share); err != nil {
+		return fmt.Errorf("save share: %w", err)
+	}
+	return nil
+}
+
+// GetShare gets the share of the service with the recipient.
+func (s *Storage) GetShare(recipient int64, service string) (entity.Share, error) {
+	sh, err := s.realStorage.GetShare(recipient, service)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Share{}, ErrNotFound
+		}
+		return entity.Share{}, fmt.Errorf("get share: %w", err)
+	}
+	return sh, nil
+}
+
+// GetShares gets all shares of the owner.
+func (s *Storage) GetShares(owner int64) ([]entity.Share, error) {
+	shares, err := s.realStorage.GetShares(owner)
+	if err != nil {
+		return nil, fmt.Errorf("get shares: %w", err)
+	}
+	return shares, nil
+}
+
+// DeleteShare deletes share.
+func (s *Storage) DeleteShare(owner int64, serviceName string, recipient int64) error {
+	err := s.realStorage.DeleteShare(owner, serviceName, recipient)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("delete share: %w", err)
+	}
+	return nil
+}
+
+// DeleteShares deletes all shares of the service.
+func (s *Storage) DeleteShares(owner int64, service string) error {
+	if err := s.realStorage.DeleteShares(owner, service); err != nil {
+		return fmt.Errorf("delete shares: %w", err)
+	}
+	return nil
+}
+
+// AddShareAccess records that the recipient has read the shared entry.
+func (s *Storage) AddShareAccess(share entity.Share, at time.Time) error {
+	if err := s.realStorage.AddShareAccess(share, at); err != nil {
+		return fmt.Errorf("add share access: %w", err)
+	}
+	return nil
+}
+
+// SaveOneTime adds one-time secret.
+func (s *Storage) SaveOneTime(secret entity.OneTime) error {
+	if err := s.realStorage.SaveOneTime(secret); err != nil {
+		return fmt.Errorf("save one-time: %w", err)
+	}
+	return nil
+}
+
+// TakeOneTime gets and deletes one-time secret.
+func (s *Storage) TakeOneTime(id string) (entity.OneTime, error) {
+	ot, err := s.realStorage.TakeOneTime(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.OneTime{}, ErrNotFound
+		}
+		return entity.OneTime{}, fmt.Errorf("take one-time: %w", err)
+	}
+	return ot, nil
+}
+
+// DeleteExpiredOneTime deletes one-time secrets expired before t.
+func (s *Storage) DeleteExpiredOneTime(t time.Time) (int64, error) {
+	n, err := s.realStorage.DeleteExpiredOneTime(t)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired one-time: %w", err)
+	}
+	return n, nil
+}
+
+// SetServiceRotation sets rotation period of user service.
+func (s *Storage) SetServiceRotation(chatID int64, serviceName string, period time.Duration) error {
+	err := s.realStorage.SetServiceRotation(chatID, serviceName, period)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("set service rotation: %w", err)
+	}
+	return nil
+}
+
+// GetRotation gets rotation settings of user.
+func (s *Storage) GetRotation(chatID int64) (entity.Rotation, error) {
+	r, err := s.realStorage.GetRotation(chatID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Rotation{}, ErrNotFound
+		}
+		return entity.Rotation{}, fmt.Errorf("get rotation: %w", err)
+	}
+	return r, nil
+}
+
+// SetRotation sets rotation settings of user.
+func (s *Storage) SetRotation(chatID int64, rotation entity.Rotation) error {
+	if err := s.realStorage.SetRotation(chatID, rotation); err != nil {
+		return fmt.Errorf("set rotation: %w", err)
+	}
+	return nil
+}
+
+// GetRotationEntries gets all services with rotation period.
+func (s *Storage) GetRotationEntries() ([]entity.RotationEntry, error) {
+	entries, err := s.realStorage.GetRotationEntries()
+	if err != nil {
+		return nil, fmt.Errorf("get rotation entries: %w", err)
+	}
+	return entries, nil
+}
+
+// AddAuditRecord adds audit log record and makes it the head of the chain sealed with headSeal.
+func (s *Storage) AddAuditRecord(record entity.AuditRecord, headSeal string) error {
+	if err := s.realStorage.AddAuditRecord(record, headSeal); err != nil {
+		return fmt.Errorf("add audit record: %w", err)
+	}
+	return nil
+}
+
+// GetAuditRecords gets the latest audit log records of user, newest first.
+func (s *Storage) GetAuditRecords(chatID int64, limit int) ([]entity.AuditRecord, error) {
+	records, err := s.realStorage.GetAuditRecords(chatID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get audit records: %w", err)
+	}
+	return records, nil
+}
+
+// DeleteAuditRecords deletes audit log records up to the record of the checkpoint, with the links
+// of the records removed by the deleted forget records, and saves the checkpoint, unless the saved one is further.
+func (s *Storage) DeleteAuditRecords(checkpoint entity.AuditAnchor) (int64, error) {
+	n, err := s.realStorage.DeleteAuditRecords(checkpoint)
+	if err != nil {
+		return 0, fmt.Errorf("delete audit records: %w", err)
+	}
+	return n, nil
+}
+
+// GetAuditCutoff gets id and hash of the last audit log record older than t.
+func (s *Storage) GetAuditCutoff(t time.Time) (entity.AuditRecord, error) {
+	r, err := s.realStorage.GetAuditCutoff(t)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.AuditRecord{}, ErrNotFound
+		}
+		return entity.AuditRecord{}, fmt.Errorf("get audit cutoff: %w", err)
+	}
+	return r, nil
+}
+
+// GetAuditLinks gets links of the removed audit log records.
+func (s *Storage) GetAuditLinks() ([]entity.AuditLink, error) {
+	links, err := s.realStorage.GetAuditLinks()
+	if err != nil {
+		return nil, fmt.Errorf("get audit links: %w", err)
+	}
+	return links, nil
+}
+
+// GetAuditAnchor gets anchor of the audit log chain of the kind.
+func (s *Storage) GetAuditAnchor(kind string) (entity.AuditAnchor, error) {
+	a, err := s.realStorage.GetAuditAnchor(kind)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.AuditAnchor{}, ErrNotFound
+		}
+		return entity.AuditAnchor{}, fmt.Errorf("get audit anchor: %w", err)
+	}
+	return a, nil
+}
+
+// GetAuditHead gets hashes of the last audit log record and of the last record of user,
+// they are empty when there are no such records.
+func (s *Storage) GetAuditHead(chatID int64) (last, chatLast string, err error) {
+	last, err = s.realStorage.GetLastAuditHash()
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", "", fmt.Errorf("get last audit hash: %w", err)
+	}
+
+	chatLast, err = s.realStorage.GetLastChatAuditHash(chatID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", "", fmt.Errorf("get last chat audit hash: %w", err)
+	}
+	return last, chatLast, nil
+}
+
+// GetAuditChain gets up to limit audit log records with id greater than afterID, oldest first.
+func (s *Storage) GetAuditChain(afterID int64, limit int) ([]entity.AuditRecord, error) {
+	records, err := s.realStorage.GetAuditChain(afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get audit chain: %w", err)
+	}
+	return records, nil
+}
+
+// GetDuressPIN gets duress PIN hash of user.
+func (s *Storage) GetDuressPIN(chatID int64) (string, error) {
+	pin, err := s.realStorage.GetDuressPIN(chatID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("get duress pin: %w", err)
+	}
+	return pin, nil
+}
+
+// SetDuressPIN adds or updates duress PIN hash of user.
+func (s *Storage) SetDuressPIN(chatID int64, pin string) error {
+	if err := s.realStorage.SetDuressPIN(chatID, pin); err != nil {
+		return fmt.Errorf("set duress pin: %w", err)
+	}
+	return nil
+}
+
+// DeleteDuressPIN deletes duress PIN hash of user.
+func (s *Storage) DeleteDuressPIN(chatID int64) error {
+	if err := s.realStorage.DeleteDuressPIN(chatID); err != nil {
+		return fmt.Errorf("delete duress pin: %w", err)
+	}
+	return nil
+}
+
+// GetDisplayMode gets the way user is shown the entries.
+func (s *Storage) GetDisplayMode(chatID int64) (string, error) {
+	mode, err := s.realStorage.GetDisplayMode(chatID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("get display mode: %w", err)
+	}
+	return mode, nil
+}
+
+// SetDisplayMode adds or updates the way user is shown the entries.
+func (s *Storage) SetDisplayMode(chatID int64, mode string) error {
+	if err := s.realStorage.SetDisplayMode(chatID, mode); err != nil {
+		return fmt.Errorf("set display mode: %w", err)
+	}
+	return nil
+}
+
+// DeleteDisplayMode deletes the way user is shown the entries.
+func (s *Storage) DeleteDisplayMode(chatID int64) error {
+	if err := s.realStorage.DeleteDisplayMode(chatID); err != nil {
+		return fmt.Errorf("delete display mode: %w", err)
+	}
+	return nil
+}
+
+// Wipe deletes the services, files, settings, shares, one-time secrets and the audit log records of user.
+// The database is wiped in one transaction first, so the caches are dropped
+// only when nothing is left to be read back into them. The forget record sealed by forget
+// takes the place of the audit log records, see DeleteUser. The lockout must survive the wipe, it is kept.
+func (s *Storage) Wipe(chatID int64, forget entity.AuditForget) error {
+	if err := s.realStorage.Wipe(chatID, forget); err != nil {
+		return fmt.Errorf("wipe: %w", err)
+	}
+
+	s.ramStorage.Delete(chatID)
+	s.langStorage.Delete(chatID)
+	return nil
+}
+
+// DeleteUser deletes the user: everything of Wipe, the lockout, the membership,
+// the username, the vault memberships and the audit log records, the database first and the caches after.
+// The forget record sealed by forget takes the place of the audit log records in the same transaction.
+func (s *Storage) DeleteUser(chatID int64, forget entity.AuditForget) error {
+	if err := s.realStorage.DeleteUser(chatID, forget); err != nil {
+		return fmt.Errorf("delete user: %w", err)
+	}
+
+	for _, cache := range []*sync.Map{s.ramStorage, s.langStorage, s.lockStorage, s.memberStorage, s.userStorage} {
+		cache.Delete(chatID)
+	}
+	return nil
+}
+
+// GetUserData gets counts and times of everything stored about user.
+func (s *Storage) GetUserData(chatID int64) (entity.UserData, error) {
+	d, err := s.realStorage.GetUserData(chatID)
+	if err != nil {
+		return entity.UserData{}, fmt.Errorf("get user data: %w", err)
+	}
+	return d, nil
+}
+
+// SaveFile adds or updates file of service.
+// Files are not cached, they are read rarely and may be large.
+func (s *Storage) SaveFile(chatID int64, f entity.File) error {
+	if err := s.realStorage.SaveFile(chatID, f); err != nil {
+		return fmt.Errorf("save file: %w", err)
+	}
+	return nil
+}
+
+// GetFile gets file of service.
+func (s *Storage) GetFile(chatID int64, serviceName string) (entity.File, error) {
+	f, err := s.realStorage.GetFile(chatID, serviceName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.File{}, ErrNotFound
+		}
+		return entity.File{}, fmt.Errorf("get file: %w", err)
+	}
+	return f, nil
+}
+
+// GetFiles gets files of user without the data.
+func (s *Storage) GetFiles(chatID int64) ([]entity.File, error) {
+	files, err := s.realStorage.GetFiles(chatID)
+	if err != nil {
+		return nil, fmt.Errorf("get files: %w", err)
+	}
+	return files, nil
+}
+
+// DeleteFile deletes file of service.
+func (s *Storage) DeleteFile(chatID int64, serviceName string) error {
+	if err := s.realStorage.DeleteFile(chatID, serviceName); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("delete file: %w", err)
+	}
+	return nil
+}
+
+// Close closes prepared statements and the database.
+func (s *Storage) Close() error {
+	if err := queries.Close(); err != nil {
+		return fmt.Errorf("close queries: %w", err)
+	}
+
+	if err := s.realStorage.Close(); err != nil {
+		return fmt.Errorf("close db: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,429 @@
+// Package strength estimates password strength in the spirit of zxcvbn.
+//
+// The password is split into the patterns attackers try first: common passwords
+// and words (also capitalized, reversed or in l33t), keyboard rows, sequences,
+// repeats and years. Each pattern costs the number of guesses to find it,
+// the other characters cost 10 guesses each, and the cheapest split of the
+// password gives its guesses and score.
+package strength
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Patterns making the password weak, the warning of the result.
+const (
+	Common   = "common"
+	Word     = "word"
+	Keyboard = "keyboard"
+	Sequence = "sequence"
+	Repeat   = "repeat"
+	Year     = "year"
+)
+
+// MaxScore the score of the strongest passwords.
+const MaxScore = 4
+
+// scoreGuesses log10 of the guesses needed for scores 1 to 4, as in zxcvbn.
+var scoreGuesses = [MaxScore]float64{3, 6, 8, 10}
+
+// bruteforceGuesses log10 of the guesses per character out of any pattern.
+const bruteforceGuesses = 1
+
+// Limits of the patterns.
+const (
+	// maxLength the longer passwords are cut to it before the analysis, as in zxcvbn.
+	maxLength      = 100
+	maxWordLen     = 20
+	minKeyboardLen = 4
+	minSequenceLen = 3
+	minYear        = 1900
+	maxYear        = 2039
+	// yearGuesses about the number of years people use.
+	yearGuesses = 50
+)
+
+// keyboardRows rows and columns of the keyboards, they are matched both ways.
+var keyboardRows = []string{
+	"qwertyuiop", "asdfghjkl", "zxcvbnm", "1234567890",
+	"1qaz", "2wsx", "3edc", "4rfv", "5tgb", "6yhn", "7ujm", "1q2w3e4r5t6y", "qazwsxedc",
+	"йцукенгшщзхъ", "фывапролджэ", "ячсмитьбю",
+}
+
+// leet the letters behind l33t substitutions.
+var leet = map[rune]rune{
+	'4': 'a', '@': 'a', '8': 'b', '(': 'c', '3': 'e', '6': 'g', '1': 'i', '!': 'i',
+	'|': 'l', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '2': 'z',
+}
+
+// ranks rank of the common passwords and words, the passwords go first.
+var ranks = func() map[string]int {
+	ranks := make(map[string]int, len(commonPasswords)+len(commonWords))
+	for _, list := range [][]string{commonPasswords, commonWords} {
+		for _, w := range list {
+			if _, ok := ranks[w]; !ok {
+				ranks[w] = len(ranks) + 1
+			}
+		}
+	}
+	return ranks
+}()
+
+// Result is the strength of a password.
+type Result struct {
+	// Score from 0, guessed in no time, to MaxScore.
+	Score int
+	// Guesses log10 of the estimated number of guesses.
+	Guesses float64
+	// Warning the pattern making the password weak, empty for strong passwords.
+	Warning string
+}
+
+type match struct {
+	// i and j bounds of the pattern, runes[i:j].
+	i, j    int
+	guesses float64
+	kind    string
+}
+
+// Estimate returns the strength of the password.
+// Only the first maxLength characters are analysed.
+func Estimate(password string) Result {
+	runes := []rune(password)
+	if len(runes) > maxLength {
+		runes = runes[:maxLength]
+	}
+	n := len(runes)
+	if n == 0 {
+		return Result{}
+	}
+
+	best, via := split(runes, matches(runes, true))
+
+	r := Result{Guesses: best[n]}
+	for r.Score < MaxScore && r.Guesses >= scoreGuesses[r.Score] {
+		r.Score++
+	}
+
+	if r.Score < MaxScore-1 {
+		r.Warning = warning(via, n)
+	}
+	return r
+}
+
+// split returns the cheapest split of the runes into the patterns:
+// best[k] the guesses of runes[:k] and via[k] the last pattern of it.
+func split(runes []rune, patterns []match) ([]float64, []*match) {
+	n := len(runes)
+	byEnd := make([][]match, n+1)
+	for _, m := range patterns {
+		byEnd[m.j] = append(byEnd[m.j], m)
+	}
+
+	best := make([]float64, n+1)
+	via := make([]*match, n+1)
+	for k := 1; k <= n; k++ {
+		best[k] = best[k-1] + bruteforceGuesses
+		for i := range byEnd[k] {
+			m := &byEnd[k][i]
+			if g := best[m.i] + m.guesses; g < best[k] {
+				best[k], via[k] = g, m
+			}
+		}
+	}
+	return best, via
+}
+
+// warning returns the kind of the longest pattern of the split.
+func warning(via []*match, n int) string {
+	var longest *match
+	for k := n; k > 0; {
+		m := via[k]
+		if m == nil {
+			k--
+			continue
+		}
+		if longest == nil || m.j-m.i > longest.j-longest.i {
+			longest = m
+		}
+		k = m.i
+	}
+
+	if longest == nil {
+		return ""
+	}
+	return longest.kind
+}
+
+// matches finds the patterns of the runes, the repeats are left out for the repeated chunks.
+func matches(runes []rune, repeats bool) []match {
+	lower := []rune(strings.ToLower(string(runes)))
+
+	var all []match
+	all = append(all, dictionaryMatches(runes, lower)...)
+	all = append(all, keyboardMatches(lower)...)
+	all = append(all, sequenceMatches(runes)...)
+	if repeats {
+		all = append(all, repeatMatches(runes)...)
+	}
+	all = append(all, yearMatches(runes)...)
+	return all
+}
+
+// dictionaryMatches finds common passwords and words.
+func dictionaryMatches(runes, lower []rune) []match {
+	var found []match
+	for i := range lower {
+		for j := i + 1; j <= len(lower) && j-i <= maxWordLen; j++ {
+			word := lower[i:j]
+			variations := math.Log10(caseVariations(runes[i:j]))
+
+			if g, kind, ok := lookup(string(word)); ok {
+				found = append(found, match{i: i, j: j, guesses: g + variations, kind: kind})
+			}
+			if g, kind, ok := lookup(reverse(word)); ok && j-i > 1 {
+				found = append(found, match{i: i, j: j, guesses: g + variations + math.Log10(2), kind: kind})
+			}
+			if plain, subs := unleet(word); subs > 0 {
+				if g, kind, ok := lookup(plain); ok {
+					found = append(found, match{i: i, j: j, guesses: g + variations + float64(subs)*math.Log10(2), kind: kind})
+				}
+			}
+		}
+	}
+	return found
+}
+
+// lookup returns log10 of the rank of the word.
+func lookup(word string) (float64, string, bool) {
+	rank, ok := ranks[word]
+	if !ok {
+		return 0, "", false
+	}
+
+	kind := Word
+	if rank <= len(commonPasswords) {
+		kind = Common
+	}
+	return math.Log10(float64(rank)), kind, true
+}
+
+// caseVariations returns the number of ways to capitalize the word like it is.
+// Lower case, upper case and capitalized words are the cheapest.
+func caseVariations(word []rune) float64 {
+	var upper, letters int
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+
+	switch {
+	case upper == 0:
+		return 1
+	case upper == letters || (upper == 1 && unicode.IsUpper(word[0])):
+		return 2
+	}
+
+	// any arrangement of up to that many upper case letters
+	var variations float64
+	for k := 0; k <= upper && k <= letters-upper; k++ {
+		variations += binomial(letters, k)
+	}
+	if upper > letters-upper {
+		variations = 0
+		for k := 0; k <= letters-upper; k++ {
+			variations += binomial(letters, k)
+		}
+	}
+	return math.Max(variations, 2)
+}
+
+func binomial(n, k int) float64 {
+	r := 1.0
+	for i := 1; i <= k; i++ {
+		r = r * float64(n-k+i) / float64(i)
+	}
+	return r
+}
+
+// unleet replaces l33t substitutions with the letters and returns their number.
+func unleet(word []rune) (string, int) {
+	var subs int
+	plain := make([]rune, len(word))
+	for i, r := range word {
+		if l, ok := leet[r]; ok {
+			plain[i] = l
+			subs++
+			continue
+		}
+		plain[i] = r
+	}
+	return string(plain), subs
+}
+
+func reverse(word []rune) string {
+	r := make([]rune, len(word))
+	for i, c := range word {
+		r[len(word)-1-i] = c
+	}
+	return string(r)
+}
+
+// keyboardMatches finds runs along the keyboard rows like qwerty or 1qaz.
+func keyboardMatches(lower []rune) []match {
+	var found []match
+	for _, row := range keyboardRows {
+		for _, keys := range [][]rune{[]rune(row), []rune(reverse([]rune(row)))} {
+			for i := range lower {
+				p := indexRune(keys, lower[i])
+				if p < 0 {
+					continue
+				}
+
+				k := 1
+				for i+k < len(lower) && p+k < len(keys) && keys[p+k] == lower[i+k] {
+					k++
+				}
+				for j := i + minKeyboardLen; j <= i+k; j++ {
+					found = append(found, match{i: i, j: j, guesses: math.Log10(float64(len(keys) * (j - i) * 2)), kind: Keyboard})
+				}
+			}
+		}
+	}
+	return found
+}
+
+func indexRune(runes []rune, r rune) int {
+	for i, c := range runes {
+		if c == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// sequenceMatches finds runs of consecutive characters like abc or 9876.
+func sequenceMatches(runes []rune) []match {
+	var found []match
+	for i := 0; i+1 < len(runes); i++ {
+		delta := runes[i+1] - runes[i]
+		if delta != 1 && delta != -1 {
+			continue
+		}
+
+		k := 2
+		for i+k < len(runes) && runes[i+k]-runes[i+k-1] == delta {
+			k++
+		}
+		if k < minSequenceLen {
+			continue
+		}
+
+		base := 26.0
+		switch first := runes[i]; {
+		case strings.ContainsRune("aAzZ019", first):
+			base = 4
+		case unicode.IsDigit(first):
+			base = 10
+		}
+		if delta < 0 {
+			base *= 2
+		}
+
+		for j := i + minSequenceLen; j <= i+k; j++ {
+			found = append(found, match{i: i, j: j, guesses: math.Log10(base * float64(j-i)), kind: Sequence})
+		}
+	}
+	return found
+}
+
+// repeatMatches finds repeated characters and chunks like aaa or abcabc.
+// A repeat costs the guesses of the shortest unit of the chunk, each unit is estimated once.
+func repeatMatches(runes []rune) []match {
+	var found []match
+	units := make(map[string]float64)
+	for i := range runes {
+		for size := 1; i+2*size <= len(runes); size++ {
+			chunk := runes[i : i+size]
+			count := 1
+			for i+(count+1)*size <= len(runes) && equal(runes[i+count*size:i+(count+1)*size], chunk) {
+				count++
+			}
+			if count < 2 || (size == 1 && count < 3) {
+				continue
+			}
+
+			unit := chunk[:period(chunk)]
+			base, ok := units[string(unit)]
+			if !ok {
+				base = math.Log10(cardinality(unit[0]))
+				if len(unit) > 1 {
+					best, _ := split(unit, matches(unit, false))
+					base = best[len(unit)]
+				}
+				units[string(unit)] = base
+			}
+			for c := 2; c <= count; c++ {
+				found = append(found, match{i: i, j: i + c*size, guesses: base + math.Log10(float64(c)), kind: Repeat})
+			}
+		}
+	}
+	return found
+}
+
+// period returns the length of the shortest unit the chunk is made of.
+func period(chunk []rune) int {
+	for p := 1; p < len(chunk); p++ {
+		if len(chunk)%p == 0 && equal(chunk[p:], chunk[:len(chunk)-p]) {
+			return p
+		}
+	}
+	return len(chunk)
+}
+
+func equal(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// cardinality the number of characters like the one.
+func cardinality(r rune) float64 {
+	switch {
+	case unicode.IsDigit(r):
+		return 10
+	case unicode.IsLetter(r):
+		return 26
+	default:
+		return 33
+	}
+}
+
+// yearMatches finds recent years like 1987 or 2024.
+func yearMatches(runes []rune) []match {
+	var found []match
+	for i := 0; i+4 <= len(runes); i++ {
+		year := 0
+		for _, r := range runes[i : i+4] {
+			if r < '0' || r > '9' {
+				year = -1
+				break
+			}
+			year = year*10 + int(r-'0')
+		}
+
+		if year >= minYear && year <= maxYear {
+			found = append(found, match{i: i, j: i + 4, guesses: math.Log10(yearGuesses), kind: Year})
+		}
+	}
+	return found
+}
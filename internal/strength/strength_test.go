@@ -0,0 +1,69 @@
+package strength
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		score    int
+		warning  string
+	}{
+		{name: "empty", password: "", score: 0},
+		{name: "common", password: "password", score: 0, warning: Common},
+		{name: "capitalized l33t", password: "P@ssw0rd", score: 0, warning: Common},
+		{name: "reversed", password: "drowssap", score: 0, warning: Common},
+		{name: "keyboard", password: "sdfghjk", score: 0, warning: Keyboard},
+		{name: "sequence", password: "abcdefgh", score: 0, warning: Sequence},
+		{name: "repeat", password: "aaaaaaaa", score: 0, warning: Repeat},
+		{name: "year", password: "1987", score: 0, warning: Year},
+		{name: "word and year", password: "iloveyou2020", score: 1, warning: Common},
+		{name: "short random", password: "x7#kQ!", score: 2},
+		{name: "long random", password: "kX9#mQ2$vL7!pR4&", score: MaxScore},
+		{name: "passphrase", password: "correcthorsebatterystaple", score: MaxScore},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Estimate(tt.password)
+			if r.Score != tt.score {
+				t.Errorf("Score = %d (guesses 1e%.1f), want %d", r.Score, r.Guesses, tt.score)
+			}
+			if r.Warning != tt.warning {
+				t.Errorf("Warning = %q, want %q", r.Warning, tt.warning)
+			}
+		})
+	}
+}
+
+func TestEstimate_monotonic(t *testing.T) {
+	// appending random characters never makes the password weaker
+	password := "qwerty"
+	prev := Estimate(password)
+	for _, c := range "#8Lz!q0P" {
+		password += string(c)
+		r := Estimate(password)
+		if r.Guesses < prev.Guesses {
+			t.Fatalf("Estimate(%q) = 1e%.1f guesses, less than 1e%.1f of the prefix", password, r.Guesses, prev.Guesses)
+		}
+		prev = r
+	}
+}
+
+func TestEstimate_long(t *testing.T) {
+	// the repeats of long passwords are estimated in no time
+	for _, password := range []string{strings.Repeat("a", 4096), strings.Repeat("ab1", 1400)} {
+		start := time.Now()
+		r := Estimate(password)
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Estimate() of %d characters took %v", len(password), elapsed)
+		}
+		if r.Warning != Repeat {
+			t.Errorf("Estimate() of %d characters warning = %q, want %q", len(password), r.Warning, Repeat)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package strength
+
+// commonPasswords the most common passwords of the leaks, most common first.
+var commonPasswords = []string{
+	"123456", "password", "12345678", "qwerty", "123456789", "12345", "1234", "111111",
+	"1234567", "dragon", "123123", "baseball", "abc123", "football", "monkey", "letmein",
+	"696969", "shadow", "master", "666666", "qwertyuiop", "123321", "mustang", "1234567890",
+	"michael", "654321", "superman", "1qaz2wsx", "7777777", "121212", "000000", "qazwsx",
+	"123qwe", "killer", "trustno1", "jordan", "jennifer", "zxcvbnm", "asdfgh", "hunter",
+	"buster", "soccer", "harley", "batman", "andrew", "tigger", "sunshine", "iloveyou",
+	"2000", "charlie", "robert", "thomas", "hockey", "ranger", "daniel", "starwars",
+	"klaster", "112233", "george", "computer", "michelle", "jessica", "pepper", "1111",
+	"zxcvbn", "555555", "11111111", "131313", "freedom", "777777", "pass", "maggie",
+	"159753", "aaaaaa", "ginger", "princess", "joshua", "cheese", "amanda", "summer",
+	"love", "ashley", "nicole", "chelsea", "biteme", "matthew", "access", "yankees",
+	"987654321", "dallas", "austin", "thunder", "taylor", "matrix", "admin", "welcome",
+	"login", "passw0rd", "qwerty123", "password1", "secret", "root", "toor", "changeme",
+	"default", "guest", "test", "user", "administrator", "qwe123", "1q2w3e4r", "1q2w3e",
+	"zaq12wsx", "q1w2e3r4", "abcd1234", "aa123456", "password123", "000000000", "999999",
+	"888888", "222222", "qweasd", "qweasdzxc", "asdf", "asdfghjkl", "natasha", "marina",
+	"nikita", "dima", "sasha", "maxim", "ivan", "parol", "privet", "qwertyu", "samsung",
+	"google", "apple", "facebook", "telegram", "whatever", "dragon1", "monkey1", "shadow1",
+}
+
+// commonWords frequent words, names and brands used in passwords.
+var commonWords = []string{
+	"the", "love", "baby", "angel", "happy", "money", "life", "girl", "boy", "king",
+	"queen", "star", "blue", "red", "black", "white", "green", "pink", "sun", "moon",
+	"dog", "cat", "lion", "tiger", "bear", "wolf", "eagle", "fish", "horse", "dragon",
+	"magic", "power", "super", "hello", "world", "home", "house", "family", "friend", "heart",
+	"sweet", "honey", "sugar", "cookie", "candy", "pizza", "coffee", "music", "rock", "metal",
+	"game", "gamer", "player", "soccer", "ball", "team", "club", "city", "london", "paris",
+	"moscow", "russia", "america", "china", "summer", "winter", "spring", "autumn", "monday", "friday",
+	"january", "april", "june", "july", "august", "october", "december", "alex", "anna", "maria",
+	"john", "david", "james", "mike", "chris", "peter", "olga", "elena", "irina", "sergey",
+	"andrey", "vladimir", "bank", "mail", "email", "work", "office", "school", "secret", "private",
+	"security", "system", "server", "cloud", "phone", "mobile", "window", "windows", "linux", "android",
+	"iphone", "google", "yandex", "amazon", "netflix", "github", "steam", "twitter", "instagram", "spotify",
+	"forever", "always", "never", "welcome", "letmein", "freedom", "justice", "peace", "faith", "hope",
+	"jesus", "christ", "god", "devil", "death", "ninja", "pirate", "zombie", "hunter", "killer",
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"password-keeper/internal/entity"
+	"password-keeper/internal/storage"
+	"strconv"
+	"strings"
+)
+
+// AccessPolicy defines who may use the bot.
+// The bot is open to everyone when neither Allow nor InviteCode is set.
+type AccessPolicy struct {
+	// Allow user IDs and usernames (with or without @) allowed to use the bot.
+	Allow []string
+	// Admins user IDs and usernames of the admins, they are allowed too.
+	Admins []string
+	// InviteCode lets users join with /start CODE.
+	InviteCode string
+}
+
+// Errors of the access control.
+var (
+	// ErrWrongInviteCode is returned when the invite code doesn't match.
+	ErrWrongInviteCode = errors.New("wrong invite code")
+	// ErrCantRevokeAdmin is returned on attempt to revoke an admin from the config.
+	ErrCantRevokeAdmin = errors.New("admins from the config can't be revoked")
+)
+
+// SetAccessPolicy sets who may use the bot.
+func (uc *UseCase) SetAccessPolicy(p AccessPolicy) {
+	uc.access = p
+}
+
+// IsOpen reports whether the bot is open to everyone.
+func (uc *UseCase) IsOpen() bool {
+	return len(uc.access.Allow) == 0 && uc.access.InviteCode == ""
+}
+
+// IsAllowed reports whether the user may use the bot.
+func (uc *UseCase) IsAllowed(userID int64, username string) bool {
+	if uc.IsAdmin(userID, username) {
+		return true
+	}
+
+	m, err := uc.storage.GetMember(userID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		err = fmt.Errorf("usecase.IsAllowed: %w", err)
+		uc.logger.Warn(err.Error())
+		return false
+	}
+
+	if err == nil && m.Revoked {
+		return false
+	}
+
+	return uc.IsOpen() || err == nil || matchUser(uc.access.Allow, userID, username)
+}
+
+// IsAdmin reports whether the user is an admin.
+func (uc *UseCase) IsAdmin(userID int64, username string) bool {
+	if matchUser(uc.access.Admins, userID, username) {
+		return true
+	}
+
+	m, err := uc.storage.GetMember(userID)
+	return err == nil && !m.Revoked && m.Role == entity.RoleAdmin
+}
+
+// Invite adds the user to the members if the invite code is right.
+// Wrong codes are counted like failed lookups, so the code can't be brute-forced.
+func (uc *UseCase) Invite(userID int64, username, code string) error {
+	if err := uc.checkLock(userID); err != nil {
+		return err
+	}
+
+	if uc.access.InviteCode == "" || subtle.ConstantTimeCompare([]byte(code), []byte(uc.access.InviteCode)) != 1 {
+		uc.registerFailure(userID)
+		return ErrWrongInviteCode
+	}
+
+	m, err := uc.storage.GetMember(userID)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		m = entity.Member{UserID: userID, Role: entity.RoleUser, CreatedAt: uc.now()}
+	case err != nil:
+		err = fmt.Errorf("usecase.Invite: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	case m.Revoked:
+		// revoked members can't come back with the same code
+		return ErrWrongInviteCode
+	}
+
+	m.Username = username
+	if err := uc.storage.SaveMember(m); err != nil {
+		err = fmt.Errorf("usecase.Invite: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// SetRevoked revokes or restores the access of the user.
+func (uc *UseCase) SetRevoked(userID int64, revoked bool) error {
+	if revoked && matchUser(uc.access.Admins, userID, "") {
+		return ErrCantRevokeAdmin
+	}
+
+	m, err := uc.storage.GetMember(userID)
+	if errors.Is(err, storage.ErrNotFound) {
+		m = entity.Member{UserID: userID, Role: entity.RoleUser, CreatedAt: uc.now()}
+	} else if err != nil {
+		err = fmt.Errorf("usecase.SetRevoked: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	m.Revoked = revoked
+	if err := uc.storage.SaveMember(m); err != nil {
+		err = fmt.Errorf("usecase.SetRevoked: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Members returns the members who joined or were revoked.
+// Users from the allowlist are not included until they are revoked.
+func (uc *UseCase) Members() ([]entity.Member, error) {
+	members, err := uc.storage.GetMembers()
+	if err != nil {
+		err = fmt.Errorf("usecase.Members: %w", err)
+		uc.logger.Warn(err.Error())
+		return nil, err
+	}
+	return members, nil
+}
+
+// Stats returns usage statistics.
+func (uc *UseCase) Stats() (entity.Stats, error) {
+	stats, err := uc.storage.GetStats()
+	if err != nil {
+		err = fmt.Errorf("usecase.Stats: %w", err)
+		uc.logger.Warn(err.Error())
+		return entity.Stats{}, err
+	}
+	return stats, nil
+}
+
+// matchUser reports whether the user ID or username is in the list.
+func matchUser(list []string, userID int64, username string) bool {
+	id := strconv.FormatInt(userID, 10)
+	for _, u := range list {
+		u = strings.TrimPrefix(strings.TrimSpace(u), "@")
+		if u == id || (username != "" && strings.EqualFold(u, username)) {
+			return true
+		}
+	}
+	return false
+}
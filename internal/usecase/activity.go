@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"password-keeper/internal/entity"
+	"password-keeper/internal/storage"
+	"time"
+)
+
+// ActivityLimit number of the latest records shown by RecentActivity.
+const ActivityLimit = 20
+
+// unknownServiceLen length of the hash prefix shown for services the chat has no entry with.
+const unknownServiceLen = 8
+
+// Activity is a record of the audit log with the name of the service.
+type Activity struct {
+	Action string
+	// Service name of the service, a prefix of its hash like #Ab3dE9fQ when
+	// the chat has no entry with it anymore, empty for actions without one.
+	Service string
+	Outcome string
+	At      time.Time
+}
+
+// SetAuditRetention sets how long the audit log is kept, zero keeps it forever.
+func (uc *UseCase) SetAuditRetention(d time.Duration) {
+	uc.auditRetention = d
+}
+
+// record appends the action on the service with the result err to the audit log of the chat
+// and returns err. When the record can't be appended the error of the log is returned instead,
+// so the action fails rather than passing unnoticed.
+func (uc *UseCase) record(chatID int64, action, service string, err error) error {
+	r := entity.AuditRecord{
+		ChatID:    chatID,
+		Action:    action,
+		Outcome:   outcome(err),
+		CreatedAt: uc.now(),
+	}
+
+	if service != "" {
+		hash, err := uc.Hash(service)
+		if err != nil {
+			return err
+		}
+		r.Service = hash
+	}
+
+	if err := uc.appendAudit(r); err != nil {
+		err = fmt.Errorf("usecase.record: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+	return err
+}
+
+// outcome returns the outcome of the action with the result err.
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return entity.OutcomeOK
+	case errors.Is(err, storage.ErrNotFound):
+		return entity.OutcomeNotFound
+	case errors.Is(err, ErrLocked):
+		return entity.OutcomeLocked
+	case errors.Is(err, ErrReadOnly), errors.Is(err, ErrVaultRole), errors.Is(err, ErrNoVault):
+		return entity.OutcomeDenied
+	default:
+		return entity.OutcomeError
+	}
+}
+
+// RecentActivity returns the latest ActivityLimit records of the audit log of the chat, newest first.
+func (uc *UseCase) RecentActivity(chatID int64) ([]Activity, error) {
+	records, err := uc.storage.GetAuditRecords(chatID, ActivityLimit)
+	if err != nil {
+		err = fmt.Errorf("usecase.RecentActivity: %w", err)
+		uc.logger.Warn(err.Error())
+		return nil, err
+	}
+
+	entries, _, err := uc.Entries(chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(entries))
+	for _, e := range entries {
+		hash, err := uc.Hash(e.Service)
+		if err != nil {
+			return nil, err
+		}
+		names[hash] = e.Service
+	}
+
+	activity := make([]Activity, 0, len(records))
+	for _, r := range records {
+		a := Activity{Action: r.Action, Outcome: r.Outcome, At: r.CreatedAt}
+		if name, ok := names[r.Service]; ok {
+			a.Service = name
+		} else if len(r.Service) >= unknownServiceLen {
+			a.Service = "#" + r.Service[:unknownServiceLen]
+		}
+		activity = append(activity, a)
+	}
+	return activity, nil
+}
+
+// PurgeAudit deletes the records of the audit log up to the last one older than the retention period,
+// so the remaining chain continues the sealed checkpoint of the last purged record.
+func (uc *UseCase) PurgeAudit() (int64, error) {
+	if uc.auditRetention == 0 {
+		return 0, nil
+	}
+
+	cutoff, err := uc.storage.GetAuditCutoff(uc.now().Add(-uc.auditRetention))
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		err = fmt.Errorf("usecase.PurgeAudit: %w", err)
+		uc.logger.Warn(err.Error())
+		return 0, err
+	}
+
+	n, err := uc.storage.DeleteAuditRecords(entity.AuditAnchor{
+		RecordID: cutoff.ID,
+		Hash:     cutoff.Hash,
+		Seal:     uc.sealAnchor(entity.AnchorCheckpoint, cutoff.Hash),
+	})
+	if err != nil {
+		err = fmt.Errorf("usecase.PurgeAudit: %w", err)
+		uc.logger.Warn(err.Error())
+		return 0, err
+	}
+	return n, nil
+}
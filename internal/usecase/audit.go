@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"password-keeper/internal/strength"
+	"time"
+)
+
+// Audit thresholds.
+const (
+	// WeakScore the highest strength score of weak passwords.
+	WeakScore = 2
+	// OldAge passwords unchanged for longer are reported as old.
+	OldAge = 365 * 24 * time.Hour
+)
+
+// AuditEntry is the health of an entry.
+type AuditEntry struct {
+	Service string
+	// Score strength of the password from 0 to strength.MaxScore.
+	Score int
+	// Warning the pattern making the password weak, see strength.Result.
+	Warning string
+	Weak    bool
+	// ReusedWith other services with the same password.
+	ReusedWith []string
+	// Old is false when the entry was saved before the changes were tracked.
+	Old     bool
+	NoLogin bool
+}
+
+// Healthy returns true when nothing is wrong with the entry.
+func (e AuditEntry) Healthy() bool {
+	return !e.Weak && len(e.ReusedWith) == 0 && !e.Old && !e.NoLogin
+}
+
+// AuditReport is the health report of the entries of a chat.
+type AuditReport struct {
+	// Entries sorted by the service.
+	Entries []AuditEntry
+	Weak    int
+	Reused  int
+	Old     int
+	NoLogin int
+	// Unnamed number of entries saved before the names were stored, they can't be audited.
+	Unnamed int
+}
+
+// Audit returns the health report of the entries of the chat.
+func (uc *UseCase) Audit(chatID int64) (AuditReport, error) {
+	entries, unnamed, err := uc.Entries(chatID)
+	if err != nil {
+		return AuditReport{}, err
+	}
+
+	report := AuditReport{Unnamed: unnamed}
+	for _, e := range entries {
+		r := strength.Estimate(e.Password)
+		a := AuditEntry{
+			Service:    e.Service,
+			Score:      r.Score,
+			Warning:    r.Warning,
+			Weak:       r.Score <= WeakScore,
+			ReusedWith: reusedWith(entries, e.Service, e.Password),
+			Old:        !e.UpdatedAt.IsZero() && uc.now().Sub(e.UpdatedAt) > OldAge,
+			NoLogin:    e.Login == "",
+		}
+
+		for _, f := range []struct {
+			bad   bool
+			count *int
+		}{
+			{a.Weak, &report.Weak},
+			{len(a.ReusedWith) > 0, &report.Reused},
+			{a.Old, &report.Old},
+			{a.NoLogin, &report.NoLogin},
+		} {
+			if f.bad {
+				*f.count++
+			}
+		}
+		report.Entries = append(report.Entries, a)
+	}
+
+	return report, nil
+}
+
+// Assess returns the strength of the password and the other services of the chat using it.
+func (uc *UseCase) Assess(chatID int64, service, password string) (strength.Result, []string, error) {
+	entries, _, err := uc.Entries(chatID)
+	if err != nil {
+		return strength.Result{}, nil, err
+	}
+
+	return strength.Estimate(password), reusedWith(entries, service, password), nil
+}
+
+// reusedWith returns the services of the entries with the password except the service.
+func reusedWith(entries []Entry, service, password string) []string {
+	if password == "" {
+		return nil
+	}
+
+	var services []string
+	for _, e := range entries {
+		if e.Service != service && e.Password == password {
+			services = append(services, e.Service)
+		}
+	}
+	return services
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BreachChecker counts the breaches the password appears in, see package breach.
+type BreachChecker interface {
+	Count(password string) (int, error)
+}
+
+// ErrBreachCheckOff is returned when no breach dataset is configured.
+var ErrBreachCheckOff = errors.New("breach check is not configured")
+
+// SetBreachChecker enables the breach check of passwords.
+func (uc *UseCase) SetBreachChecker(c BreachChecker) {
+	uc.breaches = c
+}
+
+// CheckBreach returns the number of known breaches the password appears in.
+func (uc *UseCase) CheckBreach(password string) (int, error) {
+	if uc.breaches == nil {
+		return 0, ErrBreachCheckOff
+	}
+
+	n, err := uc.breaches.Count(password)
+	if err != nil {
+		err = fmt.Errorf("usecase.CheckBreach: %w", err)
+		uc.logger.Warn(err.Error())
+		return 0, err
+	}
+	return n, nil
+}
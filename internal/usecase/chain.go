@@ -0,0 +1,320 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"password-keeper/internal/entity"
+	"password-keeper/internal/storage"
+	"sort"
+	"strconv"
+)
+
+// appendAttempts the record is appended again when another handler or another replica
+// of the bot has appended its record after the head of the chain was read. Every attempt
+// loses to one record only, so it's more than the records appended at once.
+const appendAttempts = 32
+
+// chainBatch number of records read at once by VerifyAudit.
+const chainBatch = 500
+
+// Reasons of the broken links of the audit log chain.
+const (
+	// BreakHash the record was altered.
+	BreakHash = "record hash mismatch"
+	// BreakLink records were removed or inserted before the record,
+	// the first one doesn't continue the purge checkpoint.
+	BreakLink = "previous record hash mismatch"
+	// BreakChatLink records of the chat were removed or inserted before the record.
+	BreakChatLink = "previous chat record hash mismatch"
+	// BreakUnsealed the record without a hash was added after the chain had started.
+	BreakUnsealed = "record is not sealed"
+	// BreakHead the head record is missing, the latest records were removed.
+	BreakHead = "head record is missing"
+	// BreakAnchor the head or the purge checkpoint was altered.
+	BreakAnchor = "anchor seal mismatch"
+	// BreakForgotten the links of the records removed by the forget record were altered.
+	BreakForgotten = "forgotten records mismatch"
+)
+
+// AuditVerification is the result of the audit log chain verification.
+type AuditVerification struct {
+	// Checked number of records in the chain.
+	Checked int
+	// Legacy number of records added before the chain, they can't be verified.
+	Legacy int
+	// BrokenID id of the first record with a broken link, zero when the chain is intact.
+	BrokenID   int64
+	BrokenChat int64
+	Reason     string
+}
+
+// Intact returns true when no broken link was found.
+func (v AuditVerification) Intact() bool {
+	return v.BrokenID == 0
+}
+
+// appendAudit seals the record with the hashes of the last record and
+// the last record of the chat and appends it to the audit log as the new head.
+// The storage doesn't allow two records with the same previous record,
+// so the append losing to a concurrent one fails and is retried.
+func (uc *UseCase) appendAudit(r entity.AuditRecord) error {
+	var err error
+	for i := 0; i < appendAttempts; i++ {
+		r.PrevHash, r.ChatPrevHash, err = uc.storage.GetAuditHead(r.ChatID)
+		if err != nil {
+			return err
+		}
+
+		r.Hash = uc.sealAudit(r)
+		if err = uc.storage.AddAuditRecord(r, uc.sealAnchor(entity.AnchorHead, r.Hash)); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// sealAudit returns the hash of the record keyed with the audit key,
+// so the chain can't be rebuilt without the encryption key.
+func (uc *UseCase) sealAudit(r entity.AuditRecord) string {
+	mac := hmac.New(sha256.New, uc.auditKey)
+	for _, field := range []string{
+		r.PrevHash,
+		r.ChatPrevHash,
+		strconv.FormatInt(r.ChatID, 10),
+		r.Action,
+		r.Service,
+		r.Outcome,
+		strconv.FormatInt(r.CreatedAt.Unix(), 10),
+	} {
+		mac.Write([]byte(field))
+		mac.Write([]byte{0})
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// forgetAudit seals the forget record taking the place of the removed records of a chat
+// and the links of the removed records, see entity.AuditForget. The forget record has no chat,
+// its service is the digest of the removed records, and the links let VerifyAudit walk over them,
+// so no other record can be removed unnoticed.
+func (uc *UseCase) forgetAudit(prevHash, chatPrevHash string, removed []entity.AuditLink) (entity.AuditRecord, []entity.AuditLink, string) {
+	r := entity.AuditRecord{
+		Action:       entity.ActionForget,
+		Service:      forgottenDigest(removed),
+		Outcome:      entity.OutcomeOK,
+		CreatedAt:    uc.now(),
+		PrevHash:     prevHash,
+		ChatPrevHash: chatPrevHash,
+	}
+	r.Hash = uc.sealAudit(r)
+
+	links := make([]entity.AuditLink, len(removed))
+	for i, l := range removed {
+		l.ForgetHash = r.Hash
+		l.Seal = uc.sealLink(l)
+		links[i] = l
+	}
+	return r, links, uc.sealAnchor(entity.AnchorHead, r.Hash)
+}
+
+// forgottenDigest returns the digest of the hashes of the removed records in any order.
+func forgottenDigest(links []entity.AuditLink) string {
+	hashes := make([]string, len(links))
+	for i, l := range links {
+		hashes[i] = l.Hash
+	}
+	sort.Strings(hashes)
+
+	h := sha256.New()
+	for _, hash := range hashes {
+		h.Write([]byte(hash))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// sealLink returns the keyed hash of the link of the removed record.
+func (uc *UseCase) sealLink(l entity.AuditLink) string {
+	mac := hmac.New(sha256.New, uc.auditKey)
+	for _, field := range []string{l.Hash, l.PrevHash, l.ForgetHash} {
+		mac.Write([]byte(field))
+		mac.Write([]byte{0})
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// auditLinks sealed links of the removed records.
+type auditLinks struct {
+	// prev hashes of the previous records by the hashes of the removed records.
+	prev map[string]string
+	// forgotten links by the hashes of their forget records.
+	forgotten map[string][]entity.AuditLink
+}
+
+// auditLinks reads the links of the removed records, the ones with a broken seal are left out.
+func (uc *UseCase) auditLinks() (auditLinks, error) {
+	links, err := uc.storage.GetAuditLinks()
+	if err != nil {
+		err = fmt.Errorf("usecase.VerifyAudit: %w", err)
+		uc.logger.Warn(err.Error())
+		return auditLinks{}, err
+	}
+
+	l := auditLinks{prev: make(map[string]string, len(links)), forgotten: make(map[string][]entity.AuditLink)}
+	for _, link := range links {
+		if hmac.Equal([]byte(link.Seal), []byte(uc.sealLink(link))) {
+			l.prev[link.Hash] = link.PrevHash
+			l.forgotten[link.ForgetHash] = append(l.forgotten[link.ForgetHash], link)
+		}
+	}
+	return l, nil
+}
+
+// bridges reports whether the hash leads to last over the removed records only.
+func (l auditLinks) bridges(hash, last string) bool {
+	for i := 0; hash != last; i++ {
+		prev, ok := l.prev[hash]
+		if !ok || i > len(l.prev) {
+			return false
+		}
+		hash = prev
+	}
+	return true
+}
+
+// sealAnchor returns the keyed hash of the anchor of the kind referencing the record with the hash.
+func (uc *UseCase) sealAnchor(kind, hash string) string {
+	mac := hmac.New(sha256.New, uc.auditKey)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(hash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// auditAnchor returns the anchor of the kind, the zero one when there is none yet.
+// sealed is false when the anchor was altered.
+func (uc *UseCase) auditAnchor(kind string) (a entity.AuditAnchor, sealed bool, err error) {
+	a, err = uc.storage.GetAuditAnchor(kind)
+	if errors.Is(err, storage.ErrNotFound) {
+		return entity.AuditAnchor{}, true, nil
+	}
+	if err != nil {
+		err = fmt.Errorf("usecase.VerifyAudit: %w", err)
+		uc.logger.Warn(err.Error())
+		return entity.AuditAnchor{}, false, err
+	}
+	return a, hmac.Equal([]byte(a.Seal), []byte(uc.sealAnchor(kind, a.Hash))), nil
+}
+
+// VerifyAudit walks the audit log chain and returns the first broken link.
+// The chain continues the checkpoint of the last purged record, it starts with the first sealed
+// record when nothing was purged, and ends with the head, so the first and the latest records
+// can't be removed unnoticed. The first remaining record of every chat is trusted,
+// the previous ones may have been purged. The records removed by ForgetMe are walked over
+// with their links, and every forget record must match the links of the records it removed.
+func (uc *UseCase) VerifyAudit() (AuditVerification, error) {
+	// the head is read first, the records appended during the walk follow it
+	head, sealed, err := uc.auditAnchor(entity.AnchorHead)
+	if err != nil {
+		return AuditVerification{}, err
+	}
+	if !sealed {
+		return AuditVerification{BrokenID: head.RecordID, Reason: BreakAnchor}, nil
+	}
+	checkpoint, sealed, err := uc.auditAnchor(entity.AnchorCheckpoint)
+	if err != nil {
+		return AuditVerification{}, err
+	}
+	if !sealed {
+		return AuditVerification{BrokenID: checkpoint.RecordID, Reason: BreakAnchor}, nil
+	}
+	links, err := uc.auditLinks()
+	if err != nil {
+		return AuditVerification{}, err
+	}
+
+	var v AuditVerification
+	last := checkpoint.Hash
+	chats := make(map[int64]string)
+	started := checkpoint.Hash != ""
+	// the walk reaches the head unless there is none yet or it was purged too
+	headSeen := head.Hash == "" || head.Hash == checkpoint.Hash
+
+	for afterID := int64(0); ; {
+		records, err := uc.storage.GetAuditChain(afterID, chainBatch)
+		if err != nil {
+			err = fmt.Errorf("usecase.VerifyAudit: %w", err)
+			uc.logger.Warn(err.Error())
+			return AuditVerification{}, err
+		}
+		if len(records) == 0 {
+			if !headSeen {
+				// the head may have been forgotten during the walk
+				if links, err = uc.auditLinks(); err != nil {
+					return AuditVerification{}, err
+				}
+				if _, ok := links.prev[head.Hash]; !ok {
+					v.BrokenID, v.Reason = head.RecordID, BreakHead
+				}
+			}
+			return v, nil
+		}
+
+		for _, r := range records {
+			reason := uc.checkAudit(r, started, last, chats, links)
+			if reason == BreakLink || reason == BreakForgotten {
+				// the records may have been forgotten after the links were read
+				if links, err = uc.auditLinks(); err != nil {
+					return AuditVerification{}, err
+				}
+				reason = uc.checkAudit(r, started, last, chats, links)
+			}
+			if reason != "" {
+				v.BrokenID, v.BrokenChat, v.Reason = r.ID, r.ChatID, reason
+				return v, nil
+			}
+			if r.ID == head.RecordID && r.Hash == head.Hash {
+				headSeen = true
+			}
+
+			if r.Hash == "" {
+				v.Legacy++
+				continue
+			}
+			v.Checked++
+			started = true
+			last = r.Hash
+			chats[r.ChatID] = r.Hash
+		}
+		afterID = records[len(records)-1].ID
+	}
+}
+
+// checkAudit returns the reason the link of the record is broken, empty when it is intact.
+// last and chats are the hashes of the previous record and the previous records of the chats,
+// last is the hash of the checkpoint or empty for the first sealed record.
+// The previous record may be the last one of the records removed by ForgetMe.
+func (uc *UseCase) checkAudit(r entity.AuditRecord, started bool, last string, chats map[int64]string, links auditLinks) string {
+	if r.Hash == "" {
+		if started {
+			return BreakUnsealed
+		}
+		return ""
+	}
+
+	if !hmac.Equal([]byte(r.Hash), []byte(uc.sealAudit(r))) {
+		return BreakHash
+	}
+	if r.PrevHash != last && !links.bridges(r.PrevHash, last) {
+		return BreakLink
+	}
+	if chatLast, ok := chats[r.ChatID]; ok && r.ChatPrevHash != chatLast {
+		return BreakChatLink
+	}
+	if r.ChatID == 0 && r.Action == entity.ActionForget && forgottenDigest(links.forgotten[r.Hash]) != r.Service {
+		return BreakForgotten
+	}
+	return ""
+}
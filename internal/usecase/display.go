@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"password-keeper/internal/storage"
+)
+
+// DisplayMode is what of the entry is shown on /get.
+type DisplayMode string
+
+// Display modes, DisplayAll is the default.
+const (
+	DisplayAll      DisplayMode = "all"
+	DisplayLogin    DisplayMode = "login"
+	DisplayPassword DisplayMode = "password"
+	// DisplayReveal shows the login, the password is revealed on demand.
+	DisplayReveal DisplayMode = "reveal"
+)
+
+// ErrDisplayMode is returned for unknown display modes.
+var ErrDisplayMode = errors.New("unknown display mode")
+
+// SetDisplay sets what of the entries is shown to the chat, DisplayAll removes the setting.
+func (uc *UseCase) SetDisplay(chatID int64, mode DisplayMode) error {
+	var err error
+	switch mode {
+	case DisplayAll:
+		err = uc.storage.DeleteDisplayMode(chatID)
+	case DisplayLogin, DisplayPassword, DisplayReveal:
+		err = uc.storage.SetDisplayMode(chatID, string(mode))
+	default:
+		return ErrDisplayMode
+	}
+
+	if err != nil {
+		err = fmt.Errorf("usecase.SetDisplay: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+	return nil
+}
+
+// Display returns what of the entries is shown to the chat.
+func (uc *UseCase) Display(chatID int64) (DisplayMode, error) {
+	mode, err := uc.storage.GetDisplayMode(chatID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return DisplayAll, nil
+	}
+	if err != nil {
+		err = fmt.Errorf("usecase.Display: %w", err)
+		uc.logger.Warn(err.Error())
+		return DisplayAll, err
+	}
+	return DisplayMode(mode), nil
+}
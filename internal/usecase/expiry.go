@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"password-keeper/internal/entity"
+	"password-keeper/internal/storage"
+	"sort"
+	"time"
+)
+
+// Expiry of the entries.
+const (
+	// MinExpiry the shortest time to live of an entry.
+	MinExpiry = time.Hour
+	// ExpiryWarning the owner is warned this long before the entry expires.
+	ExpiryWarning = 3 * 24 * time.Hour
+)
+
+// ErrExpiry is returned when the entry would expire sooner than MinExpiry.
+var ErrExpiry = errors.New("expiry is too soon")
+
+// Expiring is an entry about to expire or expired.
+type Expiring struct {
+	Service   string
+	ExpiresAt time.Time
+	// hash of the service, the entry is marked warned by it.
+	hash string
+}
+
+// ExpiryWarnings returns the entries to warn about by the chats:
+// they expire within ExpiryWarning and their owners haven't been warned yet.
+func (uc *UseCase) ExpiryWarnings() (map[int64][]Expiring, error) {
+	return uc.expiring(func(e entity.ExpiringEntry) bool {
+		return !e.ExpiryWarned && uc.now().Before(e.ExpiresAt)
+	})
+}
+
+// ExpiryWarned marks the chat warned about the entries, so the warning is sent once.
+func (uc *UseCase) ExpiryWarned(chatID int64, entries []Expiring) error {
+	for _, e := range entries {
+		if err := uc.storage.SetExpiryWarned(chatID, e.hash); err != nil {
+			err = fmt.Errorf("usecase.ExpiryWarned: %w", err)
+			uc.logger.Warn(err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+// SweepExpired deletes the expired entries with their shares and returns them by the chats.
+// An entry saved again after it was read here is kept. The deletions are recorded in the audit log.
+func (uc *UseCase) SweepExpired() (map[int64][]Expiring, error) {
+	now := uc.now()
+	due, err := uc.expiring(func(e entity.ExpiringEntry) bool {
+		return !now.Before(e.ExpiresAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	swept := make(map[int64][]Expiring)
+	for owner, entries := range due {
+		for _, e := range entries {
+			err := uc.sweep(owner, e, now)
+			if errors.Is(err, storage.ErrNotFound) {
+				continue
+			}
+			uc.record(owner, entity.ActionExpire, e.Service, err)
+			if err != nil {
+				continue
+			}
+			swept[owner] = append(swept[owner], e)
+		}
+	}
+	return swept, nil
+}
+
+// sweep deletes the expired entry of the owner and its shares.
+func (uc *UseCase) sweep(owner int64, e Expiring, now time.Time) error {
+	if err := uc.storage.DeleteExpired(owner, e.hash, now); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+		err = fmt.Errorf("usecase.SweepExpired: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	if err := uc.storage.DeleteShares(owner, e.hash); err != nil {
+		err = fmt.Errorf("usecase.SweepExpired: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+	return nil
+}
+
+// expiring returns the entries expiring within ExpiryWarning and matching the filter by the chats,
+// sorted by the expiry. Entries saved before the names were stored can't be listed and are left out.
+func (uc *UseCase) expiring(filter func(e entity.ExpiringEntry) bool) (map[int64][]Expiring, error) {
+	entries, err := uc.storage.GetExpiringEntries(uc.now().Add(ExpiryWarning))
+	if err != nil {
+		err = fmt.Errorf("usecase.expiring: %w", err)
+		uc.logger.Warn(err.Error())
+		return nil, err
+	}
+
+	result := make(map[int64][]Expiring)
+	for _, e := range entries {
+		if e.Name == "" || !filter(e) {
+			continue
+		}
+
+		name, err := uc.Decrypt(e.Name)
+		if err != nil {
+			err = fmt.Errorf("usecase.Decrypt: %w", err)
+			uc.logger.Warn(err.Error())
+			return nil, err
+		}
+		result[e.Owner] = append(result[e.Owner], Expiring{Service: name, ExpiresAt: e.ExpiresAt, hash: e.Service})
+	}
+
+	for _, list := range result {
+		sort.Slice(list, func(i, j int) bool { return list[i].ExpiresAt.Before(list[j].ExpiresAt) })
+	}
+	return result, nil
+}
+
+// expired reports whether the entry has expired by now.
+func expired(pair entity.Pair, now time.Time) bool {
+	return !pair.ExpiresAt.IsZero() && !now.Before(pair.ExpiresAt)
+}
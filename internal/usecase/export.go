@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"password-keeper/internal/entity"
+	"sort"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Export file format.
+//
+// The file is a JSON document:
+//
+//	{
+//	  "format": "password-keeper-export",
+//	  "version": 1,
+//	  "kdf": {"name": "argon2id", "salt": "...", "time": 3, "memory": 65536, "threads": 4},
+//	  "cipher": "aes-256-gcm",
+//	  "nonce": "...",
+//	  "data": "..."
+//	}
+//
+// salt, nonce and data are standard base64. The key is
+// argon2.IDKey(passphrase, salt, time, memory, threads, 32), memory is in KiB.
+// data is the AES-256-GCM ciphertext of the JSON
+//
+//	{"exported_at": "2006-01-02T15:04:05Z", "entries": [{"service": "...", "login": "...", "password": "..."}]}
+//
+// with the nonce and without additional data.
+const (
+	exportFormat  = "password-keeper-export"
+	exportVersion = 1
+	exportKDF     = "argon2id"
+	exportCipher  = "aes-256-gcm"
+)
+
+// Argon2id parameters of new exports and the limits of imported ones,
+// the limits keep a crafted file from exhausting the memory of the bot.
+const (
+	exportTime       = 3
+	exportMemory     = 64 * 1024
+	exportThreads    = 4
+	exportKeySize    = 32
+	exportSaltSize   = 16
+	maxExportTime    = 10
+	maxExportMemory  = 256 * 1024
+	maxExportThreads = 16
+)
+
+// MinPassphraseLen the minimal length of export passphrases.
+const MinPassphraseLen = 8
+
+var (
+	// ErrWeakPassphrase is returned when the passphrase is shorter than MinPassphraseLen.
+	ErrWeakPassphrase = errors.New("passphrase is too short")
+	// ErrBadExport is returned when the file is not an export or the passphrase is wrong.
+	ErrBadExport = errors.New("wrong passphrase or damaged export")
+	// ErrNothingToExport is returned when the chat has no entries to export.
+	ErrNothingToExport = errors.New("nothing to export")
+)
+
+// Entry is a decrypted entry of a chat.
+type Entry struct {
+	Service  string `json:"service"`
+	Login    string `json:"login"`
+	Password string `json:"password"`
+	// UpdatedAt time of the last change, it's not exported.
+	UpdatedAt time.Time `json:"-"`
+}
+
+type exportFile struct {
+	Format  string    `json:"format"`
+	Version int       `json:"version"`
+	KDF     exportKey `json:"kdf"`
+	Cipher  string    `json:"cipher"`
+	Nonce   []byte    `json:"nonce"`
+	Data    []byte    `json:"data"`
+}
+
+type exportKey struct {
+	Name    string `json:"name"`
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+type exportData struct {
+	ExportedAt time.Time `json:"exported_at"`
+	Entries    []Entry   `json:"entries"`
+}
+
+// Entries returns the decrypted entries of the chat sorted by service.
+// Entries saved before the names were stored can't be listed, their number is returned as unnamed.
+func (uc *UseCase) Entries(chatID int64) (entries []Entry, unnamed int, err error) {
+	pairs, err := uc.storage.GetAll(chatID)
+	if err != nil {
+		err = fmt.Errorf("usecase.Entries: %w", err)
+		uc.logger.Warn(err.Error())
+		return nil, 0, err
+	}
+
+	for _, pair := range pairs {
+		if pair.Name == "" {
+			unnamed++
+			continue
+		}
+
+		e := Entry{UpdatedAt: pair.UpdatedAt}
+		for _, f := range []struct{ dst, src *string }{
+			{&e.Service, &pair.Name},
+			{&e.Login, &pair.Login},
+			{&e.Password, &pair.Password},
+		} {
+			*f.dst, err = uc.Decrypt(*f.src)
+			if err != nil {
+				err = fmt.Errorf("usecase.Decrypt: %w", err)
+				uc.logger.Warn(err.Error())
+				return nil, 0, err
+			}
+		}
+		entries = append(entries, e)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Service < entries[j].Service })
+	return entries, unnamed, nil
+}
+
+// Export returns the entries of the chat encrypted with the passphrase,
+// see the format above. The number of exported and unnamed entries is returned too.
+// Every call is recorded in the audit log, the imported entries are recorded by Save.
+func (uc *UseCase) Export(chatID int64, passphrase string) (file []byte, exported, unnamed int, err error) {
+	defer func() {
+		if err = uc.record(chatID, entity.ActionExport, "", err); err != nil {
+			file, exported, unnamed = nil, 0, 0
+		}
+	}()
+
+	if err := uc.checkLock(chatID); err != nil {
+		return nil, 0, 0, err
+	}
+	if len(passphrase) < MinPassphraseLen {
+		return nil, 0, 0, ErrWeakPassphrase
+	}
+
+	entries, unnamed, err := uc.Entries(chatID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if len(entries) == 0 {
+		return nil, 0, unnamed, ErrNothingToExport
+	}
+
+	plain, err := json.Marshal(exportData{ExportedAt: uc.now().UTC(), Entries: entries})
+	if err != nil {
+		err = fmt.Errorf("usecase.Export: %w", err)
+		uc.logger.Warn(err.Error())
+		return nil, 0, 0, err
+	}
+
+	file, err = sealExport(passphrase, plain)
+	if err != nil {
+		err = fmt.Errorf("usecase.Export: %w", err)
+		uc.logger.Warn(err.Error())
+		return nil, 0, 0, err
+	}
+
+	return file, len(entries), unnamed, nil
+}
+
+// Import decrypts the export with the passphrase and saves its entries to the chat with Save.
+// Entries with the same names are replaced, except the ones shared with the chat read-only.
+// The number of imported entries is returned.
+func (uc *UseCase) Import(chatID int64, passphrase string, file []byte) (int, error) {
+	if err := uc.checkLock(chatID); err != nil {
+		return 0, err
+	}
+
+	plain, err := openExport(passphrase, file)
+	if err != nil {
+		return 0, err
+	}
+
+	var data exportData
+	if err := json.Unmarshal(plain, &data); err != nil {
+		return 0, ErrBadExport
+	}
+
+	var n int
+	for _, e := range data.Entries {
+		if e.Service == "" {
+			continue
+		}
+		err := uc.Save(chatID, e.Service, e.Login, e.Password)
+		if errors.Is(err, ErrReadOnly) {
+			continue
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// sealExport encrypts the data with a key derived from the passphrase.
+func sealExport(passphrase string, plain []byte) ([]byte, error) {
+	salt, err := randomBytes(exportSaltSize)
+	if err != nil {
+		return nil, err
+	}
+
+	kdf := exportKey{Name: exportKDF, Salt: salt, Time: exportTime, Memory: exportMemory, Threads: exportThreads}
+	gcm, err := newGCM(kdf.derive(passphrase))
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := randomBytes(gcm.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(exportFile{
+		Format:  exportFormat,
+		Version: exportVersion,
+		KDF:     kdf,
+		Cipher:  exportCipher,
+		Nonce:   nonce,
+		Data:    gcm.Seal(nil, nonce, plain, nil),
+	}, "", "  ")
+}
+
+// openExport is the reverse of sealExport.
+func openExport(passphrase string, file []byte) ([]byte, error) {
+	var f exportFile
+	if err := json.Unmarshal(file, &f); err != nil {
+		return nil, ErrBadExport
+	}
+
+	if f.Format != exportFormat || f.Version != exportVersion || f.Cipher != exportCipher || !f.KDF.valid() {
+		return nil, ErrBadExport
+	}
+
+	gcm, err := newGCM(f.KDF.derive(passphrase))
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Nonce) != gcm.NonceSize() {
+		return nil, ErrBadExport
+	}
+
+	plain, err := gcm.Open(nil, f.Nonce, f.Data, nil)
+	if err != nil {
+		return nil, ErrBadExport
+	}
+	return plain, nil
+}
+
+func (k exportKey) derive(passphrase string) []byte {
+	return argon2.IDKey([]byte(passphrase), k.Salt, k.Time, k.Memory, k.Threads, exportKeySize)
+}
+
+// valid reports whether the parameters are supported and cheap enough to derive the key.
+func (k exportKey) valid() bool {
+	return k.Name == exportKDF && len(k.Salt) >= exportSaltSize &&
+		k.Time >= 1 && k.Time <= maxExportTime &&
+		k.Memory >= 8*uint32(k.Threads) && k.Memory <= maxExportMemory &&
+		k.Threads >= 1 && k.Threads <= maxExportThreads
+}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"password-keeper/internal/entity"
+	"password-keeper/internal/storage"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// MaxFileSize is the largest file that can be attached to a service.
+const MaxFileSize = 10 << 20
+
+// ErrFileTooLarge is returned when the file is larger than MaxFileSize.
+var ErrFileTooLarge = errors.New("file is too large")
+
+// File is a decrypted attachment of a service, Data is empty in the list of Files.
+type File struct {
+	Service   string
+	FileName  string
+	Photo     bool
+	Data      []byte
+	CreatedAt time.Time
+}
+
+// SaveFile encrypts the file and attaches it to the service, replacing the previous one.
+// The content is bound to the owner and the service, so it can't be moved to another entry.
+func (uc *UseCase) SaveFile(chatID int64, f File) (err error) {
+	defer func() { err = uc.record(chatID, entity.ActionFileSave, f.Service, err) }()
+
+	if len(f.Data) > MaxFileSize {
+		return ErrFileTooLarge
+	}
+
+	service, err := uc.Hash(f.Service)
+	if err != nil {
+		err = fmt.Errorf("usecase.Hash: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	stored := entity.File{Service: service, Photo: f.Photo, CreatedAt: uc.now()}
+	for _, s := range []struct{ dst, src *string }{
+		{&stored.Name, &f.Service},
+		{&stored.FileName, &f.FileName},
+	} {
+		*s.dst, err = uc.Encrypt(*s.src)
+		if err != nil {
+			err = fmt.Errorf("usecase.Encrypt: %w", err)
+			uc.logger.Warn(err.Error())
+			return err
+		}
+	}
+
+	stored.Data, err = uc.sealFile(chatID, service, f.Data)
+	if err != nil {
+		err = fmt.Errorf("usecase.SaveFile: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	if err := uc.storage.SaveFile(chatID, stored); err != nil {
+		err = fmt.Errorf("usecase.SaveFile: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+	return nil
+}
+
+// GetFile returns the decrypted file of the service.
+// Like Get, it reveals whether the file exists, so it's protected the same way.
+func (uc *UseCase) GetFile(chatID int64, service string) (f File, err error) {
+	defer func() {
+		if err = uc.record(chatID, entity.ActionFileGet, service, err); err != nil {
+			f = File{}
+		}
+	}()
+
+	if err := uc.checkLock(chatID); err != nil {
+		return File{}, err
+	}
+
+	hash, err := uc.Hash(service)
+	if err != nil {
+		err = fmt.Errorf("usecase.Hash: %w", err)
+		uc.logger.Warn(err.Error())
+		return File{}, err
+	}
+
+	stored, err := uc.storage.GetFile(chatID, hash)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			uc.registerFailure(chatID)
+			return File{}, err
+		}
+		err = fmt.Errorf("usecase.GetFile: %w", err)
+		uc.logger.Warn(err.Error())
+		return File{}, err
+	}
+
+	f = File{Service: service, Photo: stored.Photo, CreatedAt: stored.CreatedAt}
+	f.FileName, err = uc.Decrypt(stored.FileName)
+	if err != nil {
+		err = fmt.Errorf("usecase.Decrypt: %w", err)
+		uc.logger.Warn(err.Error())
+		return File{}, err
+	}
+
+	f.Data, err = uc.openFile(chatID, hash, stored.Data)
+	if err != nil {
+		err = fmt.Errorf("usecase.GetFile: %w", err)
+		uc.logger.Warn(err.Error())
+		return File{}, err
+	}
+	return f, nil
+}
+
+// DeleteFile deletes the file of the service.
+func (uc *UseCase) DeleteFile(chatID int64, service string) (err error) {
+	defer func() { err = uc.record(chatID, entity.ActionFileDelete, service, err) }()
+
+	if err := uc.checkLock(chatID); err != nil {
+		return err
+	}
+
+	hash, err := uc.Hash(service)
+	if err != nil {
+		err = fmt.Errorf("usecase.Hash: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	err = uc.storage.DeleteFile(chatID, hash)
+	if errors.Is(err, storage.ErrNotFound) {
+		uc.registerFailure(chatID)
+		return err
+	}
+	if err != nil {
+		err = fmt.Errorf("usecase.DeleteFile: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+	return nil
+}
+
+// Files returns the files of the chat sorted by service, without the content.
+func (uc *UseCase) Files(chatID int64) ([]File, error) {
+	stored, err := uc.storage.GetFiles(chatID)
+	if err != nil {
+		err = fmt.Errorf("usecase.Files: %w", err)
+		uc.logger.Warn(err.Error())
+		return nil, err
+	}
+
+	files := make([]File, 0, len(stored))
+	for _, s := range stored {
+		f := File{Photo: s.Photo, CreatedAt: s.CreatedAt}
+		for _, field := range []struct{ dst, src *string }{
+			{&f.Service, &s.Name},
+			{&f.FileName, &s.FileName},
+		} {
+			*field.dst, err = uc.Decrypt(*field.src)
+			if err != nil {
+				err = fmt.Errorf("usecase.Decrypt: %w", err)
+				uc.logger.Warn(err.Error())
+				return nil, err
+			}
+		}
+		files = append(files, f)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Service < files[j].Service })
+	return files, nil
+}
+
+// sealFile encrypts the content with AES-GCM, the nonce is prepended to the result.
+// The owner and the service hash are authenticated as additional data.
+func (uc *UseCase) sealFile(owner int64, service string, plain []byte) ([]byte, error) {
+	gcm, err := cipher.NewGCM(uc.cipher)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := randomBytes(gcm.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plain, fileAD(owner, service)), nil
+}
+
+// openFile is the reverse of sealFile.
+func (uc *UseCase) openFile(owner int64, service string, sealed []byte) ([]byte, error) {
+	gcm, err := cipher.NewGCM(uc.cipher)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("sealed file is too short")
+	}
+
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], fileAD(owner, service))
+}
+
+func fileAD(owner int64, service string) []byte {
+	return []byte(strconv.FormatInt(owner, 10) + ":" + service)
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"password-keeper/internal/entity"
+	"password-keeper/internal/storage"
+)
+
+// ImportMode is what to do with imported entries conflicting with the saved ones.
+type ImportMode string
+
+// Modes of conflict resolution.
+const (
+	ImportSkip      ImportMode = "skip"
+	ImportOverwrite ImportMode = "overwrite"
+	// ImportKeepBoth saves the imported entry under a free name like service_2.
+	ImportKeepBoth ImportMode = "both"
+)
+
+// maxCopies limits the suffixes tried for a free name.
+const maxCopies = 100
+
+// ErrImportMode is returned for unknown modes.
+var ErrImportMode = errors.New("unknown import mode")
+
+// ImportReport is the result of an import.
+type ImportReport struct {
+	Imported int
+	// Duplicates number of entries saved already with the same login and password.
+	Duplicates int
+	// Conflicts entries saved already with another login or password, they are not saved.
+	Conflicts []Entry
+}
+
+// ImportEntries saves the entries imported from another password manager with Save.
+// Entries with the names of the saved or shared ones are not saved, they are returned as
+// conflicts to be resolved with ResolveConflicts, unless they are identical.
+// Lookups of the names are not counted as failures, the names come from the file.
+func (uc *UseCase) ImportEntries(chatID int64, entries []Entry) (ImportReport, error) {
+	var report ImportReport
+	if err := uc.checkLock(chatID); err != nil {
+		return report, err
+	}
+
+	for _, e := range entries {
+		saved, err := uc.saved(chatID, e.Service)
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			if err := uc.Save(chatID, e.Service, e.Login, e.Password); err != nil {
+				return report, err
+			}
+			report.Imported++
+		case err != nil:
+			return report, err
+		case saved.Login == e.Login && saved.Password == e.Password:
+			report.Duplicates++
+		default:
+			report.Conflicts = append(report.Conflicts, e)
+		}
+	}
+
+	uc.logger.Info(fmt.Sprintf("chat %d imported %d entries", chatID, report.Imported))
+	return report, nil
+}
+
+// ResolveConflicts saves the conflicting entries in the mode with Save and returns the number of saved ones.
+// Entries shared with the chat read-only are not overwritten.
+func (uc *UseCase) ResolveConflicts(chatID int64, conflicts []Entry, mode ImportMode) (int, error) {
+	switch mode {
+	case ImportSkip:
+		return 0, nil
+	case ImportOverwrite, ImportKeepBoth:
+	default:
+		return 0, ErrImportMode
+	}
+
+	if err := uc.checkLock(chatID); err != nil {
+		return 0, err
+	}
+
+	var n int
+	for _, e := range conflicts {
+		service := e.Service
+		if mode == ImportKeepBoth {
+			var err error
+			service, err = uc.freeName(chatID, e.Service)
+			if err != nil {
+				return n, err
+			}
+		}
+
+		err := uc.Save(chatID, service, e.Login, e.Password)
+		if errors.Is(err, ErrReadOnly) {
+			continue
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// saved returns the entry of the chat or shared with it, the access is not recorded.
+func (uc *UseCase) saved(chatID int64, service string) (entity.Pair, error) {
+	pair, err := uc.lookup(chatID, service)
+	if !errors.Is(err, storage.ErrNotFound) {
+		return pair, err
+	}
+
+	sh, err := uc.activeShare(chatID, service)
+	if err != nil {
+		return entity.Pair{}, err
+	}
+	return uc.openShare(sh, service)
+}
+
+// freeName returns the first name like service_2 the chat has no entry with, own or shared.
+func (uc *UseCase) freeName(chatID int64, service string) (string, error) {
+	for i := 2; i <= maxCopies; i++ {
+		name := fmt.Sprintf("%s_%d", service, i)
+		_, err := uc.saved(chatID, name)
+		if errors.Is(err, storage.ErrNotFound) {
+			return name, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err := fmt.Errorf("usecase.ResolveConflicts: no free name for %d copies", maxCopies)
+	uc.logger.Warn(err.Error())
+	return "", err
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Brute-force protection policy.
+// After maxFailures failed lookups within failureWindow the chat is locked out.
+// The lockout lasts baseLockout and doubles with each next one up to maxLockout.
+// The doubling starts over when the chat had no lockouts for levelResetAfter.
+const (
+	maxFailures     = 5
+	failureWindow   = time.Hour
+	baseLockout     = time.Minute
+	maxLockout      = 24 * time.Hour
+	levelResetAfter = 24 * time.Hour
+)
+
+// ErrLocked is returned when the chat is locked out after too many failed lookups.
+var ErrLocked = errors.New("too many failed lookups")
+
+// LockedUntil returns the end of the chat lockout, zero time if the chat isn't locked.
+func (uc *UseCase) LockedUntil(chatID int64) time.Time {
+	l, err := uc.storage.GetLockout(chatID)
+	if err != nil {
+		err = fmt.Errorf("usecase.LockedUntil: %w", err)
+		uc.logger.Warn(err.Error())
+		return time.Time{}
+	}
+
+	if uc.now().Before(l.LockedUntil) {
+		return l.LockedUntil
+	}
+	return time.Time{}
+}
+
+// checkLock returns ErrLocked if the chat is locked out.
+func (uc *UseCase) checkLock(chatID int64) error {
+	if !uc.LockedUntil(chatID).IsZero() {
+		return ErrLocked
+	}
+	return nil
+}
+
+// registerFailure counts the failed lookup and locks the chat out if there are too many.
+func (uc *UseCase) registerFailure(chatID int64) {
+	uc.lockMu.Lock()
+	defer uc.lockMu.Unlock()
+
+	l, err := uc.storage.GetLockout(chatID)
+	if err != nil {
+		err = fmt.Errorf("usecase.registerFailure: %w", err)
+		uc.logger.Warn(err.Error())
+		return
+	}
+
+	now := uc.now()
+	if now.Sub(l.WindowStart) > failureWindow {
+		l.Failures, l.WindowStart = 0, now
+	}
+
+	if l.Level > 0 && now.Sub(l.LockedUntil) > levelResetAfter {
+		l.Level = 0
+	}
+
+	l.Failures++
+	if l.Failures >= maxFailures {
+		l.LockedUntil = now.Add(lockoutDuration(l.Level))
+		l.Level++
+		l.Failures, l.WindowStart = 0, now
+		uc.logger.Warn(fmt.Sprintf("chat %d is locked out until %s", chatID, l.LockedUntil.Format(time.RFC3339)))
+	}
+
+	if err := uc.storage.SetLockout(chatID, l); err != nil {
+		err = fmt.Errorf("usecase.registerFailure: %w", err)
+		uc.logger.Warn(err.Error())
+	}
+}
+
+// lockoutDuration returns the duration of the lockout of the level.
+func lockoutDuration(level int) time.Duration {
+	d := baseLockout
+	for i := 0; i < level && d < maxLockout; i++ {
+		d *= 2
+	}
+
+	if d > maxLockout {
+		return maxLockout
+	}
+	return d
+}
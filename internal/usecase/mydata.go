@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"password-keeper/internal/entity"
+	"password-keeper/internal/storage"
+	"time"
+)
+
+// DataReport is everything stored about a chat, the secrets are only counted.
+type DataReport struct {
+	entity.UserData
+	// Lang is empty when the language was never chosen or detected.
+	Lang     string
+	LangAuto bool
+	// Rotation period of the reminders, zero when they are off.
+	Rotation time.Duration
+	// Member is nil when the chat has never joined the private bot.
+	Member *entity.Member
+	// AuditRetention how long the audit log is kept, zero keeps it forever.
+	AuditRetention time.Duration
+	Display        DisplayMode
+}
+
+// MyData returns the report of everything stored about the chat.
+func (uc *UseCase) MyData(chatID int64) (DataReport, error) {
+	d, err := uc.storage.GetUserData(chatID)
+	if err != nil {
+		err = fmt.Errorf("usecase.MyData: %w", err)
+		uc.logger.Warn(err.Error())
+		return DataReport{}, err
+	}
+
+	report := DataReport{UserData: d, AuditRetention: uc.auditRetention}
+	lang, err := uc.storage.GetLang(chatID)
+	switch {
+	case err == nil:
+		report.Lang, report.LangAuto = lang.Code, lang.Auto
+	case !errors.Is(err, sql.ErrNoRows):
+		err = fmt.Errorf("usecase.MyData: %w", err)
+		uc.logger.Warn(err.Error())
+		return DataReport{}, err
+	}
+
+	member, err := uc.storage.GetMember(chatID)
+	switch {
+	case err == nil:
+		report.Member = &member
+	case !errors.Is(err, storage.ErrNotFound):
+		err = fmt.Errorf("usecase.MyData: %w", err)
+		uc.logger.Warn(err.Error())
+		return DataReport{}, err
+	}
+
+	if report.Rotation, err = uc.Rotation(chatID); err != nil {
+		return DataReport{}, err
+	}
+	if report.Display, err = uc.Display(chatID); err != nil {
+		return DataReport{}, err
+	}
+	return report, nil
+}
+
+// ForgetMe deletes everything stored about the chat with its audit log records,
+// a forget record takes their place in the chain, see forgetAudit. The delete losing
+// to a concurrent append is retried like appendAudit.
+// Nothing else is recorded, the chat is forgotten.
+func (uc *UseCase) ForgetMe(chatID int64) error {
+	var err error
+	for i := 0; i < appendAttempts; i++ {
+		if err = uc.storage.DeleteUser(chatID, uc.forgetAudit); err == nil {
+			return nil
+		}
+	}
+
+	err = fmt.Errorf("usecase.ForgetMe: %w", err)
+	uc.logger.Warn(err.Error())
+	return err
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"password-keeper/internal/entity"
+	"password-keeper/internal/storage"
+	"time"
+)
+
+// Limits of one-time links.
+const (
+	oneTimeKeySize = 32
+	oneTimeIDSize  = 16
+)
+
+// ErrOneTimeGone is returned when the one-time link was opened, has expired or never existed.
+var ErrOneTimeGone = errors.New("one-time secret is gone")
+
+// OneTimePayload is the content of a one-time link, the page decrypts it to this JSON.
+type OneTimePayload struct {
+	Service  string `json:"service"`
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+// CreateOneTime encrypts the entry with a new key and stores it for a single view.
+// The returned key is put in the fragment of the link, so it never reaches the server
+// and the stored secret can't be decrypted without the link.
+func (uc *UseCase) CreateOneTime(chatID int64, service string, ttl time.Duration) (id, key string, err error) {
+	pair, err := uc.Get(chatID, service)
+	if err != nil {
+		return "", "", err
+	}
+
+	plain, err := json.Marshal(OneTimePayload{Service: service, Login: pair.Login, Password: pair.Password})
+	if err != nil {
+		err = fmt.Errorf("usecase.CreateOneTime: %w", err)
+		uc.logger.Warn(err.Error())
+		return "", "", err
+	}
+
+	rawKey, err := randomBytes(oneTimeKeySize)
+	if err != nil {
+		err = fmt.Errorf("usecase.CreateOneTime: %w", err)
+		uc.logger.Warn(err.Error())
+		return "", "", err
+	}
+
+	secret, err := SealOneTime(rawKey, plain)
+	if err != nil {
+		err = fmt.Errorf("usecase.CreateOneTime: %w", err)
+		uc.logger.Warn(err.Error())
+		return "", "", err
+	}
+
+	rawID, err := randomBytes(oneTimeIDSize)
+	if err != nil {
+		err = fmt.Errorf("usecase.CreateOneTime: %w", err)
+		uc.logger.Warn(err.Error())
+		return "", "", err
+	}
+
+	name, err := uc.Encrypt(service)
+	if err != nil {
+		err = fmt.Errorf("usecase.Encrypt: %w", err)
+		uc.logger.Warn(err.Error())
+		return "", "", err
+	}
+
+	ot := entity.OneTime{
+		ID:        base64.RawURLEncoding.EncodeToString(rawID),
+		Owner:     chatID,
+		Name:      name,
+		Secret:    secret,
+		ExpiresAt: uc.now().Add(ttl),
+		CreatedAt: uc.now(),
+	}
+	if err := uc.storage.SaveOneTime(ot); err != nil {
+		err = fmt.Errorf("usecase.CreateOneTime: %w", err)
+		uc.logger.Warn(err.Error())
+		return "", "", err
+	}
+
+	return ot.ID, base64.RawURLEncoding.EncodeToString(rawKey), nil
+}
+
+// TakeOneTime returns the secret of the link and destroys it.
+// The name of the secret is decrypted, so the owner can be told which link was opened.
+func (uc *UseCase) TakeOneTime(id string) (entity.OneTime, error) {
+	ot, err := uc.storage.TakeOneTime(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return entity.OneTime{}, ErrOneTimeGone
+		}
+		err = fmt.Errorf("usecase.TakeOneTime: %w", err)
+		uc.logger.Warn(err.Error())
+		return entity.OneTime{}, err
+	}
+
+	if !uc.now().Before(ot.ExpiresAt) {
+		return entity.OneTime{}, ErrOneTimeGone
+	}
+
+	ot.Name, err = uc.Decrypt(ot.Name)
+	if err != nil {
+		err = fmt.Errorf("usecase.Decrypt: %w", err)
+		uc.logger.Warn(err.Error())
+		return entity.OneTime{}, err
+	}
+
+	return ot, nil
+}
+
+// PurgeOneTime deletes expired one-time secrets and returns their number.
+func (uc *UseCase) PurgeOneTime() (int64, error) {
+	n, err := uc.storage.DeleteExpiredOneTime(uc.now())
+	if err != nil {
+		err = fmt.Errorf("usecase.PurgeOneTime: %w", err)
+		uc.logger.Warn(err.Error())
+		return 0, err
+	}
+	return n, nil
+}
+
+// SealOneTime encrypts the text with AES-GCM, the nonce is prepended to the result.
+// The page decrypts it with WebCrypto, so the format must stay in sync with it.
+func SealOneTime(key, plain []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce, err := randomBytes(gcm.NonceSize())
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(gcm.Seal(nonce, nonce, plain, nil)), nil
+}
+
+// OpenOneTime is the reverse of SealOneTime.
+func OpenOneTime(key []byte, secret string) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(secret)
+	if err != nil || len(raw) < gcm.NonceSize() {
+		return nil, ErrOneTimeGone
+	}
+
+	return gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"password-keeper/internal/entity"
+	"password-keeper/internal/storage"
+	"sort"
+	"time"
+)
+
+// Rotation reminder intervals.
+const (
+	// MinRotation the shortest rotation period, the periods are stored in days.
+	MinRotation = 24 * time.Hour
+	// RemindRepeat the reminder is repeated after it until the passwords are changed.
+	RemindRepeat = 7 * 24 * time.Hour
+	// SnoozePeriod the reminders are snoozed for it by the button.
+	SnoozePeriod = 30 * 24 * time.Hour
+)
+
+// GeneratedLen length of the generated passwords.
+const GeneratedLen = 20
+
+// generatedChars characters of the generated passwords, the look-alike ones are left out.
+const generatedChars = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789!#$%&*+-=?@^_"
+
+// ErrRotationPeriod is returned for periods shorter than MinRotation.
+var ErrRotationPeriod = errors.New("rotation period is too short")
+
+// SetRotation sets the rotation period of all the entries of the chat, zero turns the reminders off.
+// The entries with periods of their own keep them.
+func (uc *UseCase) SetRotation(chatID int64, period time.Duration) error {
+	if period != 0 && period < MinRotation {
+		return ErrRotationPeriod
+	}
+
+	if err := uc.storage.SetRotation(chatID, entity.Rotation{Period: period}); err != nil {
+		err = fmt.Errorf("usecase.SetRotation: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+	return nil
+}
+
+// SetEntryRotation sets the rotation period of the entry.
+// Zero means the period of the chat, negative turns the reminders off for the entry.
+// Missing entries are counted as failed lookups.
+func (uc *UseCase) SetEntryRotation(chatID int64, service string, period time.Duration) error {
+	if period > 0 && period < MinRotation {
+		return ErrRotationPeriod
+	}
+
+	if err := uc.checkLock(chatID); err != nil {
+		return err
+	}
+
+	service, err := uc.Hash(service)
+	if err != nil {
+		err = fmt.Errorf("usecase.Hash: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	if period < 0 {
+		period = -MinRotation
+	}
+	if err := uc.storage.SetServiceRotation(chatID, service, period); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			uc.registerFailure(chatID)
+		}
+		err = fmt.Errorf("usecase.SetEntryRotation: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+	return nil
+}
+
+// Rotation returns the rotation period of the chat, zero when the reminders are off.
+func (uc *UseCase) Rotation(chatID int64) (time.Duration, error) {
+	r, err := uc.storage.GetRotation(chatID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		err = fmt.Errorf("usecase.Rotation: %w", err)
+		uc.logger.Warn(err.Error())
+		return 0, err
+	}
+	return r.Period, nil
+}
+
+// DueRotations returns the services to remind about by the chats.
+// The chats with the reminders snoozed are left out.
+func (uc *UseCase) DueRotations() (map[int64][]string, error) {
+	return uc.dueRotations(func(e entity.RotationEntry) bool {
+		return e.Chat.RemindAt.Before(uc.now())
+	})
+}
+
+// DueServices returns the services of the chat with passwords older than the rotation period.
+func (uc *UseCase) DueServices(chatID int64) ([]string, error) {
+	due, err := uc.dueRotations(func(e entity.RotationEntry) bool {
+		return e.Owner == chatID
+	})
+	return due[chatID], err
+}
+
+// dueRotations returns the services of the entries matching the filter with
+// passwords older than their rotation period. The age of entries saved before
+// the changes were tracked is unknown, so they are due. Entries saved before
+// the names were stored can't be listed and are left out.
+func (uc *UseCase) dueRotations(filter func(e entity.RotationEntry) bool) (map[int64][]string, error) {
+	entries, err := uc.storage.GetRotationEntries()
+	if err != nil {
+		err = fmt.Errorf("usecase.DueRotations: %w", err)
+		uc.logger.Warn(err.Error())
+		return nil, err
+	}
+
+	due := make(map[int64][]string)
+	for _, e := range entries {
+		period := e.Period
+		if period == 0 {
+			period = e.Chat.Period
+		}
+		if period <= 0 || e.Name == "" || !filter(e) {
+			continue
+		}
+		if !e.UpdatedAt.IsZero() && uc.now().Sub(e.UpdatedAt) < period {
+			continue
+		}
+
+		name, err := uc.Decrypt(e.Name)
+		if err != nil {
+			err = fmt.Errorf("usecase.Decrypt: %w", err)
+			uc.logger.Warn(err.Error())
+			return nil, err
+		}
+		due[e.Owner] = append(due[e.Owner], name)
+	}
+
+	for _, services := range due {
+		sort.Strings(services)
+	}
+	return due, nil
+}
+
+// RemindLater stops the reminders to the chat for d.
+func (uc *UseCase) RemindLater(chatID int64, d time.Duration) error {
+	r, err := uc.storage.GetRotation(chatID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		err = fmt.Errorf("usecase.RemindLater: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	r.RemindAt = uc.now().Add(d)
+	if err := uc.storage.SetRotation(chatID, r); err != nil {
+		err = fmt.Errorf("usecase.RemindLater: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+	return nil
+}
+
+// GeneratePassword returns a random password of GeneratedLen characters.
+func GeneratePassword() (string, error) {
+	max := big.NewInt(int64(len(generatedChars)))
+	password := make([]byte, GeneratedLen)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("usecase.GeneratePassword: %w", err)
+		}
+		password[i] = generatedChars[n.Int64()]
+	}
+	return string(password), nil
+}
@@ -0,0 +1,396 @@
+package usecase
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"password-keeper/internal/entity"
+	"password-keeper/internal/storage"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// Errors of the sharing.
+var (
+	// ErrUnknownUser is returned when the recipient has never used the bot.
+	ErrUnknownUser = errors.New("unknown user")
+	// ErrShareSelf is returned on attempt to share the entry with its owner.
+	ErrShareSelf = errors.New("can't share with yourself")
+	// ErrReadOnly is returned on attempt to update the entry shared read-only.
+	ErrReadOnly = errors.New("the entry is shared read-only")
+)
+
+// SeeUser remembers the username of the user, so the entries can be shared by username.
+func (uc *UseCase) SeeUser(userID int64, username string) {
+	err := uc.storage.SaveUser(entity.User{ID: userID, Username: strings.ToLower(username)})
+	if err != nil {
+		err = fmt.Errorf("usecase.SeeUser: %w", err)
+		uc.logger.Warn(err.Error())
+	}
+}
+
+// FindUser returns the ID of the user by the ID or @username.
+// Only users who have used the bot can be found by username.
+func (uc *UseCase) FindUser(ref string) (int64, error) {
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil && id > 0 {
+		return id, nil
+	}
+
+	username := strings.ToLower(strings.TrimPrefix(ref, "@"))
+	if username == "" {
+		return 0, ErrUnknownUser
+	}
+
+	u, err := uc.storage.GetUserByName(username)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return 0, ErrUnknownUser
+		}
+		err = fmt.Errorf("usecase.FindUser: %w", err)
+		uc.logger.Warn(err.Error())
+		return 0, err
+	}
+	return u.ID, nil
+}
+
+// Share gives the recipient access to the entry of the owner.
+// The entry is encrypted again for the recipient, see sealShare,
+// and the copy is updated when the entry is saved. Zero ttl means the share never expires.
+func (uc *UseCase) Share(owner int64, service string, recipient int64, ttl time.Duration, readOnly bool) error {
+	if owner == recipient {
+		return ErrShareSelf
+	}
+
+	hash, err := uc.Hash(service)
+	if err != nil {
+		err = fmt.Errorf("usecase.Hash: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	pair, err := uc.lookup(owner, service)
+	if err != nil {
+		return err
+	}
+
+	name, err := uc.Encrypt(service)
+	if err != nil {
+		err = fmt.Errorf("usecase.Encrypt: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	sh := entity.Share{
+		Owner:     owner,
+		Service:   hash,
+		Recipient: recipient,
+		Name:      name,
+		ReadOnly:  readOnly,
+		CreatedAt: uc.now(),
+	}
+	if ttl > 0 {
+		sh.ExpiresAt = uc.now().Add(ttl)
+	}
+
+	sh.Sealed, err = uc.sealShare(sh, pair)
+	if err != nil {
+		err = fmt.Errorf("usecase.Share: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	if err := uc.storage.SaveShare(sh); err != nil {
+		err = fmt.Errorf("usecase.Share: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Unshare takes the access to the entry back from the recipient.
+func (uc *UseCase) Unshare(owner int64, service string, recipient int64) error {
+	hash, err := uc.Hash(service)
+	if err != nil {
+		err = fmt.Errorf("usecase.Hash: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	if err := uc.storage.DeleteShare(owner, hash, recipient); err != nil {
+		err = fmt.Errorf("usecase.Unshare: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+	return nil
+}
+
+// Shares returns the active shares of the owner with decrypted names.
+func (uc *UseCase) Shares(owner int64) ([]entity.Share, error) {
+	shares, err := uc.storage.GetShares(owner)
+	if err != nil {
+		err = fmt.Errorf("usecase.Shares: %w", err)
+		uc.logger.Warn(err.Error())
+		return nil, err
+	}
+
+	active := shares[:0]
+	for _, sh := range shares {
+		if uc.expired(sh) {
+			continue
+		}
+
+		sh.Name, err = uc.Decrypt(sh.Name)
+		if err != nil {
+			err = fmt.Errorf("usecase.Decrypt: %w", err)
+			uc.logger.Warn(err.Error())
+			return nil, err
+		}
+		active = append(active, sh)
+	}
+
+	return active, nil
+}
+
+// getShared returns the entry shared with the recipient and records the access.
+func (uc *UseCase) getShared(recipient int64, service string) (entity.Pair, error) {
+	sh, err := uc.activeShare(recipient, service)
+	if err != nil {
+		return entity.Pair{}, err
+	}
+
+	pair, err := uc.openShare(sh, service)
+	if err != nil {
+		return entity.Pair{}, err
+	}
+	if expired(pair, uc.now()) {
+		return entity.Pair{}, storage.ErrNotFound
+	}
+
+	if err := uc.storage.AddShareAccess(sh, uc.now()); err != nil {
+		err = fmt.Errorf("usecase.getShared: %w", err)
+		uc.logger.Warn(err.Error())
+	}
+	uc.logger.Info(fmt.Sprintf("shared entry of %d is read by %d", sh.Owner, sh.Recipient))
+
+	return pair, nil
+}
+
+// writableShare returns the share the recipient can update.
+// storage.ErrNotFound is returned when the recipient has an own entry with the name,
+// it takes precedence over the shared one.
+func (uc *UseCase) writableShare(recipient int64, service string) (entity.Share, error) {
+	hash, err := uc.Hash(service)
+	if err != nil {
+		err = fmt.Errorf("usecase.Hash: %w", err)
+		uc.logger.Warn(err.Error())
+		return entity.Share{}, err
+	}
+
+	if _, err := uc.storage.Get(recipient, hash); err == nil {
+		return entity.Share{}, storage.ErrNotFound
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		err = fmt.Errorf("usecase.writableShare: %w", err)
+		uc.logger.Warn(err.Error())
+		return entity.Share{}, err
+	}
+
+	sh, err := uc.activeShare(recipient, service)
+	if err != nil {
+		return entity.Share{}, err
+	}
+
+	if sh.ReadOnly {
+		return entity.Share{}, ErrReadOnly
+	}
+	return sh, nil
+}
+
+// dropShare removes the entry shared with the recipient from the recipient.
+func (uc *UseCase) dropShare(recipient int64, service string) error {
+	sh, err := uc.activeShare(recipient, service)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.storage.DeleteShare(sh.Owner, sh.Service, recipient); err != nil {
+		err = fmt.Errorf("usecase.dropShare: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+	return nil
+}
+
+// activeShare returns the share of the service with the recipient, expired shares are deleted.
+func (uc *UseCase) activeShare(recipient int64, service string) (entity.Share, error) {
+	hash, err := uc.Hash(service)
+	if err != nil {
+		err = fmt.Errorf("usecase.Hash: %w", err)
+		uc.logger.Warn(err.Error())
+		return entity.Share{}, err
+	}
+
+	sh, err := uc.storage.GetShare(recipient, hash)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			err = fmt.Errorf("usecase.activeShare: %w", err)
+			uc.logger.Warn(err.Error())
+		}
+		return entity.Share{}, err
+	}
+
+	if uc.expired(sh) {
+		if err := uc.storage.DeleteShare(sh.Owner, sh.Service, recipient); err != nil {
+			err = fmt.Errorf("usecase.activeShare: %w", err)
+			uc.logger.Warn(err.Error())
+		}
+		return entity.Share{}, storage.ErrNotFound
+	}
+
+	return sh, nil
+}
+
+func (uc *UseCase) expired(sh entity.Share) bool {
+	return !sh.ExpiresAt.IsZero() && !uc.now().Before(sh.ExpiresAt)
+}
+
+// sharedEntry is the copy of the entry sealed for the recipient of the share.
+type sharedEntry struct {
+	Login     string `json:"login"`
+	Password  string `json:"password"`
+	ExpiresAt int64  `json:"expires_at,omitempty"`
+}
+
+// sealShare encrypts the entry for the recipient of the share with AES-GCM, the nonce is prepended.
+// The key is derived for the recipient with HKDF from the key of the deployment, and the owner,
+// the recipient and the service hash are authenticated, so the copy can't be read by anyone else.
+func (uc *UseCase) sealShare(sh entity.Share, pair entity.Pair) ([]byte, error) {
+	gcm, err := uc.recipientGCM(sh.Recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := sharedEntry{Login: pair.Login, Password: pair.Password}
+	if !pair.ExpiresAt.IsZero() {
+		entry.ExpiresAt = pair.ExpiresAt.Unix()
+	}
+	plain, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := randomBytes(gcm.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plain, shareAD(sh)), nil
+}
+
+// openShare returns the decrypted copy of the entry of the share.
+// Shares made before the copies existed are sealed from the entry of the owner on the first read.
+func (uc *UseCase) openShare(sh entity.Share, service string) (entity.Pair, error) {
+	if len(sh.Sealed) == 0 {
+		pair, err := uc.lookup(sh.Owner, service)
+		if err != nil {
+			return entity.Pair{}, err
+		}
+
+		if err := uc.reseal(sh, pair); err != nil {
+			return entity.Pair{}, err
+		}
+		return pair, nil
+	}
+
+	var entry sharedEntry
+	plain, err := uc.openSealed(sh)
+	if err == nil {
+		err = json.Unmarshal(plain, &entry)
+	}
+	if err != nil {
+		err = fmt.Errorf("usecase.openShare: %w", err)
+		uc.logger.Warn(err.Error())
+		return entity.Pair{}, err
+	}
+
+	pair := entity.Pair{Login: entry.Login, Password: entry.Password}
+	if entry.ExpiresAt != 0 {
+		pair.ExpiresAt = time.Unix(entry.ExpiresAt, 0)
+	}
+	return pair, nil
+}
+
+// openSealed is the reverse of sealShare.
+func (uc *UseCase) openSealed(sh entity.Share) ([]byte, error) {
+	gcm, err := uc.recipientGCM(sh.Recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sh.Sealed) < gcm.NonceSize() {
+		return nil, errors.New("sealed share is too short")
+	}
+
+	return gcm.Open(nil, sh.Sealed[:gcm.NonceSize()], sh.Sealed[gcm.NonceSize():], shareAD(sh))
+}
+
+// resealShares seals the saved entry again for the recipients of its shares.
+func (uc *UseCase) resealShares(owner int64, service string, pair entity.Pair) error {
+	shares, err := uc.storage.GetShares(owner)
+	if err != nil {
+		err = fmt.Errorf("usecase.resealShares: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	for _, sh := range shares {
+		if sh.Service != service {
+			continue
+		}
+		if err := uc.reseal(sh, pair); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reseal stores the entry sealed for the recipient of the share.
+func (uc *UseCase) reseal(sh entity.Share, pair entity.Pair) error {
+	sealed, err := uc.sealShare(sh, pair)
+	if err == nil {
+		sh.Sealed = sealed
+		err = uc.storage.SaveShare(sh)
+	}
+	if err != nil {
+		err = fmt.Errorf("usecase.reseal: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+	return nil
+}
+
+// recipientGCM returns AES-GCM with the key of the recipient.
+func (uc *UseCase) recipientGCM(recipient int64) (cipher.AEAD, error) {
+	key := make([]byte, 32)
+	info := []byte("share:" + strconv.FormatInt(recipient, 10))
+	if _, err := io.ReadFull(hkdf.New(sha256.New, uc.shareKey, nil, info), key); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func shareAD(sh entity.Share) []byte {
+	return []byte(fmt.Sprintf("%d:%d:%s", sh.Owner, sh.Recipient, sh.Service))
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
 	"database/sql"
@@ -14,6 +15,8 @@ import (
 	"password-keeper/internal/entity"
 	"password-keeper/internal/storage"
 	"strings"
+	"sync"
+	"time"
 )
 
 // UseCase is the main struct for the application logic.
@@ -21,10 +24,40 @@ type UseCase struct {
 	storage *storage.Storage
 	cipher  cipher.Block
 	logger  *zap.Logger
+
+	lockMu sync.Mutex
+	now    func() time.Time
+
+	access AccessPolicy
+	// breaches is nil when the breach check is off.
+	breaches BreachChecker
+	// auditRetention how long the audit log is kept, zero keeps it forever.
+	auditRetention time.Duration
+	// auditKey seals the audit log chain, it is derived from the encryption key.
+	auditKey []byte
+	// pinKey hashes the duress PINs, it is derived from the encryption key.
+	pinKey []byte
+	// shareKey is the encryption key, the keys of the share recipients are derived from it.
+	shareKey []byte
 }
 
 const defaultLanguage = "en"
 
+// supportedLanguages languages the bot has translations for.
+var supportedLanguages = map[string]struct{}{
+	"en": {},
+	"ru": {},
+}
+
+// closestLanguages maps languages without translations to
+// the supported language their speakers most likely understand.
+var closestLanguages = map[string]string{
+	"be": "ru",
+	"kk": "ru",
+	"ky": "ru",
+	"tg": "ru",
+}
+
 // New creates a new UseCase.
 func New(storage *storage.Storage, key string, logger *zap.Logger) (*UseCase, error) {
 	cipher, err := aes.NewCipher([]byte(key))
@@ -32,15 +65,63 @@ func New(storage *storage.Storage, key string, logger *zap.Logger) (*UseCase, er
 		return nil, err
 	}
 
+	auditKey := hmac.New(sha256.New, []byte(key))
+	auditKey.Write([]byte("audit"))
+	pinKey := hmac.New(sha256.New, []byte(key))
+	pinKey.Write([]byte("duress"))
+
 	return &UseCase{
-		storage: storage,
-		cipher:  cipher,
-		logger:  logger,
+		storage:  storage,
+		cipher:   cipher,
+		logger:   logger,
+		now:      time.Now,
+		auditKey: auditKey.Sum(nil),
+		pinKey:   pinKey.Sum(nil),
+		shareKey: []byte(key),
 	}, nil
 }
 
 // Get returns the pair from the storage.
-func (uc *UseCase) Get(chatID int64, service string) (entity.Pair, error) {
+// Entries shared with the chat are returned when it has no entry with the name.
+// Lookups of missing services are counted, and too many of them lock the chat out.
+// Every call is recorded in the audit log like Save, Delete and SetLang.
+func (uc *UseCase) Get(chatID int64, service string) (pair entity.Pair, err error) {
+	defer func() {
+		if err = uc.record(chatID, entity.ActionGet, service, err); err != nil {
+			pair = entity.Pair{}
+		}
+	}()
+
+	if err := uc.checkLock(chatID); err != nil {
+		return entity.Pair{}, err
+	}
+
+	pair, err = uc.lookup(chatID, service)
+	if errors.Is(err, storage.ErrNotFound) {
+		pair, err = uc.getShared(chatID, service)
+	}
+
+	if errors.Is(err, storage.ErrNotFound) {
+		uc.registerFailure(chatID)
+	}
+	return pair, err
+}
+
+// get returns the pair of the owner, failed lookups are counted for the user.
+func (uc *UseCase) get(userID, owner int64, service string) (entity.Pair, error) {
+	if err := uc.checkLock(userID); err != nil {
+		return entity.Pair{}, err
+	}
+
+	pair, err := uc.lookup(owner, service)
+	if errors.Is(err, storage.ErrNotFound) {
+		uc.registerFailure(userID)
+	}
+	return pair, err
+}
+
+// lookup returns the decrypted pair of the owner.
+func (uc *UseCase) lookup(owner int64, service string) (entity.Pair, error) {
 	service, err := uc.Hash(service)
 	if err != nil {
 		err = fmt.Errorf("usecase.Hash: %w", err)
@@ -48,12 +129,17 @@ func (uc *UseCase) Get(chatID int64, service string) (entity.Pair, error) {
 		return entity.Pair{}, err
 	}
 
-	pair, err := uc.storage.Get(chatID, service)
+	pair, err := uc.storage.Get(owner, service)
 	if err != nil {
 		err = fmt.Errorf("usecase.Get: %w", err)
 		uc.logger.Warn(err.Error())
 		return entity.Pair{}, err
 	}
+	// the sweeper may not have deleted it yet
+	if expired(pair, uc.now()) {
+		return entity.Pair{}, storage.ErrNotFound
+	}
+
 	pair.Login, err = uc.Decrypt(pair.Login)
 	if err != nil {
 		err = fmt.Errorf("usecase.Decrypt: %w", err)
@@ -68,19 +154,59 @@ func (uc *UseCase) Get(chatID int64, service string) (entity.Pair, error) {
 		return entity.Pair{}, err
 	}
 
-	return pair, nil
+	// the caller knows the name, only Entries needs it.
+	return entity.Pair{Login: pair.Login, Password: pair.Password, ExpiresAt: pair.ExpiresAt}, nil
 }
 
 // Save saves the pair to the storage.
-func (uc *UseCase) Save(chatID int64, service, login, password string) (err error) {
-	login, err = uc.Encrypt(login)
+// When the chat has no entry with the name but the entry is shared with it
+// and not read-only, the entry of the owner is updated.
+func (uc *UseCase) Save(chatID int64, service, login, password string) error {
+	return uc.SaveUntil(chatID, service, login, password, time.Time{})
+}
+
+// SaveUntil is Save of an entry deleted at expiresAt, zero means never.
+// Saving the entry again replaces its expiry as well.
+func (uc *UseCase) SaveUntil(chatID int64, service, login, password string, expiresAt time.Time) (err error) {
+	defer func() { err = uc.record(chatID, entity.ActionSave, service, err) }()
+
+	if !expiresAt.IsZero() && expiresAt.Sub(uc.now()) < MinExpiry {
+		return ErrExpiry
+	}
+
+	owner := chatID
+	sh, err := uc.writableShare(chatID, service)
+	if err == nil {
+		owner = sh.Owner
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		return err
+	}
+
+	return uc.saveUntil(owner, service, login, password, expiresAt)
+}
+
+func (uc *UseCase) save(owner int64, service, login, password string) error {
+	return uc.saveUntil(owner, service, login, password, time.Time{})
+}
+
+// saveUntil saves the entry of the owner. The owner is warned only about the entries
+// saved for longer than ExpiryWarning, the others are marked warned right away.
+func (uc *UseCase) saveUntil(owner int64, service, login, password string, expiresAt time.Time) (err error) {
+	name, err := uc.Encrypt(service)
+	if err != nil {
+		err = fmt.Errorf("usecase.Encrypt: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	encLogin, err := uc.Encrypt(login)
 	if err != nil {
 		err = fmt.Errorf("usecase.Encrypt: %w", err)
 		uc.logger.Warn(err.Error())
 		return err
 	}
 
-	password, err = uc.Encrypt(password)
+	encPassword, err := uc.Encrypt(password)
 	if err != nil {
 		err = fmt.Errorf("usecase.Encrypt: %w", err)
 		uc.logger.Warn(err.Error())
@@ -94,24 +220,74 @@ func (uc *UseCase) Save(chatID int64, service, login, password string) (err erro
 		return err
 	}
 
-	if err := uc.storage.Save(chatID, service, entity.Pair{Login: login, Password: password}); err != nil {
+	pair := entity.Pair{
+		Name:         name,
+		Login:        encLogin,
+		Password:     encPassword,
+		UpdatedAt:    uc.now(),
+		CreatedAt:    uc.now(),
+		ExpiresAt:    expiresAt,
+		ExpiryWarned: !expiresAt.IsZero() && expiresAt.Sub(uc.now()) <= ExpiryWarning,
+	}
+	if err := uc.storage.Save(owner, service, pair); err != nil {
 		err = fmt.Errorf("usecase.Save: %w", err)
 		uc.logger.Warn(err.Error())
 		return err
 	}
 
-	return nil
+	return uc.resealShares(owner, service, entity.Pair{Login: login, Password: password, ExpiresAt: expiresAt})
 }
 
-// Delete deletes the pair from the storage.
+// Delete deletes the pair from the storage together with its shares.
+// An entry shared with the chat is removed from the chat only.
+// Like Get, it reveals whether the service exists, so it's protected the same way.
 func (uc *UseCase) Delete(chatID int64, service string) (err error) {
-	service, err = uc.Hash(service)
+	defer func() { err = uc.record(chatID, entity.ActionDelete, service, err) }()
+
+	if err := uc.checkLock(chatID); err != nil {
+		return err
+	}
+
+	err = uc.remove(chatID, service)
+	if errors.Is(err, storage.ErrNotFound) {
+		err = uc.dropShare(chatID, service)
+	}
+
+	if errors.Is(err, storage.ErrNotFound) {
+		uc.registerFailure(chatID)
+	}
+	return err
+}
+
+// delete deletes the pair of the owner, failed lookups are counted for the user.
+func (uc *UseCase) delete(userID, owner int64, service string) error {
+	if err := uc.checkLock(userID); err != nil {
+		return err
+	}
+
+	err := uc.remove(owner, service)
+	if errors.Is(err, storage.ErrNotFound) {
+		uc.registerFailure(userID)
+	}
+	return err
+}
+
+// remove deletes the pair of the owner and its shares.
+func (uc *UseCase) remove(owner int64, service string) error {
+	service, err := uc.Hash(service)
 	if err != nil {
 		err = fmt.Errorf("usecase.Hash: %w", err)
 		uc.logger.Warn(err.Error())
 		return err
 	}
-	if err := uc.storage.Delete(chatID, service); err != nil {
+
+	if err := uc.storage.Delete(owner, service); err != nil {
+		err = fmt.Errorf("usecase.Delete: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	if err := uc.storage.DeleteShares(owner, service); err != nil {
 		err = fmt.Errorf("usecase.Delete: %w", err)
 		uc.logger.Warn(err.Error())
 		return err
@@ -120,27 +296,70 @@ func (uc *UseCase) Delete(chatID int64, service string) (err error) {
 }
 
 // GetLang returns the language of the user.
+// Chats without a stored language get the default one.
 func (uc *UseCase) GetLang(chatID int64) string {
 	l, err := uc.storage.GetLang(chatID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			uc.SetLang(chatID, defaultLanguage)
-			return defaultLanguage
+		if !errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("usecase.GetLang: %w", err)
+			uc.logger.Warn(err.Error())
 		}
-		err = fmt.Errorf("usecase.GetLang: %w", err)
-		uc.logger.Warn(err.Error())
 		return defaultLanguage
 	}
-	return l
+	return l.Code
 }
 
-// SetLang sets the language of the user.
+// SetLang sets the language chosen by the user.
 func (uc *UseCase) SetLang(chatID int64, lang string) {
+	err := uc.setLang(chatID, entity.Language{Code: lang})
+	uc.record(chatID, entity.ActionLang, "", err)
+}
+
+// DetectLang picks the closest supported language for the Telegram
+// language code of the user on the first contact of the chat.
+// A stored language is never overridden, so members of a group
+// with other languages don't switch the language of the group.
+func (uc *UseCase) DetectLang(chatID int64, languageCode string) {
+	_, err := uc.storage.GetLang(chatID)
+	if err == nil {
+		return
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("usecase.DetectLang: %w", err)
+		uc.logger.Warn(err.Error())
+		return
+	}
+
+	uc.setLang(chatID, entity.Language{Code: MatchLang(languageCode), Auto: true})
+}
+
+func (uc *UseCase) setLang(chatID int64, lang entity.Language) error {
 	err := uc.storage.SetLang(chatID, lang)
 	if err != nil {
 		err = fmt.Errorf("usecase.SetLang: %w", err)
 		uc.logger.Warn(err.Error())
 	}
+	return err
+}
+
+// MatchLang returns the supported language closest to the IETF
+// language tag, e.g. "ru-RU" -> "ru".
+func MatchLang(tag string) string {
+	base := strings.ToLower(tag)
+	if i := strings.IndexAny(base, "-_"); i != -1 {
+		base = base[:i]
+	}
+
+	if _, ok := supportedLanguages[base]; ok {
+		return base
+	}
+
+	if lang, ok := closestLanguages[base]; ok {
+		return lang
+	}
+
+	return defaultLanguage
 }
 
 // Encrypt encrypts the text.
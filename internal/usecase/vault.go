@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"password-keeper/internal/entity"
+	"password-keeper/internal/storage"
+)
+
+// Errors of the team vaults.
+var (
+	// ErrVaultExists is returned when the group already has a vault or the name is taken.
+	ErrVaultExists = errors.New("vault already exists")
+	// ErrNoVault is returned when the vault doesn't exist or the user is not its member,
+	// the two cases are not distinguished, so vault names can't be probed.
+	ErrNoVault = errors.New("vault not found")
+	// ErrVaultRole is returned when the role of the member is too low for the action.
+	ErrVaultRole = errors.New("not enough rights in the vault")
+	// ErrWrongRole is returned for unknown roles.
+	ErrWrongRole = errors.New("wrong vault role")
+	// ErrLastOwner is returned on attempt to leave the vault without an owner.
+	ErrLastOwner = errors.New("the vault must have an owner")
+	// ErrVaultMember is returned on attempt to add a member of the vault again.
+	ErrVaultMember = errors.New("already a member of the vault")
+)
+
+// vaultRoles ranks of the vault roles, each role can do everything the lower ones can.
+var vaultRoles = map[string]int{
+	entity.VaultViewer: 1,
+	entity.VaultEditor: 2,
+	entity.VaultOwner:  3,
+}
+
+// CreateVault creates the vault of the group chat, the user becomes its owner.
+func (uc *UseCase) CreateVault(chatID, userID int64, name string) error {
+	if _, err := uc.storage.GetVault(chatID); err == nil {
+		return ErrVaultExists
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		err = fmt.Errorf("usecase.CreateVault: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	if _, err := uc.storage.GetVaultByName(name); err == nil {
+		return ErrVaultExists
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		err = fmt.Errorf("usecase.CreateVault: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	if err := uc.storage.CreateVault(entity.Vault{ID: chatID, Name: name, CreatedAt: uc.now()}); err != nil {
+		err = fmt.Errorf("usecase.CreateVault: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	return uc.saveVaultMember(entity.VaultMember{VaultID: chatID, UserID: userID, Role: entity.VaultOwner})
+}
+
+// Vault returns the vault of the group chat.
+func (uc *UseCase) Vault(chatID int64) (entity.Vault, error) {
+	v, err := uc.storage.GetVault(chatID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return entity.Vault{}, ErrNoVault
+		}
+		err = fmt.Errorf("usecase.Vault: %w", err)
+		uc.logger.Warn(err.Error())
+		return entity.Vault{}, err
+	}
+	return v, nil
+}
+
+// FindVault returns the vault with the name if the user is its member.
+func (uc *UseCase) FindVault(userID int64, name string) (entity.Vault, error) {
+	v, err := uc.storage.GetVaultByName(name)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return entity.Vault{}, ErrNoVault
+		}
+		err = fmt.Errorf("usecase.FindVault: %w", err)
+		uc.logger.Warn(err.Error())
+		return entity.Vault{}, err
+	}
+
+	if _, err := uc.vaultMember(v.ID, userID, entity.VaultViewer); err != nil {
+		return entity.Vault{}, err
+	}
+
+	return v, nil
+}
+
+// UserVaults returns the vaults the user is a member of.
+func (uc *UseCase) UserVaults(userID int64) ([]entity.Vault, error) {
+	vaults, err := uc.storage.GetUserVaults(userID)
+	if err != nil {
+		err = fmt.Errorf("usecase.UserVaults: %w", err)
+		uc.logger.Warn(err.Error())
+		return nil, err
+	}
+	return vaults, nil
+}
+
+// AddVaultMember adds the user to the vault with the role, only owners can do it.
+// Members of the group are not added on their own, so they don't see the secrets unless an owner allows it.
+func (uc *UseCase) AddVaultMember(chatID, ownerID, userID int64, role string) error {
+	if _, ok := vaultRoles[role]; !ok {
+		return ErrWrongRole
+	}
+
+	if _, err := uc.vaultMember(chatID, ownerID, entity.VaultOwner); err != nil {
+		return err
+	}
+
+	_, err := uc.storage.GetVaultMember(chatID, userID)
+	switch {
+	case err == nil:
+		return ErrVaultMember
+	case !errors.Is(err, storage.ErrNotFound):
+		err = fmt.Errorf("usecase.AddVaultMember: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	return uc.saveVaultMember(entity.VaultMember{VaultID: chatID, UserID: userID, Role: role})
+}
+
+// SetVaultRole changes the role of the member, only owners can do it.
+func (uc *UseCase) SetVaultRole(chatID, ownerID, userID int64, role string) error {
+	if _, ok := vaultRoles[role]; !ok {
+		return ErrWrongRole
+	}
+
+	if _, err := uc.vaultMember(chatID, ownerID, entity.VaultOwner); err != nil {
+		return err
+	}
+
+	m, err := uc.vaultMember(chatID, userID, entity.VaultViewer)
+	if err != nil {
+		return err
+	}
+
+	if m.Role == entity.VaultOwner && role != entity.VaultOwner {
+		if err := uc.checkOtherOwners(chatID, userID); err != nil {
+			return err
+		}
+	}
+
+	m.Role = role
+	return uc.saveVaultMember(m)
+}
+
+// RemoveVaultMember removes the user from the vault.
+// Owners can remove anyone, other members can only leave.
+func (uc *UseCase) RemoveVaultMember(chatID, actorID, userID int64) error {
+	minRole := entity.VaultOwner
+	if actorID == userID {
+		minRole = entity.VaultViewer
+	}
+
+	if _, err := uc.vaultMember(chatID, actorID, minRole); err != nil {
+		return err
+	}
+
+	m, err := uc.vaultMember(chatID, userID, entity.VaultViewer)
+	if err != nil {
+		return err
+	}
+
+	if m.Role == entity.VaultOwner {
+		if err := uc.checkOtherOwners(chatID, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := uc.storage.DeleteVaultMember(chatID, userID); err != nil {
+		err = fmt.Errorf("usecase.RemoveVaultMember: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// VaultMembers returns the members of the vault to its member.
+func (uc *UseCase) VaultMembers(chatID, userID int64) ([]entity.VaultMember, error) {
+	if _, err := uc.vaultMember(chatID, userID, entity.VaultViewer); err != nil {
+		return nil, err
+	}
+
+	members, err := uc.storage.GetVaultMembers(chatID)
+	if err != nil {
+		err = fmt.Errorf("usecase.VaultMembers: %w", err)
+		uc.logger.Warn(err.Error())
+		return nil, err
+	}
+	return members, nil
+}
+
+// VaultGet returns the pair from the vault.
+// Failed lookups are counted for the user, not for the vault,
+// so one member can't lock the others out. The calls are recorded in the audit log of the user.
+func (uc *UseCase) VaultGet(vaultID, userID int64, service string) (pair entity.Pair, err error) {
+	defer func() {
+		if err = uc.record(userID, entity.ActionGet, service, err); err != nil {
+			pair = entity.Pair{}
+		}
+	}()
+
+	if _, err := uc.vaultMember(vaultID, userID, entity.VaultViewer); err != nil {
+		return entity.Pair{}, err
+	}
+
+	return uc.get(userID, vaultID, service)
+}
+
+// VaultSave saves the pair to the vault, viewers can't do it.
+func (uc *UseCase) VaultSave(vaultID, userID int64, service, login, password string) (err error) {
+	defer func() { err = uc.record(userID, entity.ActionSave, service, err) }()
+
+	if _, err := uc.vaultMember(vaultID, userID, entity.VaultEditor); err != nil {
+		return err
+	}
+
+	return uc.save(vaultID, service, login, password)
+}
+
+// VaultDelete deletes the pair from the vault, viewers can't do it.
+func (uc *UseCase) VaultDelete(vaultID, userID int64, service string) (err error) {
+	defer func() { err = uc.record(userID, entity.ActionDelete, service, err) }()
+
+	if _, err := uc.vaultMember(vaultID, userID, entity.VaultEditor); err != nil {
+		return err
+	}
+
+	return uc.delete(userID, vaultID, service)
+}
+
+// vaultMember returns the member of the vault if their role is at least minRole.
+func (uc *UseCase) vaultMember(vaultID, userID int64, minRole string) (entity.VaultMember, error) {
+	m, err := uc.storage.GetVaultMember(vaultID, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return entity.VaultMember{}, ErrNoVault
+		}
+		err = fmt.Errorf("usecase.vaultMember: %w", err)
+		uc.logger.Warn(err.Error())
+		return entity.VaultMember{}, err
+	}
+
+	if vaultRoles[m.Role] < vaultRoles[minRole] {
+		return entity.VaultMember{}, ErrVaultRole
+	}
+
+	return m, nil
+}
+
+// checkOtherOwners returns ErrLastOwner if the user is the only owner of the vault.
+func (uc *UseCase) checkOtherOwners(vaultID, userID int64) error {
+	members, err := uc.storage.GetVaultMembers(vaultID)
+	if err != nil {
+		err = fmt.Errorf("usecase.checkOtherOwners: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+
+	for _, m := range members {
+		if m.UserID != userID && m.Role == entity.VaultOwner {
+			return nil
+		}
+	}
+
+	return ErrLastOwner
+}
+
+func (uc *UseCase) saveVaultMember(m entity.VaultMember) error {
+	if err := uc.storage.SaveVaultMember(m); err != nil {
+		err = fmt.Errorf("usecase.saveVaultMember: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+	return nil
+}
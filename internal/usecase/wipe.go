@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"password-keeper/internal/entity"
+	"password-keeper/internal/storage"
+	"strconv"
+)
+
+// MinDuressPINLen the shortest duress PIN.
+const MinDuressPINLen = 4
+
+// ErrDuressPIN is returned for duress PINs shorter than MinDuressPINLen.
+var ErrDuressPIN = errors.New("duress PIN is too short")
+
+// Wipe deletes the entries, files, settings, shares, one-time links and the audit log records of the chat.
+// The wipe is recorded after them, so it is the only record left.
+func (uc *UseCase) Wipe(chatID int64) (err error) {
+	defer func() { err = uc.record(chatID, entity.ActionWipe, "", err) }()
+
+	return uc.wipe(chatID)
+}
+
+// wipe deletes everything of the chat like Wipe without recording it. A forget record
+// takes the place of the audit log records and the delete is retried like in ForgetMe.
+func (uc *UseCase) wipe(chatID int64) error {
+	var err error
+	for i := 0; i < appendAttempts; i++ {
+		if err = uc.storage.Wipe(chatID, uc.forgetAudit); err == nil {
+			return nil
+		}
+	}
+
+	err = fmt.Errorf("usecase.Wipe: %w", err)
+	uc.logger.Warn(err.Error())
+	return err
+}
+
+// SetDuressPIN sets the PIN wiping the chat silently, empty PIN removes it.
+// Only the hash of the PIN keyed with the encryption key is stored.
+func (uc *UseCase) SetDuressPIN(chatID int64, pin string) error {
+	var err error
+	switch {
+	case pin == "":
+		err = uc.storage.DeleteDuressPIN(chatID)
+	case len([]rune(pin)) < MinDuressPINLen:
+		return ErrDuressPIN
+	default:
+		err = uc.storage.SetDuressPIN(chatID, uc.hashPIN(chatID, pin))
+	}
+
+	if err != nil {
+		err = fmt.Errorf("usecase.SetDuressPIN: %w", err)
+		uc.logger.Warn(err.Error())
+		return err
+	}
+	return nil
+}
+
+// HasDuressPIN returns true when the chat has set the duress PIN.
+func (uc *UseCase) HasDuressPIN(chatID int64) (bool, error) {
+	_, err := uc.storage.GetDuressPIN(chatID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		err = fmt.Errorf("usecase.HasDuressPIN: %w", err)
+		uc.logger.Warn(err.Error())
+		return false, err
+	}
+	return true, nil
+}
+
+// Duress wipes the chat when the text is its duress PIN and returns true.
+// The wipe is not recorded, so the chat looks like it never had any entries.
+func (uc *UseCase) Duress(chatID int64, text string) (bool, error) {
+	pin, err := uc.storage.GetDuressPIN(chatID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		err = fmt.Errorf("usecase.Duress: %w", err)
+		uc.logger.Warn(err.Error())
+		return false, err
+	}
+
+	if !hmac.Equal([]byte(pin), []byte(uc.hashPIN(chatID, text))) {
+		return false, nil
+	}
+	return true, uc.wipe(chatID)
+}
+
+// hashPIN returns the hash of the PIN of the chat keyed with the PIN key.
+func (uc *UseCase) hashPIN(chatID int64, pin string) string {
+	mac := hmac.New(sha256.New, uc.pinKey)
+	mac.Write([]byte(strconv.FormatInt(chatID, 10)))
+	mac.Write([]byte{0})
+	mac.Write([]byte(pin))
+	return base64.RawStdEncoding.EncodeToString(mac.Sum(nil))
+}
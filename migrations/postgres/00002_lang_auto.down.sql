@@ -0,0 +1 @@
+ALTER TABLE chats DROP COLUMN chat_lang_auto;
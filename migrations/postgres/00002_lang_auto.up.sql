@@ -0,0 +1 @@
+ALTER TABLE chats ADD COLUMN chat_lang_auto BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,7 @@
+CREATE TABLE lockouts (
+    chat_id BIGINT PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    window_start BIGINT NOT NULL DEFAULT 0,
+    level INTEGER NOT NULL DEFAULT 0,
+    locked_until BIGINT NOT NULL DEFAULT 0
+);
@@ -0,0 +1,4 @@
+DELETE FROM services a USING services b WHERE a.owner = b.owner AND a.service > b.service;
+ALTER TABLE services DROP CONSTRAINT services_pkey;
+ALTER TABLE services ADD PRIMARY KEY (owner);
+ALTER TABLE services ALTER COLUMN service DROP NOT NULL;
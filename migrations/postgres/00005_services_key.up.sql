@@ -0,0 +1,6 @@
+ALTER TABLE chats ALTER COLUMN chat_id TYPE BIGINT;
+ALTER TABLE services ALTER COLUMN owner TYPE BIGINT;
+DELETE FROM services WHERE service IS NULL;
+ALTER TABLE services ALTER COLUMN service SET NOT NULL;
+ALTER TABLE services DROP CONSTRAINT services_pkey;
+ALTER TABLE services ADD PRIMARY KEY (owner, service);
@@ -0,0 +1,11 @@
+CREATE TABLE vaults (
+    vault_id BIGINT PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    created_at BIGINT NOT NULL DEFAULT 0
+);
+CREATE TABLE vault_members (
+    vault_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'viewer',
+    PRIMARY KEY (vault_id, user_id)
+);
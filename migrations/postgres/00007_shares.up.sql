@@ -0,0 +1,23 @@
+CREATE TABLE users (
+    user_id BIGINT PRIMARY KEY,
+    username TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX users_username ON users (username);
+CREATE TABLE shares (
+    owner BIGINT NOT NULL,
+    service TEXT NOT NULL,
+    recipient BIGINT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    read_only BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at BIGINT NOT NULL DEFAULT 0,
+    created_at BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (owner, service, recipient)
+);
+CREATE INDEX shares_recipient ON shares (recipient, service);
+CREATE TABLE share_accesses (
+    owner BIGINT NOT NULL,
+    service TEXT NOT NULL,
+    recipient BIGINT NOT NULL,
+    accessed_at BIGINT NOT NULL
+);
+CREATE INDEX share_accesses_share ON share_accesses (owner, service, recipient);
@@ -0,0 +1,8 @@
+CREATE TABLE onetime_secrets (
+    id TEXT PRIMARY KEY,
+    owner BIGINT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    secret TEXT NOT NULL,
+    expires_at BIGINT NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT 0
+);
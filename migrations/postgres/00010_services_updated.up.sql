@@ -0,0 +1 @@
+ALTER TABLE services ADD COLUMN updated_at BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+ALTER TABLE services ADD COLUMN created_at BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE services ADD COLUMN rotate_days INTEGER NOT NULL DEFAULT 0;
+CREATE TABLE rotations (
+    chat_id BIGINT PRIMARY KEY,
+    period_days INTEGER NOT NULL DEFAULT 0,
+    remind_at BIGINT NOT NULL DEFAULT 0
+);
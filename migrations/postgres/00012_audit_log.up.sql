@@ -0,0 +1,11 @@
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    service TEXT NOT NULL DEFAULT '',
+    outcome VARCHAR(20) NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT 0
+);
+CREATE INDEX audit_log_chat ON audit_log (chat_id, id);
+CREATE INDEX audit_log_created ON audit_log (created_at);
+CREATE RULE audit_log_append_only AS ON UPDATE TO audit_log DO INSTEAD NOTHING;
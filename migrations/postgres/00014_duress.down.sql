@@ -0,0 +1 @@
+DROP TABLE duress_pins;
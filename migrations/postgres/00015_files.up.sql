@@ -0,0 +1,10 @@
+CREATE TABLE files (
+    owner BIGINT NOT NULL,
+    service TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    file_name TEXT NOT NULL DEFAULT '',
+    photo BOOLEAN NOT NULL DEFAULT FALSE,
+    data BYTEA NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (owner, service)
+);
@@ -0,0 +1,3 @@
+DROP INDEX services_expires_at;
+ALTER TABLE services DROP COLUMN expiry_warned;
+ALTER TABLE services DROP COLUMN expires_at;
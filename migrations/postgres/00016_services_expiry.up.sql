@@ -0,0 +1,3 @@
+ALTER TABLE services ADD COLUMN expires_at BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE services ADD COLUMN expiry_warned BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX services_expires_at ON services (expires_at) WHERE expires_at > 0;
@@ -0,0 +1 @@
+DROP TABLE display_modes;
@@ -0,0 +1,4 @@
+CREATE TABLE display_modes (
+    chat_id BIGINT PRIMARY KEY,
+    mode TEXT NOT NULL
+);
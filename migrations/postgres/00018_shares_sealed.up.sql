@@ -0,0 +1 @@
+ALTER TABLE shares ADD COLUMN sealed BYTEA;
@@ -0,0 +1,7 @@
+CREATE TABLE lockouts (
+    chat_id INTEGER PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    window_start INTEGER NOT NULL DEFAULT 0,
+    level INTEGER NOT NULL DEFAULT 0,
+    locked_until INTEGER NOT NULL DEFAULT 0
+);
@@ -0,0 +1,7 @@
+CREATE TABLE members (
+    user_id INTEGER PRIMARY KEY,
+    username TEXT NOT NULL DEFAULT '',
+    role VARCHAR(20) NOT NULL DEFAULT 'user',
+    revoked BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at INTEGER NOT NULL DEFAULT 0
+);
@@ -0,0 +1,10 @@
+CREATE TABLE services_old (
+    owner INTEGER PRIMARY KEY,
+    service TEXT,
+    login TEXT,
+    password TEXT
+);
+INSERT OR IGNORE INTO services_old (owner, service, login, password)
+    SELECT owner, service, login, password FROM services;
+DROP TABLE services;
+ALTER TABLE services_old RENAME TO services;
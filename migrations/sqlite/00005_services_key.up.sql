@@ -0,0 +1,11 @@
+CREATE TABLE services_new (
+    owner INTEGER NOT NULL,
+    service TEXT NOT NULL,
+    login TEXT,
+    password TEXT,
+    PRIMARY KEY (owner, service)
+);
+INSERT INTO services_new (owner, service, login, password)
+    SELECT owner, service, login, password FROM services WHERE service IS NOT NULL;
+DROP TABLE services;
+ALTER TABLE services_new RENAME TO services;
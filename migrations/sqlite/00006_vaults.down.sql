@@ -0,0 +1,2 @@
+DROP TABLE vault_members;
+DROP TABLE vaults;
@@ -0,0 +1,11 @@
+CREATE TABLE vaults (
+    vault_id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    created_at INTEGER NOT NULL DEFAULT 0
+);
+CREATE TABLE vault_members (
+    vault_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'viewer',
+    PRIMARY KEY (vault_id, user_id)
+);
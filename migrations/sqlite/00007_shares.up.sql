@@ -0,0 +1,23 @@
+CREATE TABLE users (
+    user_id INTEGER PRIMARY KEY,
+    username TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX users_username ON users (username);
+CREATE TABLE shares (
+    owner INTEGER NOT NULL,
+    service TEXT NOT NULL,
+    recipient INTEGER NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    read_only BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at INTEGER NOT NULL DEFAULT 0,
+    created_at INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (owner, service, recipient)
+);
+CREATE INDEX shares_recipient ON shares (recipient, service);
+CREATE TABLE share_accesses (
+    owner INTEGER NOT NULL,
+    service TEXT NOT NULL,
+    recipient INTEGER NOT NULL,
+    accessed_at INTEGER NOT NULL
+);
+CREATE INDEX share_accesses_share ON share_accesses (owner, service, recipient);
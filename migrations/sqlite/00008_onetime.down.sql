@@ -0,0 +1 @@
+DROP TABLE onetime_secrets;
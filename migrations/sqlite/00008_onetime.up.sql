@@ -0,0 +1,8 @@
+CREATE TABLE onetime_secrets (
+    id TEXT PRIMARY KEY,
+    owner INTEGER NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    secret TEXT NOT NULL,
+    expires_at INTEGER NOT NULL,
+    created_at INTEGER NOT NULL DEFAULT 0
+);
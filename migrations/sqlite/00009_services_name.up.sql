@@ -0,0 +1 @@
+ALTER TABLE services ADD COLUMN name TEXT NOT NULL DEFAULT '';
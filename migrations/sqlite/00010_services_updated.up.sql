@@ -0,0 +1 @@
+ALTER TABLE services ADD COLUMN updated_at INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP TABLE rotations;
+ALTER TABLE services DROP COLUMN rotate_days;
+ALTER TABLE services DROP COLUMN created_at;
@@ -0,0 +1,14 @@
+CREATE TABLE audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    chat_id INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    service TEXT NOT NULL DEFAULT '',
+    outcome VARCHAR(20) NOT NULL,
+    created_at INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX audit_log_chat ON audit_log (chat_id, id);
+CREATE INDEX audit_log_created ON audit_log (created_at);
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit log is append-only');
+END;
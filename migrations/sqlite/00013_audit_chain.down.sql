@@ -0,0 +1,4 @@
+DROP INDEX audit_log_chain;
+ALTER TABLE audit_log DROP COLUMN hash;
+ALTER TABLE audit_log DROP COLUMN chat_prev_hash;
+ALTER TABLE audit_log DROP COLUMN prev_hash;
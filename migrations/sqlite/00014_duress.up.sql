@@ -0,0 +1,4 @@
+CREATE TABLE duress_pins (
+    chat_id INTEGER PRIMARY KEY,
+    pin TEXT NOT NULL
+);
@@ -0,0 +1,10 @@
+CREATE TABLE files (
+    owner INTEGER NOT NULL,
+    service TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    file_name TEXT NOT NULL DEFAULT '',
+    photo BOOLEAN NOT NULL DEFAULT FALSE,
+    data BLOB NOT NULL,
+    created_at INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (owner, service)
+);
@@ -0,0 +1,6 @@
+CREATE TABLE audit_anchors (
+    kind VARCHAR(20) PRIMARY KEY,
+    record_id INTEGER NOT NULL,
+    hash TEXT NOT NULL,
+    seal TEXT NOT NULL
+);
@@ -0,0 +1,6 @@
+CREATE TABLE audit_forgotten (
+    hash TEXT PRIMARY KEY,
+    prev_hash TEXT NOT NULL,
+    forget_hash TEXT NOT NULL,
+    seal TEXT NOT NULL
+);